@@ -96,17 +96,41 @@ type GatewayStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +operator-sdk:csv:customresourcedefinitions:displayName="Image"
 	Image string `json:"image,omitempty"`
+	// License contains the details of the Gateway license
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +operator-sdk:csv:customresourcedefinitions:displayName="License"
+	License LicenseStatus `json:"license,omitempty"`
 	// Management Pod is a Gateway with a special annotation is used as a selector for the
 	// management service and applying singleton resources
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +operator-sdk:csv:customresourcedefinitions:displayName="ManagementPod"
 	ManagementPod string `json:"managementPod,omitempty"`
+	// LeaderElection reports the current management pod leader and recent elections
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +operator-sdk:csv:customresourcedefinitions:displayName="LeaderElection"
+	LeaderElection LeaderElectionStatus `json:"leaderElection,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +operator-sdk:csv:customresourcedefinitions:displayName="RepositoryStatus"
 	RepositoryStatus []GatewayRepositoryStatus `json:"repositoryStatus,omitempty"`
+	// GatewayUpgrade reports the progress of the last Gateway version upgrade
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +operator-sdk:csv:customresourcedefinitions:displayName="GatewayUpgrade"
+	GatewayUpgrade GatewayUpgradeStatus `json:"gatewayUpgrade,omitempty"`
+	// BlueGreen reports the active colour of a blueGreen Gateway and the rollout to the idle colour
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +operator-sdk:csv:customresourcedefinitions:displayName="BlueGreen"
+	BlueGreen BlueGreenStatus `json:"blueGreen,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +operator-sdk:csv:customresourcedefinitions:displayName="PortalSyncStatus"
 	PortalSyncStatus PortalSyncStatus `json:"PortalSyncStatus,omitempty"`
+	// OtkSchema reports the Job that creates or upgrades the OTK database schema
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +operator-sdk:csv:customresourcedefinitions:displayName="OtkSchema"
+	OtkSchema OtkSchemaStatus `json:"otkSchema,omitempty"`
+	// OtkMaintenanceTasks reports the OTK database maintenance tasks that were last scheduled on the Gateway
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +operator-sdk:csv:customresourcedefinitions:displayName="OtkMaintenanceTasks"
+	OtkMaintenanceTasks OtkMaintenanceTasksStatus `json:"otkMaintenanceTasks,omitempty"`
 	// LastAppliedClusterProperties
 	LastAppliedClusterProperties []string `json:"lastAppliedClusterProperties,omitempty"`
 	// LastAppliedClusterProperties
@@ -130,6 +154,118 @@ type GatewayState struct {
 	StartTime string `json:"startTime,omitempty"`
 	// Repositories
 	RepositoryStatus map[string]string `json:"repositoryStatus,omitempty"`
+	// Unhealthy is set when Graphman requests to the Gateway pod have failed repeatedly
+	// the pod is skipped until a trial request succeeds
+	Unhealthy bool `json:"unhealthy,omitempty"`
+	// Reason is the last Graphman error for an unhealthy Gateway pod
+	Reason string `json:"reason,omitempty"`
+}
+
+// GatewayUpgradeStatus tracks an upgrade between Gateway major/minor versions
+type GatewayUpgradeStatus struct {
+	// Phase of the upgrade Preflight, Provisioning, Promoting, Completed or Aborted
+	Phase GatewayUpgradePhase `json:"phase,omitempty"`
+	// FromImage is the Gateway image before the upgrade
+	FromImage string `json:"fromImage,omitempty"`
+	// ToImage is the Gateway image that is being upgraded to
+	ToImage string `json:"toImage,omitempty"`
+	// Deployment is the temporary Deployment that runs ToImage while the Gateway Deployment is upgraded
+	Deployment string `json:"deployment,omitempty"`
+	// Revision is the pod-template-hash of the Gateway pods that the Service is pinned to during provisioning
+	Revision string `json:"revision,omitempty"`
+	// PreflightChecks that were run before the upgrade started
+	PreflightChecks []GatewayUpgradeCheck `json:"preflightChecks,omitempty"`
+	// Reason is set if the upgrade is waiting or can not continue
+	Reason      string `json:"reason,omitempty"`
+	StartedAt   string `json:"startedAt,omitempty"`
+	CompletedAt string `json:"completedAt,omitempty"`
+}
+
+// GatewayUpgradeCheck is the result of a single preflight check
+type GatewayUpgradeCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+type GatewayUpgradePhase string
+
+const (
+	// GatewayUpgradePhasePreflight runs the preflight checks, the Gateway Deployment is not changed
+	GatewayUpgradePhasePreflight GatewayUpgradePhase = "Preflight"
+	// GatewayUpgradePhaseProvisioning creates and configures the upgrade Deployment, the Service is pinned to the current Gateway pods
+	GatewayUpgradePhaseProvisioning GatewayUpgradePhase = "Provisioning"
+	// GatewayUpgradePhasePromoting moves the Service to the upgrade Deployment and rolls out the Gateway Deployment
+	GatewayUpgradePhasePromoting GatewayUpgradePhase = "Promoting"
+	// GatewayUpgradePhaseCompleted the Service is restored and the upgrade Deployment is removed
+	GatewayUpgradePhaseCompleted GatewayUpgradePhase = "Completed"
+	// GatewayUpgradePhaseAborted the upgrade Deployment is removed and the Gateway Deployment keeps FromImage
+	GatewayUpgradePhaseAborted GatewayUpgradePhase = "Aborted"
+)
+
+// BlueGreenStatus tracks the Deployments of a blueGreen Gateway
+type BlueGreenStatus struct {
+	// Active is the colour that the Service selects
+	Active BlueGreenColor `json:"active,omitempty"`
+	// Previous is the colour that is kept for rollback
+	Previous BlueGreenColor `json:"previous,omitempty"`
+	// Checksum of the Gateway Deployment that the active colour runs
+	Checksum string `json:"checksum,omitempty"`
+	// Pending is the checksum of the Gateway Deployment that is being rolled out to the idle colour
+	Pending string `json:"pending,omitempty"`
+	// SmokeTests are the results of the last smoke tests against the idle colour
+	SmokeTests []SmokeTestResult `json:"smokeTests,omitempty"`
+	// RolledBack is set when the Service has been switched back to the previous colour
+	RolledBack bool `json:"rolledBack,omitempty"`
+	// Reason is set if the rollout is waiting or can not continue
+	Reason string `json:"reason,omitempty"`
+	// LastSwitched is when the Service was last switched
+	LastSwitched string `json:"lastSwitched,omitempty"`
+	// Revision is the pod-template-hash of the Gateway Deployment that the Service selects until the first switch
+	Revision string `json:"revision,omitempty"`
+	// Rejected is the checksum of the Gateway Deployment that was rolled back, it is not rolled out again until the Gateway spec changes
+	Rejected string `json:"rejected,omitempty"`
+}
+
+// SmokeTestResult is the result of a single smoke test against a Gateway pod
+type SmokeTestResult struct {
+	Name    string `json:"name"`
+	Pod     string `json:"pod"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+type BlueGreenColor string
+
+const (
+	BlueGreenColorBlue  BlueGreenColor = "blue"
+	BlueGreenColorGreen BlueGreenColor = "green"
+)
+
+// OtkSchemaStatus is the result of the last OTK database schema migration
+type OtkSchemaStatus struct {
+	// Version is the OTK version that the schema was last created or upgraded with
+	Version string `json:"version,omitempty"`
+	// Job is the schema Job for the current OTK initContainerImage
+	Job string `json:"job,omitempty"`
+	// Result of the current Job Running, Succeeded or Failed
+	Result string `json:"result,omitempty"`
+	// Reason is set if the Job failed
+	Reason string `json:"reason,omitempty"`
+	// LastMigration is when the schema was last created or upgraded
+	LastMigration string `json:"lastMigration,omitempty"`
+}
+
+// OtkMaintenanceTasksStatus is the result of the last time the OTK database maintenance tasks were applied
+type OtkMaintenanceTasksStatus struct {
+	// Tasks that were applied with their schedule
+	Tasks []OtkMaintenanceTask `json:"tasks,omitempty"`
+	// Checksum of the applied tasks
+	Checksum string `json:"checksum,omitempty"`
+	// LastApplied is when the tasks were last applied
+	LastApplied string `json:"lastApplied,omitempty"`
+	// Reason is set if the tasks could not be applied
+	Reason string `json:"reason,omitempty"`
 }
 
 // GatewayRepositoryStatus tracks the status of which Graphman repositories have been applied to the Gateway Resource.
@@ -169,12 +305,30 @@ type GatewayRepositoryStatus struct {
 	Conditions []RepositoryCondition `json:"conditions,omitempty"`
 	// Directories
 	Directories []string `json:"directories,omitempty"`
+	// Drift is the list of entities that are missing or modified on one or more Gateway pods
+	Drift []DriftedEntity `json:"drift,omitempty"`
+	// LastDriftRemediation is the last time this repository reference was reapplied due to drift
+	LastDriftRemediation string `json:"lastDriftRemediation,omitempty"`
+}
+
+// DriftedEntity is an entity from a repository reference that does not match the Gateway
+type DriftedEntity struct {
+	// Type of entity i.e. services, clusterProperties
+	Type string `json:"type,omitempty"`
+	// Name or identifier of the entity
+	Name string `json:"name,omitempty"`
+	// Reason is missing or modified
+	Reason string `json:"reason,omitempty"`
+	// Pods where the entity has drifted
+	Pods []string `json:"pods,omitempty"`
 }
 
 type RepositoryCondition struct {
 	Time   string `json:"time,omitempty"`
 	Status string `json:"status,omitempty"`
 	Reason string `json:"reason,omitempty"`
+	// Pod is set for the verify results of a Gateway pod
+	Pod string `json:"pod,omitempty"`
 }
 
 // PortalSyncStatus tracks the status of which portals are synced with a gateway.
@@ -194,6 +348,47 @@ type License struct {
 	// SecretName is the Kubernetes Secret that contains the Gateway license
 	// There must be a key called license.xml
 	SecretName string `json:"secretName"`
+	// ExpiryWarningDays is the number of days before the license expires that
+	// the LicenseValid condition starts reporting a warning. Default is 30
+	ExpiryWarningDays int `json:"expiryWarningDays,omitempty"`
+}
+
+// LeaderElectionStatus
+type LeaderElectionStatus struct {
+	// Leader is the Gateway pod that currently holds the management Lease
+	Leader string `json:"leader,omitempty"`
+	// LeaseTransitions is the number of times the leader has changed
+	LeaseTransitions int32 `json:"leaseTransitions,omitempty"`
+	// History contains the most recent leader elections
+	History []LeaderElectionEvent `json:"history,omitempty"`
+}
+
+// LeaderElectionEvent
+type LeaderElectionEvent struct {
+	// Pod that was elected
+	Pod string `json:"pod,omitempty"`
+	// Time the pod was elected
+	Time string `json:"time,omitempty"`
+	// Reason the previous leader was replaced
+	Reason string `json:"reason,omitempty"`
+}
+
+// LicenseStatus contains the details of the Gateway license
+type LicenseStatus struct {
+	// Id of the license
+	Id string `json:"id,omitempty"`
+	// Product the license is issued for
+	Product string `json:"product,omitempty"`
+	// MajorVersion of the Gateway that the license is valid for
+	MajorVersion string `json:"majorVersion,omitempty"`
+	// Licensee is the name of the license holder
+	Licensee string `json:"licensee,omitempty"`
+	// FeatureSets that are enabled by the license
+	FeatureSets []string `json:"featureSets,omitempty"`
+	// Expires is empty if the license does not expire
+	Expires string `json:"expires,omitempty"`
+	// DaysToExpiry is the number of days until the license expires
+	DaysToExpiry int `json:"daysToExpiry,omitempty"`
 }
 
 // App contains Gateway specific deployment and application level configuration
@@ -230,8 +425,23 @@ type App struct {
 	// to be applied to one ephemeral gateway only.
 	// This works inconjunction with repository references and only supports dynamic repository references.
 	SingletonExtraction bool `json:"singletonExtraction,omitempty"`
+	// MaxConcurrentPodUpdates is the number of Gateway pods that bundles are applied to in parallel
+	// defaults to the operator MAX_CONCURRENT_POD_UPDATES environment variable or 5 if that is not set
+	MaxConcurrentPodUpdates int `json:"maxConcurrentPodUpdates,omitempty"`
+	// Upgrade orchestrates changes to the Gateway image between major/minor versions
+	Upgrade GatewayUpgrade `json:"upgrade,omitempty"`
+	// DeploymentStrategy rollingUpdate (default) or blueGreen. blueGreen runs ephemeral Gateways as two Deployments
+	// and only moves the Service to pods that are fully configured and have passed their smoke tests
+	// +kubebuilder:validation:Enum=rollingUpdate;blueGreen
+	DeploymentStrategy DeploymentStrategy `json:"deploymentStrategy,omitempty"`
+	// BlueGreen configures the blueGreen deploymentStrategy
+	BlueGreen BlueGreen `json:"blueGreen,omitempty"`
 	// BootstrapRepositoryReferences bootstraps repositoryReferences of type dynamic to avoid service unavailable at gateway ready.
 	RepositoryReferenceBootstrap RepositoryReferenceBootstrap `json:"repositoryReferenceBootstrap,omitempty"`
+	// DriftDetection periodically compares the entities in dynamic repository references with what is on the Gateway
+	DriftDetection DriftDetection `json:"driftDetection,omitempty"`
+	// Monitoring generates Prometheus Operator ServiceMonitor or PodMonitor resources for the Gateway
+	Monitoring Monitoring `json:"monitoring,omitempty"`
 	// RepositoryReferenceDelete enables repository delete when a repositoryReference is disabled or removed.
 	// To avoid potential conflicts the current gateway state is reset by reapplying all other repository references post
 	// delete
@@ -270,6 +480,64 @@ type App struct {
 	Otel                          Otel             `json:"otel,omitempty"`
 }
 
+// GatewayUpgrade replaces the rolling update of the Gateway Deployment when the image changes major or minor version.
+// Preflight checks are run, then a second Deployment with the new image is created and configured with all repository
+// references and external entities. The Service is moved to it once it is ready while the Gateway Deployment is upgraded
+// and the second Deployment is removed when the Gateway Deployment has been rolled out.
+// Patch version changes continue to use a rolling update.
+type GatewayUpgrade struct {
+	// Enabled orchestrates major/minor version changes. Database backed Gateways are not supported
+	Enabled bool `json:"enabled,omitempty"`
+	// Abort an upgrade that has not been promoted yet. The Gateway Deployment keeps the previous image
+	// until this is unset or the image is reverted
+	Abort bool `json:"abort,omitempty"`
+}
+
+type DeploymentStrategy string
+
+const (
+	DeploymentStrategyRollingUpdate DeploymentStrategy = "rollingUpdate"
+	DeploymentStrategyBlueGreen     DeploymentStrategy = "blueGreen"
+)
+
+// BlueGreen replaces the rolling update of the Gateway Deployment with two Deployments, <name>-blue and <name>-green.
+// Changes that roll the Gateway pods are applied to the idle colour, once its pods have the repository references and
+// external entities of the active colour and the smoke tests pass the Service is switched to it. The previous colour
+// is kept so that the Service can be switched back. Database backed Gateways are not supported.
+// Major/minor version changes are rolled out in the same way, the upgrade configuration does not apply.
+type BlueGreen struct {
+	// SmokeTests are sent to each pod of the idle colour before the Service is switched to it
+	SmokeTests []SmokeTest `json:"smokeTests,omitempty"`
+	// Rollback switches the Service back to the previous colour.
+	// Changes are not rolled out until this is unset
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// SmokeTest is an HTTP request that is sent to a Gateway pod
+type SmokeTest struct {
+	// Name of the test, defaults to the path
+	Name string `json:"name,omitempty"`
+	// Path of the request, defaults to /
+	Path string `json:"path,omitempty"`
+	// Method defaults to GET
+	Method string `json:"method,omitempty"`
+	// Headers that are added to the request
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body of the request
+	Body string `json:"body,omitempty"`
+	// Scheme http or https, defaults to https. Certificates are not verified
+	// +kubebuilder:validation:Enum=http;https
+	Scheme string `json:"scheme,omitempty"`
+	// Port on the Gateway pod, defaults to 8443
+	Port int32 `json:"port,omitempty"`
+	// ExpectedStatus defaults to 200
+	ExpectedStatus int `json:"expectedStatus,omitempty"`
+	// ExpectedBody is a regular expression that the response body must match
+	ExpectedBody string `json:"expectedBody,omitempty"`
+	// TimeoutSeconds defaults to 10
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
 // RepositoryReferenceBootstrap facilitates bootstrap of dynamic repo references and the desired source of truth.
 type RepositoryReferenceBootstrap struct {
 	// Enable or disable bootstrapping repository references
@@ -304,6 +572,50 @@ type RepositoryReferenceDelete struct {
 	ReconcileDirectoryChanges bool `json:"reconcileDirectoryChanges,omitempty"`
 }
 
+type DriftPolicy string
+
+const (
+	DriftPolicyReport    DriftPolicy = "report"
+	DriftPolicyRemediate DriftPolicy = "remediate"
+)
+
+// Monitoring configures scraping of Gateway pods by the Prometheus Operator
+// the ServiceMonitor or PodMonitor adds gateway, version and repository commit labels to every metric
+type Monitoring struct {
+	// Enable or disable monitoring
+	Enabled bool `json:"enabled,omitempty"`
+	// Type serviceMonitor or podMonitor. Default is serviceMonitor
+	// +kubebuilder:validation:Enum=serviceMonitor;podMonitor
+	Type string `json:"type,omitempty"`
+	// Port is the name of the metrics port, this can be a Gateway listen port or the port of an OTel collector sidecar.
+	// serviceMonitors require a service port with this name, podMonitors require a container port. Default is metrics
+	Port string `json:"port,omitempty"`
+	// Path that metrics are served on. Default is /metrics
+	Path string `json:"path,omitempty"`
+	// Scheme http or https. Default is http
+	Scheme string `json:"scheme,omitempty"`
+	// InsecureSkipVerify disables verification of the metrics endpoint certificate when scheme is https
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// Interval between scrapes. Default is 30s
+	Interval string `json:"interval,omitempty"`
+	// ScrapeTimeout. Defaults to the Prometheus global scrape timeout
+	ScrapeTimeout string `json:"scrapeTimeout,omitempty"`
+	// Labels are added to the ServiceMonitor or PodMonitor so that it is selected by Prometheus
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// DriftDetection queries Gateway pods (or the management pod for database backed Gateways) via Graphman
+// and compares the result with the latest commit of each dynamic repository reference.
+type DriftDetection struct {
+	// Enable or disable drift detection
+	Enabled bool `json:"enabled,omitempty"`
+	// IntervalSeconds how often drift detection runs. Default is 300 seconds
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+	// DriftPolicy report or remediate. Default is report
+	// report updates the repository status and metrics, remediate also reapplies the repository reference
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+}
+
 // Otel used when no dedicated OTel agent is present. This enriches the telemetry that the SDK is able to emit to your observability backend
 type Otel struct {
 	OtelSDKOnly                   OtelSDKOnly `json:"sdkOnly,omitempty"`
@@ -358,13 +670,20 @@ type Otk struct {
 	SubSolutionKitNames []string `json:"subSolutionKitNames,omitempty"`
 	// InternalOtkGatewayReference to an Operator managed Gateway deployment that is configured with otk.type: internal
 	// This configures a relationship between DMZ and Internal Gateways.
+	// Deprecated: use internalGateway
 	InternalOtkGatewayReference string `json:"internalGatewayReference,omitempty"`
+	// InternalOtkGateway that this DMZ Gateway routes to. The internal Gateway can be in a different namespace
+	// or external to the cluster. Takes precedence over internalGatewayReference
+	InternalOtkGateway OtkGatewayReference `json:"internalGateway,omitempty"`
 	// InternalGatewayPort defaults to 9443 or graphmanDynamicSync port
 	InternalGatewayPort int `json:"internalGatewayPort,omitempty"`
+	// DmzOtkGatewayReference to an Operator managed Gateway deployment that is configured with otk.type: dmz
+	// Deprecated: use dmzGateways
+	DmzOtkGatewayReference string `json:"dmzGatewayReference,omitempty"`
+	// DmzOtkGateways that front this internal Gateway. Each DMZ Gateway is trusted by this Gateway and each DMZ Gateway trusts this Gateway
+	DmzOtkGateways []OtkGatewayReference `json:"dmzGateways,omitempty"`
 	// OTKPort is used in Single mode - sets the otk.port cluster-wide property and in Dual-Mode
 	// sets host_oauth2_auth_server port in #OTK Client Context Variables
-	// TODO: Make this an array for many dmz deployments to one internal
-	DmzOtkGatewayReference string `json:"dmzGatewayReference,omitempty"`
 	// OTKPort defaults to 8443
 	OTKPort int `json:"port,omitempty"`
 	// MaintenanceTasks for the OTK database are disabled by default
@@ -373,11 +692,42 @@ type Otk struct {
 	RuntimeSyncIntervalSeconds int `json:"runtimeSyncIntervalSeconds,omitempty"`
 }
 
+// OtkGatewayReference to the other side of an internal/dmz OTK deployment
+// Operator managed Gateways are referenced by name and namespace, Gateways that are not managed by the Operator by host
+type OtkGatewayReference struct {
+	// Name of an Operator managed Gateway
+	Name string `json:"name,omitempty"`
+	// Namespace of the Operator managed Gateway, defaults to the namespace of this Gateway.
+	// The Operator must be watching this namespace
+	Namespace string `json:"namespace,omitempty"`
+	// Host of a Gateway that is not managed by the Operator
+	Host string `json:"host,omitempty"`
+	// Port of a Gateway that is not managed by the Operator, defaults to internalGatewayPort
+	Port int `json:"port,omitempty"`
+	// CaBundleSecretName is a secret in this namespace that contains the PEM encoded certificates
+	// of a Gateway that is not managed by the Operator. Required if host is set
+	CaBundleSecretName string `json:"caBundleSecretName,omitempty"`
+}
+
 // OtkMaintenanceTasks are included in the install bundle as disabled scheduled tasks
 // if enabled they will be scheduled on the leader gateway node
 type OtkMaintenanceTasks struct {
 	// Enable or disable database maintenance tasks
 	Enabled bool `json:"enabled,omitempty"`
+	// Tasks overrides the schedule of individual maintenance tasks. Tasks that are not listed use their default schedule.
+	// MySQL and Oracle gateways schedule client, id_token, sessions, token, token revocation list and miscellaneous tokens,
+	// Cassandra expires tokens and sessions itself and only schedules client and token revocation list
+	Tasks []OtkMaintenanceTask `json:"tasks,omitempty"`
+}
+
+// OtkMaintenanceTask is a scheduled task that runs an OTK Database Maintenance policy
+type OtkMaintenanceTask struct {
+	// Name of the task i.e. token or the full policy name OTK Database Maintenance - token
+	Name string `json:"name,omitempty"`
+	// Schedule is a Gateway cron expression i.e. 0 */5 * * * ?
+	Schedule string `json:"schedule,omitempty"`
+	// Disabled tasks remain on the Gateway but are not scheduled
+	Disabled bool `json:"disabled,omitempty"`
 }
 
 type OtkOverrides struct {
@@ -410,6 +760,7 @@ type RedisType string
 const (
 	RedisTypeStandalone RedisType = "standalone"
 	RedisTypeSentinel   RedisType = "sentinel"
+	RedisTypeCluster    RedisType = "cluster"
 )
 
 type RedisConfigurations struct {
@@ -444,6 +795,8 @@ type RedisConfiguration struct {
 	Sentinel RedisSentinel `json:"sentinel,omitempty"`
 	// Standalone configuration
 	Standalone RedisNode `json:"standalone,omitempty"`
+	// Cluster configuration
+	Cluster RedisCluster `json:"cluster,omitempty"`
 	// Auth if using sentinel or standalone (from Gateway v11.1.00)
 	Auth RedisAuth `json:"auth,omitempty"`
 	// TLS configuration if using sentinel or standalone (from Gateway v11.1.00)
@@ -456,6 +809,11 @@ type RedisSentinel struct {
 	Nodes     []RedisNode `json:"nodes,omitempty"`
 }
 
+type RedisCluster struct {
+	// Nodes is a list of seed nodes used to discover the cluster topology
+	Nodes []RedisNode `json:"nodes,omitempty"`
+}
+
 type RedisCerts struct {
 	// Enable or disable an additional mount for redis certificates
 	Enabled    bool   `json:"enabled,omitempty"`
@@ -488,6 +846,12 @@ type RedisSsl struct {
 	Crt string `json:"crt,omitempty"`
 	// VerifyPeer
 	VerifyPeer bool `json:"verifyPeer,omitempty"`
+	// ClientCertSecretName references a kubernetes.io/tls secret that contains a client certificate and key for mutual TLS
+	ClientCertSecretName string `json:"clientCertSecretName,omitempty"`
+	// ServerName overrides the hostname used for SNI and certificate verification
+	ServerName string `json:"serverName,omitempty"`
+	// MinVersion is the minimum TLS version, valid options are 1.2 and 1.3
+	MinVersion string `json:"minVersion,omitempty"`
 }
 
 type OtkDatabase struct {
@@ -570,10 +934,11 @@ type OtkSql struct {
 	JDBCDriverClass      string                        `json:"jdbcDriverClass,omitempty"`
 	DatabaseProperties   map[string]intstr.IntOrString `json:"databaseProperties,omitempty"`
 	ConnectionProperties map[string]intstr.IntOrString `json:"connectionProperties,omitempty"`
-	// ManageSchema appends an additional initContainer for the OTK that connects to and updates the OTK database
-	// only supports MySQL and Oracle
+	// ManageSchema runs an Operator managed Job that connects to and creates or upgrades the OTK database
+	// The Job runs once for each OTK initContainerImage and the Gateway Deployment is rolled out when it has succeeded.
+	// A failed Job can be retried by deleting it. only supports MySQL and Oracle
 	ManageSchema bool `json:"manageSchema,omitempty"`
-	// DatabaseWaitTimeout applies to the db-initcontainer only
+	// DatabaseWaitTimeout applies to the OTK schema Job only
 	DatabaseWaitTimeout int `json:"databaseWaitTimeout,omitempty"`
 }
 
@@ -624,6 +989,15 @@ type Management struct {
 	Graphman Graphman `json:"graphman,omitempty"`
 	// Service is the Gateway Management Service
 	Service Service `json:"service,omitempty"`
+	// LeaderElection configures how the management pod is elected
+	LeaderElection LeaderElection `json:"leaderElection,omitempty"`
+}
+
+// LeaderElection configures the Lease that tracks the Gateway management pod
+// the Operator prefers ready pods and the oldest pod when electing a new leader
+type LeaderElection struct {
+	// GracePeriodSeconds is how long the leader may be unready before a new leader is elected. Default is 30
+	GracePeriodSeconds int `json:"gracePeriodSeconds,omitempty"`
 }
 
 type DisklessConfig struct {
@@ -1088,6 +1462,14 @@ type RepositoryReference struct {
 	Type         RepositoryReferenceType `json:"type,omitempty"`
 	Encryption   BundleEncryption        `json:"encryption,omitempty"`
 	Notification Notification            `json:"notification,omitempty"`
+	// Verify runs HTTP checks against each Gateway pod that a new commit has been applied to,
+	// the results are recorded per pod in the repository status conditions.
+	// Limited to dynamic type on ephemeral Gateways
+	Verify []SmokeTest `json:"verify,omitempty"`
+	// RollbackOnVerifyFailure reapplies the cached bundle of the previous commit to pods that fail verification.
+	// Entities that were added by the new commit are not removed and the new commit is not retried until it changes.
+	// When disabled pods that fail verification keep the new commit and are not verified again until it changes
+	RollbackOnVerifyFailure bool `json:"rollbackOnVerifyFailure,omitempty"`
 }
 
 // BundleEncryption allows setting an encryption passphrase per repository or external secret/key reference
@@ -1142,7 +1524,8 @@ type PodResources struct {
 type Ingress struct {
 	// Enabled or disabled
 	Enabled bool `json:"enabled,omitempty"`
-	// Type ingress or route
+	// Type ingress, route or gatewayAPI
+	// gatewayAPI creates Kubernetes Gateway API routes that attach to an existing Gateway
 	Type string `json:"type,omitempty"`
 	// Annotations for the ingress resource
 	Annotations map[string]string `json:"annotations,omitempty"`
@@ -1155,6 +1538,43 @@ type Ingress struct {
 	TLS []networkingv1.IngressTLS `json:"tls,omitempty"`
 	// Rules
 	Rules []networkingv1.IngressRule `json:"rules,omitempty"`
+	// GatewayAPI configures the Kubernetes Gateway API routes that are created when type is gatewayAPI
+	GatewayAPI GatewayAPI `json:"gatewayAPI,omitempty"`
+}
+
+// GatewayAPI configures Kubernetes Gateway API routes for the Gateway and management services
+type GatewayAPI struct {
+	// ParentRefs are the Kubernetes Gateway API Gateways that routes are attached to
+	ParentRefs []GatewayAPIParentReference `json:"parentRefs,omitempty"`
+	// Routes to create, defaults to a single HTTPRoute for the Gateway service
+	Routes []GatewayAPIRoute `json:"routes,omitempty"`
+}
+
+// GatewayAPIParentReference is a reference to a Kubernetes Gateway API Gateway
+type GatewayAPIParentReference struct {
+	// Name of the Gateway
+	Name string `json:"name"`
+	// Namespace of the Gateway, defaults to the namespace of the route
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName is the name of a listener on the Gateway
+	SectionName string `json:"sectionName,omitempty"`
+	// Port of a listener on the Gateway
+	Port int32 `json:"port,omitempty"`
+}
+
+// GatewayAPIRoute
+type GatewayAPIRoute struct {
+	// Kind of route HTTPRoute, GRPCRoute or TLSRoute. Defaults to HTTPRoute
+	// +kubebuilder:validation:Enum=HTTPRoute;GRPCRoute;TLSRoute
+	Kind string `json:"kind,omitempty"`
+	// Management routes to the management service instead of the Gateway service
+	Management bool `json:"management,omitempty"`
+	// Hostnames that the route matches
+	Hostnames []string `json:"hostnames,omitempty"`
+	// Path prefix for HTTPRoutes. Defaults to /
+	Path string `json:"path,omitempty"`
+	// Port of the backend service, defaults to the https or management service port
+	Port int32 `json:"port,omitempty"`
 }
 
 // RouteSpec from https://pkg.go.dev/github.com/openshift/api/route/v1#RouteSpec
@@ -21,6 +21,7 @@ package v1
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
@@ -114,6 +115,11 @@ func validateGateway(r *Gateway) (admission.Warnings, error) {
 			if rr.Type == "" {
 				return warnings, fmt.Errorf("please specify a repository reference type in your gateway configuration. reference name: %s index: %d", rr.Name, i)
 			}
+			for _, verify := range rr.Verify {
+				if _, err := regexp.Compile(verify.ExpectedBody); err != nil {
+					return warnings, fmt.Errorf("please specify a valid expectedBody regular expression for verify check %s. reference name: %s index: %d", verify.Name, rr.Name, i)
+				}
+			}
 		}
 	}
 
@@ -169,6 +175,19 @@ func validateGateway(r *Gateway) (admission.Warnings, error) {
 		}
 	}
 
+	if r.Spec.App.Otk.Enabled {
+		if r.Spec.App.Otk.InternalOtkGateway != (OtkGatewayReference{}) {
+			if err := validateOtkGatewayReference(r.Spec.App.Otk.InternalOtkGateway); err != nil {
+				return warnings, fmt.Errorf("%w. internalGateway", err)
+			}
+		}
+		for i, ref := range r.Spec.App.Otk.DmzOtkGateways {
+			if err := validateOtkGatewayReference(ref); err != nil {
+				return warnings, fmt.Errorf("%w. dmzGateways index: %d", err, i)
+			}
+		}
+	}
+
 	if r.Spec.App.Hazelcast.External {
 		if r.Spec.App.Hazelcast.Endpoint == "" {
 			return warnings, fmt.Errorf("please specify the endpoint for your external Hazelcast server")
@@ -201,6 +220,17 @@ func validateGateway(r *Gateway) (admission.Warnings, error) {
 		}
 	}
 
+	if r.Spec.App.DeploymentStrategy == DeploymentStrategyBlueGreen {
+		if r.Spec.App.Management.Database.Enabled {
+			return warnings, fmt.Errorf("the blueGreen deploymentStrategy is not supported for database backed gateways")
+		}
+		for i, smokeTest := range r.Spec.App.BlueGreen.SmokeTests {
+			if _, err := regexp.Compile(smokeTest.ExpectedBody); err != nil {
+				return warnings, fmt.Errorf("please specify a valid expectedBody regular expression for smoke test %s. index: %d", smokeTest.Name, i)
+			}
+		}
+	}
+
 	if r.Spec.App.Management.Service.Enabled {
 		if r.Spec.App.Management.Service.Type != v1.ServiceTypeClusterIP && r.Spec.App.Management.Service.Type != v1.ServiceTypeLoadBalancer && r.Spec.App.Management.Service.Type != v1.ServiceTypeNodePort {
 			return warnings, fmt.Errorf("please specify a valid management service type, valid types are LoadBalancer, ClusterIP and NodePort")
@@ -229,3 +259,13 @@ func validateGateway(r *Gateway) (admission.Warnings, error) {
 
 	return warnings, nil
 }
+
+func validateOtkGatewayReference(ref OtkGatewayReference) error {
+	if ref.Name == "" && ref.Host == "" {
+		return fmt.Errorf("please specify the name of an operator managed gateway or the host of an external gateway in your otk gateway reference")
+	}
+	if ref.Host != "" && ref.CaBundleSecretName == "" {
+		return fmt.Errorf("please specify a caBundleSecretName for the external otk gateway %s", ref.Host)
+	}
+	return nil
+}
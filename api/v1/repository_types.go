@@ -158,7 +158,11 @@ type RepositoryStatus struct {
 	// Updated the last time this repository was successfully updated
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +operator-sdk:csv:customresourcedefinitions:displayName="Updated"
-	Updated            string `json:"updated,omitempty"`
+	Updated string `json:"updated,omitempty"`
+	// CommitTime is the time the synced commit was made, http, local and statestore repositories use the time the checksum changed
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +operator-sdk:csv:customresourcedefinitions:displayName="CommitTime"
+	CommitTime         string `json:"commitTime,omitempty"`
 	Summary            string `json:"summary,omitempty"`
 	LastAppliedSummary string `json:"lastAppliedSummary,omitempty"`
 	Vendor             string `json:"vendor,omitempty"`
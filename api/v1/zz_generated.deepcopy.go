@@ -81,7 +81,11 @@ func (in *App) DeepCopyInto(out *App) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.Upgrade = in.Upgrade
+	in.BlueGreen.DeepCopyInto(&out.BlueGreen)
 	out.RepositoryReferenceBootstrap = in.RepositoryReferenceBootstrap
+	out.DriftDetection = in.DriftDetection
+	in.Monitoring.DeepCopyInto(&out.Monitoring)
 	out.RepositoryReferenceDelete = in.RepositoryReferenceDelete
 	if in.RepositoryReferences != nil {
 		in, out := &in.RepositoryReferences, &out.RepositoryReferences
@@ -190,6 +194,48 @@ func (in *Autoscaling) DeepCopy() *Autoscaling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreen) DeepCopyInto(out *BlueGreen) {
+	*out = *in
+	if in.SmokeTests != nil {
+		in, out := &in.SmokeTests, &out.SmokeTests
+		*out = make([]SmokeTest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreen.
+func (in *BlueGreen) DeepCopy() *BlueGreen {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreen)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenStatus) DeepCopyInto(out *BlueGreenStatus) {
+	*out = *in
+	if in.SmokeTests != nil {
+		in, out := &in.SmokeTests, &out.SmokeTests
+		*out = make([]SmokeTestResult, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenStatus.
+func (in *BlueGreenStatus) DeepCopy() *BlueGreenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Bootstrap) DeepCopyInto(out *Bootstrap) {
 	*out = *in
@@ -460,6 +506,41 @@ func (in *DisklessConfig) DeepCopy() *DisklessConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetection) DeepCopyInto(out *DriftDetection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetection.
+func (in *DriftDetection) DeepCopy() *DriftDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftedEntity) DeepCopyInto(out *DriftedEntity) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftedEntity.
+func (in *DriftedEntity) DeepCopy() *DriftedEntity {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftedEntity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalCert) DeepCopyInto(out *ExternalCert) {
 	*out = *in
@@ -538,6 +619,68 @@ func (in *Gateway) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayAPI) DeepCopyInto(out *GatewayAPI) {
+	*out = *in
+	if in.ParentRefs != nil {
+		in, out := &in.ParentRefs, &out.ParentRefs
+		*out = make([]GatewayAPIParentReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]GatewayAPIRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayAPI.
+func (in *GatewayAPI) DeepCopy() *GatewayAPI {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayAPI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayAPIParentReference) DeepCopyInto(out *GatewayAPIParentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayAPIParentReference.
+func (in *GatewayAPIParentReference) DeepCopy() *GatewayAPIParentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayAPIParentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayAPIRoute) DeepCopyInto(out *GatewayAPIRoute) {
+	*out = *in
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayAPIRoute.
+func (in *GatewayAPIRoute) DeepCopy() *GatewayAPIRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayAPIRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayList) DeepCopyInto(out *GatewayList) {
 	*out = *in
@@ -583,6 +726,13 @@ func (in *GatewayRepositoryStatus) DeepCopyInto(out *GatewayRepositoryStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]DriftedEntity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRepositoryStatus.
@@ -651,6 +801,8 @@ func (in *GatewayStatus) DeepCopyInto(out *GatewayStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.License.DeepCopyInto(&out.License)
+	in.LeaderElection.DeepCopyInto(&out.LeaderElection)
 	if in.RepositoryStatus != nil {
 		in, out := &in.RepositoryStatus, &out.RepositoryStatus
 		*out = make([]GatewayRepositoryStatus, len(*in))
@@ -658,7 +810,11 @@ func (in *GatewayStatus) DeepCopyInto(out *GatewayStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.GatewayUpgrade.DeepCopyInto(&out.GatewayUpgrade)
+	in.BlueGreen.DeepCopyInto(&out.BlueGreen)
 	out.PortalSyncStatus = in.PortalSyncStatus
+	out.OtkSchema = in.OtkSchema
+	in.OtkMaintenanceTasks.DeepCopyInto(&out.OtkMaintenanceTasks)
 	if in.LastAppliedClusterProperties != nil {
 		in, out := &in.LastAppliedClusterProperties, &out.LastAppliedClusterProperties
 		*out = make([]string, len(*in))
@@ -718,6 +874,56 @@ func (in *GatewayStatus) DeepCopy() *GatewayStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayUpgrade) DeepCopyInto(out *GatewayUpgrade) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayUpgrade.
+func (in *GatewayUpgrade) DeepCopy() *GatewayUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayUpgradeCheck) DeepCopyInto(out *GatewayUpgradeCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayUpgradeCheck.
+func (in *GatewayUpgradeCheck) DeepCopy() *GatewayUpgradeCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayUpgradeCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayUpgradeStatus) DeepCopyInto(out *GatewayUpgradeStatus) {
+	*out = *in
+	if in.PreflightChecks != nil {
+		in, out := &in.PreflightChecks, &out.PreflightChecks
+		*out = make([]GatewayUpgradeCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayUpgradeStatus.
+func (in *GatewayUpgradeStatus) DeepCopy() *GatewayUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Graphman) DeepCopyInto(out *Graphman) {
 	*out = *in
@@ -808,6 +1014,7 @@ func (in *Ingress) DeepCopyInto(out *Ingress) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.GatewayAPI.DeepCopyInto(&out.GatewayAPI)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
@@ -856,6 +1063,56 @@ func (in *Java) DeepCopy() *Java {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElection) DeepCopyInto(out *LeaderElection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElection.
+func (in *LeaderElection) DeepCopy() *LeaderElection {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElectionEvent) DeepCopyInto(out *LeaderElectionEvent) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectionEvent.
+func (in *LeaderElectionEvent) DeepCopy() *LeaderElectionEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElectionEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElectionStatus) DeepCopyInto(out *LeaderElectionStatus) {
+	*out = *in
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]LeaderElectionEvent, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectionStatus.
+func (in *LeaderElectionStatus) DeepCopy() *LeaderElectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *License) DeepCopyInto(out *License) {
 	*out = *in
@@ -871,6 +1128,26 @@ func (in *License) DeepCopy() *License {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LicenseStatus) DeepCopyInto(out *LicenseStatus) {
+	*out = *in
+	if in.FeatureSets != nil {
+		in, out := &in.FeatureSets, &out.FeatureSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LicenseStatus.
+func (in *LicenseStatus) DeepCopy() *LicenseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LicenseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ListenPort) DeepCopyInto(out *ListenPort) {
 	*out = *in
@@ -959,6 +1236,7 @@ func (in *Management) DeepCopyInto(out *Management) {
 	out.Restman = in.Restman
 	in.Graphman.DeepCopyInto(&out.Graphman)
 	in.Service.DeepCopyInto(&out.Service)
+	out.LeaderElection = in.LeaderElection
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Management.
@@ -971,6 +1249,28 @@ func (in *Management) DeepCopy() *Management {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Monitoring) DeepCopyInto(out *Monitoring) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Monitoring.
+func (in *Monitoring) DeepCopy() *Monitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(Monitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Notification) DeepCopyInto(out *Notification) {
 	*out = *in
@@ -1034,7 +1334,13 @@ func (in *Otk) DeepCopyInto(out *Otk) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.MaintenanceTasks = in.MaintenanceTasks
+	out.InternalOtkGateway = in.InternalOtkGateway
+	if in.DmzOtkGateways != nil {
+		in, out := &in.DmzOtkGateways, &out.DmzOtkGateways
+		*out = make([]OtkGatewayReference, len(*in))
+		copy(*out, *in)
+	}
+	in.MaintenanceTasks.DeepCopyInto(&out.MaintenanceTasks)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Otk.
@@ -1123,9 +1429,44 @@ func (in *OtkDatabaseAuthCredentials) DeepCopy() *OtkDatabaseAuthCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OtkGatewayReference) DeepCopyInto(out *OtkGatewayReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtkGatewayReference.
+func (in *OtkGatewayReference) DeepCopy() *OtkGatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(OtkGatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OtkMaintenanceTask) DeepCopyInto(out *OtkMaintenanceTask) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtkMaintenanceTask.
+func (in *OtkMaintenanceTask) DeepCopy() *OtkMaintenanceTask {
+	if in == nil {
+		return nil
+	}
+	out := new(OtkMaintenanceTask)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OtkMaintenanceTasks) DeepCopyInto(out *OtkMaintenanceTasks) {
 	*out = *in
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]OtkMaintenanceTask, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtkMaintenanceTasks.
@@ -1138,6 +1479,26 @@ func (in *OtkMaintenanceTasks) DeepCopy() *OtkMaintenanceTasks {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OtkMaintenanceTasksStatus) DeepCopyInto(out *OtkMaintenanceTasksStatus) {
+	*out = *in
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]OtkMaintenanceTask, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtkMaintenanceTasksStatus.
+func (in *OtkMaintenanceTasksStatus) DeepCopy() *OtkMaintenanceTasksStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OtkMaintenanceTasksStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OtkOverrides) DeepCopyInto(out *OtkOverrides) {
 	*out = *in
@@ -1153,6 +1514,21 @@ func (in *OtkOverrides) DeepCopy() *OtkOverrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OtkSchemaStatus) DeepCopyInto(out *OtkSchemaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtkSchemaStatus.
+func (in *OtkSchemaStatus) DeepCopy() *OtkSchemaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OtkSchemaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OtkSql) DeepCopyInto(out *OtkSql) {
 	*out = *in
@@ -1339,11 +1715,32 @@ func (in *RedisCerts) DeepCopy() *RedisCerts {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisCluster) DeepCopyInto(out *RedisCluster) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]RedisNode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisCluster.
+func (in *RedisCluster) DeepCopy() *RedisCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisConfiguration) DeepCopyInto(out *RedisConfiguration) {
 	*out = *in
 	in.Sentinel.DeepCopyInto(&out.Sentinel)
 	out.Standalone = in.Standalone
+	in.Cluster.DeepCopyInto(&out.Cluster)
 	out.Auth = in.Auth
 	out.Ssl = in.Ssl
 }
@@ -1535,6 +1932,13 @@ func (in *RepositoryReference) DeepCopyInto(out *RepositoryReference) {
 	}
 	out.Encryption = in.Encryption
 	in.Notification.DeepCopyInto(&out.Notification)
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = make([]SmokeTest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryReference.
@@ -1752,6 +2156,43 @@ func (in *ServiceAccount) DeepCopy() *ServiceAccount {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SmokeTest) DeepCopyInto(out *SmokeTest) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SmokeTest.
+func (in *SmokeTest) DeepCopy() *SmokeTest {
+	if in == nil {
+		return nil
+	}
+	out := new(SmokeTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SmokeTestResult) DeepCopyInto(out *SmokeTestResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SmokeTestResult.
+func (in *SmokeTestResult) DeepCopy() *SmokeTestResult {
+	if in == nil {
+		return nil
+	}
+	out := new(SmokeTestResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *System) DeepCopyInto(out *System) {
 	*out = *in
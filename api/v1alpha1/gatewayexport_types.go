@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Broadcom Inc. and its subsidiaries. All Rights Reserved.
+
+package v1alpha1
+
+import (
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GatewayExportSpec defines the desired state of GatewayExport
+type GatewayExportSpec struct {
+	// Enabled - if enabled the configuration of the Gateway is exported to the repository
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enabled"
+	Enabled bool `json:"enabled,omitempty"`
+	// GatewayReference is the name of a Gateway in the same namespace as the GatewayExport
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="GatewayReference"
+	GatewayReference string `json:"gatewayReference,omitempty"`
+	// RepositoryReference is the Git repository that the configuration is pushed to
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RepositoryReference"
+	RepositoryReference ExportRepositoryReference `json:"repositoryReference,omitempty"`
+	// IntervalSeconds how often the configuration is exported. If 0 the configuration is only exported
+	// when the GatewayExport is created or when ExportRequest changes
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="IntervalSeconds"
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+	// ExportRequest - changing this value triggers an export on demand
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ExportRequest"
+	ExportRequest string `json:"exportRequest,omitempty"`
+	// Encryption - secrets and private keys are encrypted with this passphrase.
+	// If no passphrase is configured secret values are left out of the export
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Encryption"
+	Encryption securityv1.BundleEncryption `json:"encryption,omitempty"`
+}
+
+// ExportRepositoryReference is a Git Repository that the Gateway configuration is exported to
+type ExportRepositoryReference struct {
+	// Name of a Repository of type git in the same namespace, its endpoint and auth are used to push
+	Name string `json:"name,omitempty"`
+	// Branch that the export is pushed to. Defaults to the Repository branch.
+	// The branch is created if it does not exist
+	Branch string `json:"branch,omitempty"`
+	// Path is the directory in the repository that the exploded configuration is written to.
+	// Defaults to the root of the repository
+	Path string `json:"path,omitempty"`
+	// Author of the export commits
+	Author ExportAuthor `json:"author,omitempty"`
+}
+
+// ExportAuthor
+type ExportAuthor struct {
+	// Name defaults to layer7-operator
+	Name string `json:"name,omitempty"`
+	// Email defaults to layer7-operator@localhost
+	Email string `json:"email,omitempty"`
+}
+
+// GatewayExportStatus defines the observed state of GatewayExport
+type GatewayExportStatus struct {
+	// Ready is true when the last export was pushed successfully
+	Ready bool `json:"ready"`
+	// Commit is the latest commit on the export branch
+	Commit string `json:"commit,omitempty"`
+	// Pod is the Gateway pod that the configuration was exported from
+	Pod string `json:"pod,omitempty"`
+	// Entities is the number of entities in the last export
+	Entities int `json:"entities,omitempty"`
+	// ExportRequest is the last ExportRequest that was handled
+	ExportRequest string `json:"exportRequest,omitempty"`
+	// Reason is set if the last export failed
+	Reason     string `json:"reason,omitempty"`
+	LastExport string `json:"lastExport,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=gwexport;gwexports
+
+// GatewayExport is the Schema for the gatewayexports API
+type GatewayExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GatewayExportSpec   `json:"spec,omitempty"`
+	Status GatewayExportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GatewayExportList contains a list of GatewayExport
+type GatewayExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GatewayExport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GatewayExport{}, &GatewayExportList{})
+}
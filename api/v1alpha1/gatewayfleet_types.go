@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Broadcom Inc. and its subsidiaries. All Rights Reserved.
+
+package v1alpha1
+
+import (
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GatewayFleetSpec defines the desired state of GatewayFleet
+type GatewayFleetSpec struct {
+	// Enabled - if enabled the repository is rolled out to every member cluster
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enabled"
+	Enabled bool `json:"enabled,omitempty"`
+	// RepositoryReference is a Repository in the same namespace as the GatewayFleet
+	// its latest commit is applied to every Gateway in each member cluster
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RepositoryReference"
+	RepositoryReference securityv1.RepositoryReference `json:"repositoryReference,omitempty"`
+	// Clusters that are members of this fleet
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Clusters"
+	Clusters []RemoteCluster `json:"clusters,omitempty"`
+	// SyncIntervalSeconds how often the GatewayFleet CR is reconciled. Default is 30 seconds
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="SyncIntervalSeconds"
+	SyncIntervalSeconds int `json:"syncIntervalSeconds,omitempty"`
+}
+
+// RemoteCluster is a member cluster of a GatewayFleet
+// Graphman requests are sent to Gateway pod IPs in the member cluster
+// the Operator must be able to route to the member cluster pod network
+type RemoteCluster struct {
+	// Name of the member cluster, used in status
+	Name string `json:"name,omitempty"`
+	// Enabled - if disabled this cluster is skipped
+	Enabled bool `json:"enabled,omitempty"`
+	// KubeconfigSecret references a Secret in the GatewayFleet namespace that contains a kubeconfig for the member cluster
+	KubeconfigSecret KubeconfigSecret `json:"kubeconfigSecret,omitempty"`
+	// Namespace in the member cluster that contains the Gateways. Defaults to the GatewayFleet namespace
+	Namespace string `json:"namespace,omitempty"`
+	// Gateways are the names of Gateway custom resources in the member cluster
+	Gateways []string `json:"gateways,omitempty"`
+}
+
+// KubeconfigSecret
+type KubeconfigSecret struct {
+	// Name of the Secret
+	Name string `json:"name,omitempty"`
+	// Key in the Secret that contains the kubeconfig. Defaults to kubeconfig
+	Key string `json:"key,omitempty"`
+}
+
+// GatewayFleetStatus defines the observed state of GatewayFleet
+type GatewayFleetStatus struct {
+	// Ready is true when every Gateway in every enabled member cluster has the latest commit
+	Ready bool `json:"ready"`
+	// Commit is the Repository commit that is being rolled out
+	Commit string `json:"commit,omitempty"`
+	// UpdatedClusters is the number of member clusters where every Gateway has the latest commit
+	UpdatedClusters int `json:"updatedClusters,omitempty"`
+	// Clusters reports the rollout status of each member cluster
+	Clusters    []RemoteClusterStatus `json:"clusters,omitempty"`
+	LastUpdated string                `json:"lastUpdated,omitempty"`
+}
+
+// RemoteClusterStatus
+type RemoteClusterStatus struct {
+	Name string `json:"name,omitempty"`
+	// Ready is true when every Gateway in this cluster has the latest commit
+	Ready bool `json:"ready"`
+	// Reason is set if the member cluster could not be reconciled
+	Reason   string                `json:"reason,omitempty"`
+	Gateways []RemoteGatewayStatus `json:"gateways,omitempty"`
+}
+
+// RemoteGatewayStatus
+type RemoteGatewayStatus struct {
+	Name string `json:"name,omitempty"`
+	// Pods is the number of Gateway pods that are not being deleted
+	Pods int `json:"pods,omitempty"`
+	// UpdatedPods is the number of Gateway pods that have the latest commit applied
+	UpdatedPods int `json:"updatedPods,omitempty"`
+	// Reason is set if the repository could not be applied to this Gateway
+	Reason string `json:"reason,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=fleet;fleets
+
+// GatewayFleet is the Schema for the gatewayfleets API
+type GatewayFleet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GatewayFleetSpec   `json:"spec,omitempty"`
+	Status GatewayFleetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GatewayFleetList contains a list of GatewayFleet
+type GatewayFleetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GatewayFleet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GatewayFleet{}, &GatewayFleetList{})
+}
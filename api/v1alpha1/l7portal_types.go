@@ -35,6 +35,7 @@ type L7PortalSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Endpoint"
 	Endpoint string `json:"endpoint,omitempty"`
 	// EnrollmentBundle - allows a custom enrollment bundle to be set in the Portal CR
+	// this is a Graphman bundle or a base64 encoded Graphman bundle, it is retrieved from the Portal if not set
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="EnrollmentBundle"
 	EnrollmentBundle string `json:"enrollmentBundle,omitempty"`
 	// Deployment Tags - determines which Gateway deployments these APIs will be applied to
@@ -84,9 +85,13 @@ type L7PortalList struct {
 
 // PortalAuth
 type PortalAuth struct {
-	Endpoint           string `json:"endpoint,omitempty"`
-	PapiClientId       string `json:"clientId,omitempty"`
-	PapiClientSecret   string `json:"clientSecret,omitempty"`
+	// Endpoint of the Portal token service, defaults to the Portal endpoint
+	Endpoint string `json:"endpoint,omitempty"`
+	// PapiClientId is the client id of a Portal API (PAPI) client
+	PapiClientId string `json:"clientId,omitempty"`
+	// PapiClientSecret is the client secret of a Portal API (PAPI) client
+	PapiClientSecret string `json:"clientSecret,omitempty"`
+	// ExistingSecretName is a Secret with clientId and clientSecret keys
 	ExistingSecretName string `json:"existingSecretName,omitempty"`
 }
 
@@ -31,6 +31,7 @@ type RedisType string
 const (
 	RedisTypeStandalone RedisType = "standalone"
 	RedisTypeSentinel   RedisType = "sentinel"
+	RedisTypeCluster    RedisType = "cluster"
 )
 
 // L7StateStoreSpec defines the desired state of L7StateStore
@@ -79,13 +80,36 @@ type Redis struct {
 	StoreId        string          `json:"storeId,omitempty"`
 	Standalone     RedisStandalone `json:"standalone,omitempty"`
 	Sentinel       RedisSentinel   `json:"sentinel,omitempty"`
+	Cluster        RedisCluster    `json:"cluster,omitempty"`
 	Database       int             `json:"database,omitempty"`
+	// ReadOnly credentials are mounted into Gateway initContainers in place of the
+	// read-write credentials used by the Operator
+	ReadOnly RedisReadOnlyCredentials `json:"readOnly,omitempty"`
+}
+
+type RedisReadOnlyCredentials struct {
+	// ExistingSecret containing username and masterPassword keys
+	ExistingSecret string `json:"existingSecret,omitempty"`
+	Username       string `json:"username,omitempty"`
+	MasterPassword string `json:"masterPassword,omitempty"`
 }
 
 type RedisTls struct {
 	Enabled    bool   `json:"enabled,omitempty"`
 	RedisCrt   string `json:"redisCrt,omitempty"`
 	VerifyPeer bool   `json:"verifyPeer,omitempty"`
+	// ExistingSecret is a kubernetes.io/tls Secret containing a client certificate and key (tls.crt, tls.key)
+	// for mutual TLS. The Secret is read on every connection so rotated certificates are picked up automatically.
+	// Gateway initContainers mount this secret at /graphman/statestore-tls/<l7statestore-name>
+	ExistingSecret string `json:"existingSecret,omitempty"`
+	// ClientCrt in plaintext, ignored if existingSecret is set
+	ClientCrt string `json:"clientCrt,omitempty"`
+	// ClientKey in plaintext, ignored if existingSecret is set
+	ClientKey string `json:"clientKey,omitempty"`
+	// ServerName overrides the hostname used for SNI and certificate verification
+	ServerName string `json:"serverName,omitempty"`
+	// MinVersion is the minimum TLS version, valid options are 1.2 and 1.3. Defaults to 1.2
+	MinVersion string `json:"minVersion,omitempty"`
 }
 
 type RedisSentinel struct {
@@ -98,6 +122,16 @@ type RedisSentinelNode struct {
 	Port int    `json:"port,omitempty"`
 }
 
+type RedisCluster struct {
+	// Nodes is a list of seed nodes used to discover the cluster topology
+	Nodes []RedisClusterNode `json:"nodes,omitempty"`
+}
+
+type RedisClusterNode struct {
+	Host string `json:"host,omitempty"`
+	Port int    `json:"port,omitempty"`
+}
+
 type RedisStandalone struct {
 	Host string `json:"host,omitempty"`
 	Port int    `json:"port,omitempty"`
@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Broadcom Inc. and its subsidiaries. All Rights Reserved.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OtkClientSpec defines the desired state of OtkClient
+type OtkClientSpec struct {
+	// GatewayReference is the name of an OTK enabled Gateway in the same namespace as the OtkClient.
+	// The Gateway must be configured with otk.type single or internal
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="GatewayReference"
+	GatewayReference string `json:"gatewayReference,omitempty"`
+	// ClientName is the name of the client in OTK, defaults to the name of the OtkClient
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ClientName"
+	ClientName string `json:"clientName,omitempty"`
+	// ClientId is the OAuth client_id (client key) that applications use
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ClientId"
+	ClientId string `json:"clientId,omitempty"`
+	// ClientSecret is the Kubernetes secret that contains the OAuth client_secret.
+	// The client is updated when the secret changes. Public clients do not have a secret
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ClientSecret"
+	ClientSecret OtkClientSecret `json:"clientSecret,omitempty"`
+	// Type confidential or public, defaults to confidential
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Type"
+	Type OtkClientType `json:"type,omitempty"`
+	// Description of the client
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Description"
+	Description string `json:"description,omitempty"`
+	// RedirectUris that authorization responses can be sent to
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RedirectUris"
+	RedirectUris []string `json:"redirectUris,omitempty"`
+	// Scopes that the client can request
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scopes"
+	Scopes []string `json:"scopes,omitempty"`
+	// GrantTypes that the client can use i.e. authorization_code, client_credentials, refresh_token
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="GrantTypes"
+	GrantTypes []string `json:"grantTypes,omitempty"`
+}
+
+type OtkClientType string
+
+const (
+	OtkClientTypeConfidential OtkClientType = "confidential"
+	OtkClientTypePublic       OtkClientType = "public"
+)
+
+// OtkClientSecret is a Kubernetes secret in the same namespace as the OtkClient
+type OtkClientSecret struct {
+	// SecretName of the secret that contains the client secret
+	SecretName string `json:"secretName,omitempty"`
+	// Key in the secret, defaults to clientSecret
+	Key string `json:"key,omitempty"`
+}
+
+// OtkClientStatus defines the observed state of OtkClient
+type OtkClientStatus struct {
+	// Registered is true when the current client configuration has been registered with the Gateway
+	Registered bool `json:"registered"`
+	// Gateway that the client is registered with
+	Gateway string `json:"gateway,omitempty"`
+	// Pod that the client was registered through
+	Pod string `json:"pod,omitempty"`
+	// ClientId that is registered
+	ClientId string `json:"clientId,omitempty"`
+	// Checksum of the registered client configuration and client secret
+	Checksum string `json:"checksum,omitempty"`
+	// Reason is set if the last registration failed
+	Reason      string `json:"reason,omitempty"`
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=otkc;otkcs
+
+// OtkClient is the Schema for the otkclients API
+type OtkClient struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OtkClientSpec   `json:"spec,omitempty"`
+	Status OtkClientStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OtkClientList contains a list of OtkClient
+type OtkClientList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OtkClient `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OtkClient{}, &OtkClientList{})
+}
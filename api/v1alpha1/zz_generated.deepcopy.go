@@ -54,6 +54,232 @@ func (in *EnrollmentBundle) DeepCopy() *EnrollmentBundle {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportAuthor) DeepCopyInto(out *ExportAuthor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportAuthor.
+func (in *ExportAuthor) DeepCopy() *ExportAuthor {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportAuthor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportRepositoryReference) DeepCopyInto(out *ExportRepositoryReference) {
+	*out = *in
+	out.Author = in.Author
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportRepositoryReference.
+func (in *ExportRepositoryReference) DeepCopy() *ExportRepositoryReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportRepositoryReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayExport) DeepCopyInto(out *GatewayExport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayExport.
+func (in *GatewayExport) DeepCopy() *GatewayExport {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayExport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayExportList) DeepCopyInto(out *GatewayExportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GatewayExport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayExportList.
+func (in *GatewayExportList) DeepCopy() *GatewayExportList {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayExportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayExportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayExportSpec) DeepCopyInto(out *GatewayExportSpec) {
+	*out = *in
+	out.RepositoryReference = in.RepositoryReference
+	out.Encryption = in.Encryption
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayExportSpec.
+func (in *GatewayExportSpec) DeepCopy() *GatewayExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayExportStatus) DeepCopyInto(out *GatewayExportStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayExportStatus.
+func (in *GatewayExportStatus) DeepCopy() *GatewayExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayFleet) DeepCopyInto(out *GatewayFleet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayFleet.
+func (in *GatewayFleet) DeepCopy() *GatewayFleet {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayFleet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayFleet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayFleetList) DeepCopyInto(out *GatewayFleetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GatewayFleet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayFleetList.
+func (in *GatewayFleetList) DeepCopy() *GatewayFleetList {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayFleetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayFleetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayFleetSpec) DeepCopyInto(out *GatewayFleetSpec) {
+	*out = *in
+	in.RepositoryReference.DeepCopyInto(&out.RepositoryReference)
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]RemoteCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayFleetSpec.
+func (in *GatewayFleetSpec) DeepCopy() *GatewayFleetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayFleetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayFleetStatus) DeepCopyInto(out *GatewayFleetStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]RemoteClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayFleetStatus.
+func (in *GatewayFleetStatus) DeepCopy() *GatewayFleetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayFleetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayPodDeploymentCondition) DeepCopyInto(out *GatewayPodDeploymentCondition) {
 	*out = *in
@@ -89,6 +315,21 @@ func (in *GatewayProxy) DeepCopy() *GatewayProxy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeconfigSecret) DeepCopyInto(out *KubeconfigSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigSecret.
+func (in *KubeconfigSecret) DeepCopy() *KubeconfigSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeconfigSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *L7Api) DeepCopyInto(out *L7Api) {
 	*out = *in
@@ -411,6 +652,126 @@ func (in *LinkedGatewayStatus) DeepCopy() *LinkedGatewayStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OtkClient) DeepCopyInto(out *OtkClient) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtkClient.
+func (in *OtkClient) DeepCopy() *OtkClient {
+	if in == nil {
+		return nil
+	}
+	out := new(OtkClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OtkClient) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OtkClientList) DeepCopyInto(out *OtkClientList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OtkClient, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtkClientList.
+func (in *OtkClientList) DeepCopy() *OtkClientList {
+	if in == nil {
+		return nil
+	}
+	out := new(OtkClientList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OtkClientList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OtkClientSecret) DeepCopyInto(out *OtkClientSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtkClientSecret.
+func (in *OtkClientSecret) DeepCopy() *OtkClientSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(OtkClientSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OtkClientSpec) DeepCopyInto(out *OtkClientSpec) {
+	*out = *in
+	out.ClientSecret = in.ClientSecret
+	if in.RedirectUris != nil {
+		in, out := &in.RedirectUris, &out.RedirectUris
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GrantTypes != nil {
+		in, out := &in.GrantTypes, &out.GrantTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtkClientSpec.
+func (in *OtkClientSpec) DeepCopy() *OtkClientSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OtkClientSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OtkClientStatus) DeepCopyInto(out *OtkClientStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OtkClientStatus.
+func (in *OtkClientStatus) DeepCopy() *OtkClientStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OtkClientStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyTemplate) DeepCopyInto(out *PolicyTemplate) {
 	*out = *in
@@ -519,6 +880,8 @@ func (in *Redis) DeepCopyInto(out *Redis) {
 	out.Tls = in.Tls
 	out.Standalone = in.Standalone
 	in.Sentinel.DeepCopyInto(&out.Sentinel)
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	out.ReadOnly = in.ReadOnly
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redis.
@@ -531,6 +894,56 @@ func (in *Redis) DeepCopy() *Redis {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisCluster) DeepCopyInto(out *RedisCluster) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]RedisClusterNode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisCluster.
+func (in *RedisCluster) DeepCopy() *RedisCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisClusterNode) DeepCopyInto(out *RedisClusterNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisClusterNode.
+func (in *RedisClusterNode) DeepCopy() *RedisClusterNode {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisClusterNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisReadOnlyCredentials) DeepCopyInto(out *RedisReadOnlyCredentials) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisReadOnlyCredentials.
+func (in *RedisReadOnlyCredentials) DeepCopy() *RedisReadOnlyCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisReadOnlyCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisSentinel) DeepCopyInto(out *RedisSentinel) {
 	*out = *in
@@ -596,6 +1009,62 @@ func (in *RedisTls) DeepCopy() *RedisTls {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
+	*out = *in
+	out.KubeconfigSecret = in.KubeconfigSecret
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteCluster.
+func (in *RemoteCluster) DeepCopy() *RemoteCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterStatus) DeepCopyInto(out *RemoteClusterStatus) {
+	*out = *in
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]RemoteGatewayStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterStatus.
+func (in *RemoteClusterStatus) DeepCopy() *RemoteClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteGatewayStatus) DeepCopyInto(out *RemoteGatewayStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteGatewayStatus.
+func (in *RemoteGatewayStatus) DeepCopy() *RemoteGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurePassword) DeepCopyInto(out *SecurePassword) {
 	*out = *in
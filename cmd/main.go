@@ -54,10 +54,14 @@ import (
 	securityv1 "github.com/caapim/layer7-operator/api/v1"
 	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
 	"github.com/caapim/layer7-operator/internal/controller/api"
+	"github.com/caapim/layer7-operator/internal/controller/export"
+	"github.com/caapim/layer7-operator/internal/controller/fleet"
 	"github.com/caapim/layer7-operator/internal/controller/gateway"
+	"github.com/caapim/layer7-operator/internal/controller/otkclient"
 	"github.com/caapim/layer7-operator/internal/controller/portal"
 	"github.com/caapim/layer7-operator/internal/controller/repository"
 	controller "github.com/caapim/layer7-operator/internal/controller/statestore"
+	"github.com/caapim/layer7-operator/internal/graphman"
 	"github.com/caapim/layer7-operator/internal/platform"
 	"github.com/caapim/layer7-operator/pkg/util"
 	//+kubebuilder:scaffold:imports
@@ -91,6 +95,23 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", true,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	graphmanClientConfig := graphman.DefaultClientConfig
+	flag.DurationVar(&graphmanClientConfig.RequestTimeout, "graphman-request-timeout", graphmanClientConfig.RequestTimeout,
+		"The deadline for a single Graphman request to a Gateway pod.")
+	flag.IntVar(&graphmanClientConfig.MaxRetries, "graphman-max-retries", graphmanClientConfig.MaxRetries,
+		"The number of times a Graphman request is retried after a connection error or an unavailable response.")
+	flag.DurationVar(&graphmanClientConfig.RetryBackoff, "graphman-retry-backoff", graphmanClientConfig.RetryBackoff,
+		"The initial delay between Graphman retries, it doubles after each attempt and is jittered.")
+	flag.DurationVar(&graphmanClientConfig.MaxRetryBackoff, "graphman-max-retry-backoff", graphmanClientConfig.MaxRetryBackoff,
+		"The maximum delay between Graphman retries.")
+	flag.Float64Var(&graphmanClientConfig.RequestsPerSecond, "graphman-requests-per-second", graphmanClientConfig.RequestsPerSecond,
+		"The maximum rate of Graphman requests to each Gateway pod. 0 disables rate limiting.")
+	flag.IntVar(&graphmanClientConfig.FailureThreshold, "graphman-failure-threshold", graphmanClientConfig.FailureThreshold,
+		"The number of consecutive failed Graphman requests before a Gateway pod is marked unhealthy and skipped. 0 disables circuit breaking.")
+	flag.DurationVar(&graphmanClientConfig.OpenDuration, "graphman-unhealthy-duration", graphmanClientConfig.OpenDuration,
+		"How long an unhealthy Gateway pod is skipped before Graphman requests are attempted again.")
+	flag.IntVar(&graphmanClientConfig.MaxBundleSize, "graphman-max-bundle-size", graphmanClientConfig.MaxBundleSize,
+		"The approximate size in bytes above which Graphman bundles are split into dependency ordered chunks. 0 disables chunking.")
 	opts := zap.Options{
 		Development: false,
 	}
@@ -100,6 +121,7 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	graphman.SetClientConfig(graphmanClientConfig)
 
 	disableHTTP2 := func(c *tls.Config) {
 		setupLog.Info("disabling http/2")
@@ -229,6 +251,39 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&fleet.GatewayFleetReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("GatewayFleet"),
+		Recorder: mgr.GetEventRecorderFor("GatewayFleet"),
+		Scheme:   mgr.GetScheme(),
+		Platform: string(platform),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GatewayFleet")
+		os.Exit(1)
+	}
+
+	if err = (&export.GatewayExportReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("GatewayExport"),
+		Recorder: mgr.GetEventRecorderFor("GatewayExport"),
+		Scheme:   mgr.GetScheme(),
+		Platform: string(platform),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GatewayExport")
+		os.Exit(1)
+	}
+
+	if err = (&otkclient.OtkClientReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("OtkClient"),
+		Recorder: mgr.GetEventRecorderFor("OtkClient"),
+		Scheme:   mgr.GetScheme(),
+		Platform: string(platform),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OtkClient")
+		os.Exit(1)
+	}
+
 	if webhookenabled {
 		if err = (&securityv1.Gateway{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Gateway")
@@ -250,12 +305,12 @@ func main() {
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer cancel()
 
-		meterShutdown, err := util.InitOTelProvider(collectorUrl, ctx)
+		otelShutdown, err := util.InitOTelProvider(collectorUrl, ctx)
 		if err != nil {
 			setupLog.Error(err, "error in starting otel context")
 		}
 		defer func() {
-			if err := meterShutdown(ctx); err != nil {
+			if err := otelShutdown(ctx); err != nil {
 				setupLog.Error(err, "failed to shutdown otel provider")
 			}
 		}()
@@ -28,18 +28,29 @@ package api
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
 	"github.com/caapim/layer7-operator/pkg/api/reconcile"
+	"github.com/caapim/layer7-operator/pkg/util"
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	creconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const apiFinalizer = "security.brcmlabs.com/finalizer"
@@ -54,6 +65,9 @@ type L7ApiReconciler struct {
 }
 
 func (r *L7ApiReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := util.StartSpan(ctx, "L7ApiReconciler.Reconcile", attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer span.End()
+
 	_ = log.FromContext(ctx)
 
 	log := r.Log.WithValues("L7Api", req.NamespacedName)
@@ -115,5 +129,69 @@ func (r *L7ApiReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 func (r *L7ApiReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&securityv1alpha1.L7Api{}).
+		// new or replaced Gateway pods are deployed without waiting for the L7Api to change
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.l7ApisForGateway),
+			builder.WithPredicates(gatewayPodPredicate())).
+		Watches(&appsv1.Deployment{},
+			handler.EnqueueRequestsFromMapFunc(r.l7ApisForGateway),
+			builder.WithPredicates(gatewayPodPredicate())).
 		Complete(r)
 }
+
+// l7ApisForGateway maps a Gateway pod or Deployment to the L7Apis whose deployment tags include the Gateway
+func (r *L7ApiReconciler) l7ApisForGateway(ctx context.Context, a client.Object) []creconcile.Request {
+	gatewayName, ok := util.DefaultLabelsName(a.GetLabels())
+	if !ok {
+		return []creconcile.Request{}
+	}
+
+	l7ApiList := &securityv1alpha1.L7ApiList{}
+	err := r.List(ctx, l7ApiList, client.InNamespace(a.GetNamespace()))
+	if err != nil {
+		return []creconcile.Request{}
+	}
+	req := []creconcile.Request{}
+	for _, l7Api := range l7ApiList.Items {
+		if slices.Contains(l7Api.Spec.DeploymentTags, gatewayName) {
+			req = append(req, creconcile.Request{NamespacedName: types.NamespacedName{Namespace: l7Api.Namespace, Name: l7Api.Name}})
+		}
+	}
+	return req
+}
+
+// gatewayPodPredicate ignores pods and Deployments that do not belong to a Gateway and
+// Gateway pod and Deployment updates that do not change where L7Apis can be deployed
+func gatewayPodPredicate() predicate.Predicate {
+	return predicate.And(predicate.NewPredicateFuncs(func(o client.Object) bool {
+		_, ok := util.DefaultLabelsName(o.GetLabels())
+		return ok
+	}), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			switch newObj := e.ObjectNew.(type) {
+			case *corev1.Pod:
+				oldObj, ok := e.ObjectOld.(*corev1.Pod)
+				if !ok {
+					return true
+				}
+				return oldObj.Status.PodIP != newObj.Status.PodIP || gatewayReady(oldObj) != gatewayReady(newObj)
+			case *appsv1.Deployment:
+				oldObj, ok := e.ObjectOld.(*appsv1.Deployment)
+				if !ok {
+					return true
+				}
+				return oldObj.Status.ReadyReplicas != newObj.Status.ReadyReplicas || oldObj.Status.Replicas != newObj.Status.Replicas
+			}
+			return true
+		},
+	})
+}
+
+func gatewayReady(pod *corev1.Pod) bool {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == "gateway" {
+			return containerStatus.Ready
+		}
+	}
+	return false
+}
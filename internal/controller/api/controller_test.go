@@ -0,0 +1,47 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/caapim/layer7-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestGatewayPodPredicate(t *testing.T) {
+	gatewayPod := func(labels map[string]string, ready bool) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ssg-0", Namespace: "default", Labels: labels},
+			Status:     corev1.PodStatus{PodIP: "10.0.0.1", ContainerStatuses: []corev1.ContainerStatus{{Name: "gateway", Ready: ready}}},
+		}
+	}
+	labels := util.DefaultLabels("ssg", map[string]string{})
+	p := gatewayPodPredicate()
+
+	tests := []struct {
+		name   string
+		oldPod *corev1.Pod
+		newPod *corev1.Pod
+		want   bool
+	}{
+		{name: "gateway pod becomes ready", oldPod: gatewayPod(labels, false), newPod: gatewayPod(labels, true), want: true},
+		{name: "gateway pod unchanged", oldPod: gatewayPod(labels, true), newPod: gatewayPod(labels, true), want: false},
+		{name: "pod without gateway labels", oldPod: gatewayPod(map[string]string{"app": "ssg"}, false), newPod: gatewayPod(map[string]string{"app": "ssg"}, true), want: false},
+		{name: "pod managed by another operator", oldPod: gatewayPod(map[string]string{"app.kubernetes.io/name": "ssg"}, false), newPod: gatewayPod(map[string]string{"app.kubernetes.io/name": "ssg"}, true), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Update(event.UpdateEvent{ObjectOld: tt.oldPod, ObjectNew: tt.newPod}); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !p.Create(event.CreateEvent{Object: gatewayPod(labels, false)}) {
+		t.Errorf("expected new gateway pods to be watched")
+	}
+	if p.Create(event.CreateEvent{Object: gatewayPod(nil, false)}) {
+		t.Errorf("expected pods without gateway labels to be ignored")
+	}
+}
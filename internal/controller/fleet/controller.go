@@ -0,0 +1,116 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+
+package fleet
+
+import (
+	"context"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	"github.com/caapim/layer7-operator/pkg/fleet/reconcile"
+	"github.com/caapim/layer7-operator/pkg/util"
+	"go.opentelemetry.io/otel/attribute"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	creconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/go-logr/logr"
+)
+
+// GatewayFleetReconciler reconciles a GatewayFleet object
+type GatewayFleetReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Platform string
+}
+
+func (r *GatewayFleetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := util.StartSpan(ctx, "GatewayFleetReconciler.Reconcile", attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer span.End()
+
+	log := r.Log.WithValues("GatewayFleet", req.NamespacedName)
+	fleet := &securityv1alpha1.GatewayFleet{}
+	err := r.Get(ctx, req.NamespacedName, fleet)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	params := reconcile.Params{
+		Client:   r.Client,
+		Recorder: r.Recorder,
+		Scheme:   r.Scheme,
+		Log:      log,
+		Instance: fleet,
+		Platform: r.Platform,
+	}
+
+	syncInterval := 30 * time.Second
+	if fleet.Spec.SyncIntervalSeconds > 0 {
+		syncInterval = time.Duration(fleet.Spec.SyncIntervalSeconds) * time.Second
+	}
+
+	err = reconcile.Fleet(ctx, params)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: syncInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayFleetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&securityv1alpha1.GatewayFleet{}).
+		// new repository commits are rolled out without waiting for the sync interval
+		Watches(&securityv1.Repository{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, a client.Object) []creconcile.Request {
+				fleetList := &securityv1alpha1.GatewayFleetList{}
+				err := r.List(ctx, fleetList, client.InNamespace(a.GetNamespace()))
+				if err != nil {
+					return []creconcile.Request{}
+				}
+				req := []creconcile.Request{}
+				for _, fleet := range fleetList.Items {
+					if fleet.Spec.RepositoryReference.Name == a.GetName() {
+						req = append(req, creconcile.Request{NamespacedName: types.NamespacedName{Namespace: fleet.Namespace, Name: fleet.Name}})
+					}
+				}
+				return req
+			})).
+		Complete(r)
+}
@@ -0,0 +1,101 @@
+package fleet
+
+import (
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+var _ = Describe("GatewayFleet controller", func() {
+	Context("When a repository is rolled out to a member cluster", func() {
+		var (
+			namespace = "default"
+			fleetName = "fleet"
+			repoName  = "fleet-repo"
+			secret    = "member-kubeconfig"
+		)
+
+		BeforeEach(func() {
+			DeferCleanup(func() {
+				k8sClient.Delete(ctx, &securityv1alpha1.GatewayFleet{ObjectMeta: metav1.ObjectMeta{Name: fleetName, Namespace: namespace}})
+				k8sClient.Delete(ctx, &securityv1.Repository{ObjectMeta: metav1.ObjectMeta{Name: repoName, Namespace: namespace}})
+				k8sClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secret, Namespace: namespace}})
+				memberClient.Delete(ctx, &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: namespace}})
+			})
+		})
+
+		It("Should report the Gateways of the member cluster", func() {
+			By("Creating a Gateway in the member cluster")
+			gateway := &securityv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: namespace},
+				Spec: securityv1.GatewaySpec{
+					License: securityv1.License{Accept: true, SecretName: "ssg-license"},
+					App:     securityv1.App{Replicas: 1, Image: "docker.io/caapim/gateway:11.1.3"},
+				},
+			}
+			Expect(memberClient.Create(ctx, gateway)).Should(Succeed())
+
+			By("Creating a ready repository in the hub cluster")
+			repository := &securityv1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: repoName, Namespace: namespace},
+				Spec:       securityv1.RepositorySpec{Enabled: true, Type: securityv1.RepositoryTypeGit, Endpoint: "https://github.com/example/repo"},
+			}
+			Expect(k8sClient.Create(ctx, repository)).Should(Succeed())
+			repository.Status = securityv1.RepositoryStatus{Ready: true, Commit: "c1"}
+			Expect(k8sClient.Status().Update(ctx, repository)).Should(Succeed())
+
+			By("Creating a GatewayFleet with the member cluster kubeconfig")
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secret, Namespace: namespace},
+				Data:       map[string][]byte{"kubeconfig": memberKubeconfig},
+			})).Should(Succeed())
+			fleet := &securityv1alpha1.GatewayFleet{
+				ObjectMeta: metav1.ObjectMeta{Name: fleetName, Namespace: namespace},
+				Spec: securityv1alpha1.GatewayFleetSpec{
+					Enabled:             true,
+					RepositoryReference: securityv1.RepositoryReference{Name: repoName},
+					Clusters: []securityv1alpha1.RemoteCluster{{
+						Name:             "member",
+						Enabled:          true,
+						KubeconfigSecret: securityv1alpha1.KubeconfigSecret{Name: secret},
+						Gateways:         []string{"ssg", "missing"},
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, fleet)).Should(Succeed())
+
+			By("Reconciling the GatewayFleet")
+			reconciler := &GatewayFleetReconciler{
+				Client:   k8sClient,
+				Recorder: record.NewFakeRecorder(10),
+				Log:      zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)),
+				Scheme:   scheme.Scheme,
+			}
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: fleetName, Namespace: namespace}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: fleetName, Namespace: namespace}, fleet)).Should(Succeed())
+			Expect(fleet.Status.Commit).To(Equal("c1"))
+			Expect(fleet.Status.Ready).To(BeFalse())
+			Expect(fleet.Status.Clusters).To(HaveLen(1))
+
+			cluster := fleet.Status.Clusters[0]
+			Expect(cluster.Name).To(Equal("member"))
+			Expect(cluster.Reason).To(BeEmpty(), "the member cluster should be reachable with the kubeconfig secret")
+			Expect(cluster.Gateways).To(HaveLen(2))
+			Expect(cluster.Gateways[0].Name).To(Equal("ssg"))
+			Expect(cluster.Gateways[0].Reason).NotTo(ContainSubstring(`"ssg" not found`))
+			Expect(cluster.Gateways[0].Pods).To(Equal(0))
+			Expect(cluster.Gateways[1].Name).To(Equal("missing"))
+			Expect(cluster.Gateways[1].Reason).To(ContainSubstring(`"missing" not found`))
+		})
+	})
+})
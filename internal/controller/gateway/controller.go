@@ -37,6 +37,7 @@ import (
 	"time"
 
 	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	gatewayresources "github.com/caapim/layer7-operator/pkg/gateway"
 	"github.com/caapim/layer7-operator/pkg/gateway/reconcile"
 	"github.com/caapim/layer7-operator/pkg/util"
 	"github.com/go-logr/logr"
@@ -46,11 +47,13 @@ import (
 	"go.opentelemetry.io/otel/metric"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -79,11 +82,15 @@ type ReconcileOperations struct {
 }
 
 func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := util.StartSpan(ctx, "GatewayReconciler.Reconcile", attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer span.End()
+
 	log := r.Log.WithValues("gateway", req.NamespacedName)
 	gw := &securityv1.Gateway{}
 	err := r.Get(ctx, req.NamespacedName, gw)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
+			reconcile.RemoveGatewayMetrics(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
@@ -96,10 +103,14 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		{reconcile.ServiceAccount, "service account"},
 		{reconcile.Ingress, "ingress"},
 		{reconcile.Routes, "openshift routes"},
+		{reconcile.GatewayAPIRoutes, "gateway api routes"},
+		{reconcile.Monitoring, "monitoring"},
 		{reconcile.HorizontalPodAutoscaler, "horizontalPodAutoscaler"},
 		{reconcile.PodDisruptionBudget, "podDisruptionBudget"},
 		{reconcile.GatewayStatus, "gatewayStatus"},
 		{reconcile.ConfigMaps, "configMaps"},
+		{reconcile.OtkSchema, "otk schema"},
+		{reconcile.GatewayUpgrade, "gateway upgrade"},
 		{reconcile.Deployment, "deployment"},
 		{reconcile.ManagementPod, "management pod"},
 		{reconcile.ClusterProperties, "cluster properties"},
@@ -110,11 +121,11 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		{reconcile.ExternalCerts, "external certs"},
 	}
 
+	// scheduled jobs always run, the management pod leader Lease is renewed between reconciles
+	ops = append(ops, ReconcileOperations{reconcile.ScheduledJobs, "scheduled jobs"})
+
 	if gw.Spec.App.Otk.Enabled {
-		if gw.Spec.App.Otk.Type == securityv1.OtkTypeDMZ || gw.Spec.App.Otk.Type == securityv1.OtkTypeInternal {
-			ops = append(ops, ReconcileOperations{reconcile.ScheduledJobs, "scheduled jobs"})
-		}
-		if gw.Spec.App.Otk.Type == securityv1.OtkTypeSingle && !gw.Spec.App.Management.Database.Enabled {
+		if gw.Spec.App.Otk.Type == securityv1.OtkTypeSingle || gw.Spec.App.Otk.Type == securityv1.OtkTypeInternal {
 			ops = append(ops, ReconcileOperations{reconcile.OTKDatabaseMaintenanceTasks, "otk-db-maintenance-tasks"})
 		}
 	}
@@ -131,8 +142,11 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	start := time.Now()
 	for _, op := range ops {
 		r.muTasks.Lock()
-		err = op.Run(ctx, params)
+		opCtx, opSpan := util.StartSpan(ctx, op.Name)
+		err = op.Run(opCtx, params)
+		util.EndSpan(opSpan, err)
 		if err != nil {
+			util.RecordSpanError(span, err)
 			_ = captureMetrics(ctx, params, start, true, op.Name)
 			// record failures here
 			r.muTasks.Unlock()
@@ -143,6 +157,11 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	_ = captureMetrics(ctx, params, start, false, "")
 
+	// upgrades and blue/green rollouts wait for bundles to be applied to pods which does not trigger a reconcile
+	if reconcile.GatewayUpgradeInProgress(gw) || reconcile.BlueGreenInProgress(gw) {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
 	return ctrl.Result{RequeueAfter: 12 * time.Hour}, nil
 }
 
@@ -155,6 +174,7 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&networkingv1.Ingress{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&batchv1.Job{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&autoscalingv2.HorizontalPodAutoscaler{})
 
@@ -236,6 +256,9 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 						req = append(req, creconcile.Request{NamespacedName: types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name}})
 					}
 				}
+				if util.Contains(gatewayresources.RedisClientCertSecrets(&gateway), a.GetName()) {
+					req = append(req, creconcile.Request{NamespacedName: types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name}})
+				}
 			}
 			return req
 		}),
@@ -382,6 +405,26 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.Platform == "openshift" {
 		builder.Owns(&routev1.Route{})
 	}
+
+	// Kubernetes Gateway API routes are only watched if their CRDs are installed
+	for _, gvk := range gatewayresources.GatewayAPIRouteKinds {
+		if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			continue
+		}
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(gvk)
+		builder.Owns(route)
+	}
+
+	// Prometheus Operator monitors are only watched if their CRDs are installed
+	for _, gvk := range gatewayresources.MonitorKinds {
+		if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			continue
+		}
+		monitor := &unstructured.Unstructured{}
+		monitor.SetGroupVersionKind(gvk)
+		builder.Owns(monitor)
+	}
 	return builder.Complete(r)
 }
 
@@ -0,0 +1,144 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+
+package otkclient
+
+import (
+	"context"
+
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	"github.com/caapim/layer7-operator/pkg/otkclient/reconcile"
+	"github.com/caapim/layer7-operator/pkg/util"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	creconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/go-logr/logr"
+)
+
+const otkClientFinalizer = "security.brcmlabs.com/finalizer"
+
+// OtkClientReconciler reconciles a OtkClient object
+type OtkClientReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Platform string
+}
+
+func (r *OtkClientReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := util.StartSpan(ctx, "OtkClientReconciler.Reconcile", attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer span.End()
+
+	log := r.Log.WithValues("OtkClient", req.NamespacedName)
+	otkClient := &securityv1alpha1.OtkClient{}
+	err := r.Get(ctx, req.NamespacedName, otkClient)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	params := reconcile.Params{
+		Client:   r.Client,
+		Recorder: r.Recorder,
+		Scheme:   r.Scheme,
+		Log:      log,
+		Instance: otkClient,
+		Platform: r.Platform,
+	}
+
+	// the client is removed from the Gateway before the OtkClient is deleted
+	if otkClient.DeletionTimestamp != nil {
+		if !controllerutil.ContainsFinalizer(otkClient, otkClientFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		err = reconcile.Remove(ctx, params)
+		if err != nil {
+			log.Info("error removing otk client", "error", err.Error())
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(otkClient, otkClientFinalizer)
+		return ctrl.Result{}, r.Update(ctx, otkClient)
+	}
+
+	if !controllerutil.ContainsFinalizer(otkClient, otkClientFinalizer) {
+		controllerutil.AddFinalizer(otkClient, otkClientFinalizer)
+		err = r.Update(ctx, otkClient)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	err = reconcile.Register(ctx, params)
+	if err != nil {
+		log.Info("error registering otk client", "error", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OtkClientReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&securityv1alpha1.OtkClient{}).
+		// client secrets are rotated by updating the referenced secret, only secrets that are labelled as client secrets are watched
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.otkClientsForSecret),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+				_, ok := o.GetLabels()[reconcile.OtkClientSecretLabel]
+				return ok
+			}))).
+		Complete(r)
+}
+
+func (r *OtkClientReconciler) otkClientsForSecret(ctx context.Context, a client.Object) []creconcile.Request {
+	otkClientList := &securityv1alpha1.OtkClientList{}
+	err := r.List(ctx, otkClientList, client.InNamespace(a.GetNamespace()))
+	if err != nil {
+		return []creconcile.Request{}
+	}
+	req := []creconcile.Request{}
+	for _, otkClient := range otkClientList.Items {
+		if otkClient.Spec.ClientSecret.SecretName == a.GetName() {
+			req = append(req, creconcile.Request{NamespacedName: types.NamespacedName{Namespace: otkClient.Namespace, Name: otkClient.Name}})
+		}
+	}
+	return req
+}
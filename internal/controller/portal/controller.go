@@ -18,20 +18,33 @@ package portal
 
 import (
 	"context"
+	"slices"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
 	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
 	"github.com/caapim/layer7-operator/pkg/portal/reconcile"
+	"github.com/caapim/layer7-operator/pkg/util"
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	creconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const portalFinalizer = "security.brcmlabs.com/finalizer"
+
 // L7PortalReconciler reconciles a Gateway object
 type L7PortalReconciler struct {
 	client.Client
@@ -42,6 +55,8 @@ type L7PortalReconciler struct {
 }
 
 func (r *L7PortalReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := util.StartSpan(ctx, "L7PortalReconciler.Reconcile", attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer span.End()
 
 	log := r.Log.WithValues("portal", req.NamespacedName)
 
@@ -62,6 +77,26 @@ func (r *L7PortalReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		Instance: portal,
 	}
 
+	if portal.DeletionTimestamp != nil {
+		done, err := reconcile.Finalize(ctx, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// owned L7Apis are removed from the Portal before the L7Portal is deleted
+	if !controllerutil.ContainsFinalizer(portal, portalFinalizer) {
+		controllerutil.AddFinalizer(portal, portalFinalizer)
+		err = r.Update(ctx, portal)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	err = reconcile.Jobs(ctx, params)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -80,5 +115,69 @@ func (r *L7PortalReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&securityv1alpha1.L7Portal{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		// the enrollment bundle is applied to Gateway pods as soon as they are ready
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.l7PortalsForGateway),
+			builder.WithPredicates(gatewayPodReadyPredicate())).
 		Complete(r)
 }
+
+// l7PortalsForGateway maps a Gateway pod to the L7Portals whose deployment tags include the Gateway
+func (r *L7PortalReconciler) l7PortalsForGateway(ctx context.Context, a client.Object) []creconcile.Request {
+	gatewayName, ok := util.DefaultLabelsName(a.GetLabels())
+	if !ok {
+		return []creconcile.Request{}
+	}
+
+	l7PortalList := &securityv1alpha1.L7PortalList{}
+	err := r.List(ctx, l7PortalList, client.InNamespace(a.GetNamespace()))
+	if err != nil {
+		return []creconcile.Request{}
+	}
+	req := []creconcile.Request{}
+	for _, l7Portal := range l7PortalList.Items {
+		if slices.Contains(l7Portal.Spec.DeploymentTags, gatewayName) {
+			req = append(req, creconcile.Request{NamespacedName: types.NamespacedName{Namespace: l7Portal.Namespace, Name: l7Portal.Name}})
+		}
+	}
+	return req
+}
+
+// gatewayPodReadyPredicate only passes Gateway pods that have become ready
+func gatewayPodReadyPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if _, ok := util.DefaultLabelsName(e.ObjectNew.GetLabels()); !ok {
+				return false
+			}
+			oldPod, ok := e.ObjectOld.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			newPod, ok := e.ObjectNew.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			return !gatewayReady(oldPod) && gatewayReady(newPod)
+		},
+	}
+}
+
+func gatewayReady(pod *corev1.Pod) bool {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == "gateway" {
+			return containerStatus.Ready
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package portal
+
+import (
+	"testing"
+
+	"github.com/caapim/layer7-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestGatewayPodReadyPredicate(t *testing.T) {
+	gatewayPod := func(labels map[string]string, ready bool) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ssg-0", Namespace: "default", Labels: labels},
+			Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "gateway", Ready: ready}}},
+		}
+	}
+	labels := util.DefaultLabels("ssg", map[string]string{})
+	p := gatewayPodReadyPredicate()
+
+	tests := []struct {
+		name   string
+		oldPod *corev1.Pod
+		newPod *corev1.Pod
+		want   bool
+	}{
+		{name: "gateway pod becomes ready", oldPod: gatewayPod(labels, false), newPod: gatewayPod(labels, true), want: true},
+		{name: "gateway pod stays ready", oldPod: gatewayPod(labels, true), newPod: gatewayPod(labels, true), want: false},
+		{name: "gateway pod becomes not ready", oldPod: gatewayPod(labels, true), newPod: gatewayPod(labels, false), want: false},
+		{name: "pod without gateway labels", oldPod: gatewayPod(nil, false), newPod: gatewayPod(nil, true), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Update(event.UpdateEvent{ObjectOld: tt.oldPod, ObjectNew: tt.newPod}); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if p.Create(event.CreateEvent{Object: gatewayPod(labels, true)}) {
+		t.Errorf("expected new pods to be ignored until they are ready")
+	}
+}
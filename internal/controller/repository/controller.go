@@ -71,6 +71,9 @@ type ReconcileOperations struct {
 }
 
 func (r *RepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := util.StartSpan(ctx, "RepositoryReconciler.Reconcile", attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer span.End()
+
 	start := time.Now()
 	log := r.Log.WithValues("repository", req.NamespacedName)
 
@@ -109,8 +112,11 @@ func (r *RepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	for _, op := range ops {
-		err = op.Run(ctx, params)
+		opCtx, opSpan := util.StartSpan(ctx, op.Name)
+		err = op.Run(opCtx, params)
+		util.EndSpan(opSpan, err)
 		if err != nil {
+			util.RecordSpanError(span, err)
 			log.Error(err, fmt.Sprintf("failed to reconcile %s", op.Name))
 			_ = captureMetrics(ctx, params, start, req.NamespacedName.Namespace)
 			return ctrl.Result{}, err
@@ -33,6 +33,8 @@ import (
 
 	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
 	"github.com/caapim/layer7-operator/pkg/statestore/reconcile"
+	"github.com/caapim/layer7-operator/pkg/util"
+	"go.opentelemetry.io/otel/attribute"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
@@ -52,6 +54,9 @@ type L7StateStoreReconciler struct {
 }
 
 func (r *L7StateStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := util.StartSpan(ctx, "L7StateStoreReconciler.Reconcile", attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer span.End()
+
 	log := r.Log.WithValues("L7StateStore", req.NamespacedName)
 	stateStore := &securityv1alpha1.L7StateStore{}
 	err := r.Get(ctx, req.NamespacedName, stateStore)
@@ -1007,6 +1007,112 @@ func implodeBundle(path string, processNestedRepos bool) (Bundle, error) {
 	return bundle, nil
 }
 
+// explodeBundle writes each entity in the bundle to its own file in path using the layout that implodeBundle reads
+// existing entity folders are removed first so that entities which no longer exist are removed as well
+func explodeBundle(bundle Bundle, path string) (int, error) {
+	for _, folder := range entityFolderList {
+		err := os.RemoveAll(filepath.Join(path, folder))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	count := 0
+	written := map[string]bool{}
+	bundleVal := reflect.ValueOf(bundle)
+	for i := 0; i < bundleVal.NumField(); i++ {
+		field := bundleVal.Field(i)
+		if field.Kind() != reflect.Slice {
+			continue
+		}
+		fieldName := bundleVal.Type().Field(i).Name
+		entityType := strings.Split(bundleVal.Type().Field(i).Tag.Get("json"), ",")[0]
+
+		for j := 0; j < field.Len(); j++ {
+			entity := field.Index(j)
+			if entity.IsNil() {
+				continue
+			}
+			dir := filepath.Join(path, entityType)
+			suffix := ""
+			switch fieldName {
+			case "Policies":
+				dir = filepath.Join(path, "tree", entity.Elem().FieldByName("FolderPath").String())
+				suffix = ".policy"
+			case "Services":
+				dir = filepath.Join(path, "tree", entity.Elem().FieldByName("FolderPath").String())
+				suffix = ".service"
+			}
+
+			name := explodedFileName(entity.Elem(), fieldName)
+			file := filepath.Join(dir, name+suffix+".json")
+			for n := 2; written[file]; n++ {
+				file = filepath.Join(dir, fmt.Sprintf("%s-%d%s.json", name, n, suffix))
+			}
+			written[file] = true
+
+			entityBytes, err := json.MarshalIndent(entity.Interface(), "", "  ")
+			if err != nil {
+				return count, err
+			}
+			err = os.MkdirAll(dir, 0755)
+			if err != nil {
+				return count, err
+			}
+			err = os.WriteFile(file, entityBytes, 0644)
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// explodedFileName returns a file name for an entity that is safe to use in any directory
+// characters that have a meaning to parseEntity like '.' are replaced
+func explodedFileName(e reflect.Value, fieldName string) string {
+	var primaryFields []string
+	switch fieldName {
+	case "HttpConfigurations":
+		primaryFields = []string{"Host", "Port"}
+	case "Keys":
+		primaryFields = []string{"Alias"}
+	case "Schemas", "Dtds":
+		primaryFields = []string{"SystemId"}
+	case "CustomKeyValues":
+		primaryFields = []string{"Key"}
+	default:
+		primaryFields = []string{"Name"}
+	}
+
+	var parts []string
+	for _, field := range primaryFields {
+		if f := e.FieldByName(field); f.IsValid() {
+			switch f.Kind() {
+			case reflect.String:
+				if f.String() != "" {
+					parts = append(parts, f.String())
+				}
+			case reflect.Int, reflect.Int32, reflect.Int64:
+				if f.Int() != 0 {
+					parts = append(parts, fmt.Sprintf("%d", f.Int()))
+				}
+			}
+		}
+	}
+
+	name := strings.Join(parts, "-")
+	if name == "" {
+		if f := e.FieldByName("Goid"); f.IsValid() && f.String() != "" {
+			name = f.String()
+		} else {
+			name = strings.ToLower(fieldName)
+		}
+	}
+	return regexp.MustCompile(`[^a-zA-Z0-9_-]`).ReplaceAllString(name, "_")
+}
+
 func DetectGraphmanFolders(path string) (projects []string, err error) {
 	topLevelProjects := []string{}
 	err = filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
@@ -1101,3 +1207,209 @@ func deleteGenericBundle(
 	)
 	return &data_, err_
 }
+
+// summaryFields are the fields that identify an entity and its configuration checksum
+// these are used to compare a desired bundle with what is on the gateway
+var summaryFields = map[string]string{
+	"webApiServices":                      "name resolutionPath checksum",
+	"internalWebApiServices":              "name resolutionPath checksum",
+	"soapServices":                        "name resolutionPath checksum",
+	"internalSoapServices":                "name resolutionPath checksum",
+	"policyFragments":                     "name checksum",
+	"encassConfigs":                       "name checksum",
+	"clusterProperties":                   "name checksum",
+	"jdbcConnections":                     "name checksum",
+	"trustedCerts":                        "name thumbprintSha1 checksum",
+	"schemas":                             "systemId checksum",
+	"dtds":                                "systemId checksum",
+	"fips":                                "name checksum",
+	"ldaps":                               "name checksum",
+	"internalGroups":                      "name checksum",
+	"fipGroups":                           "name checksum",
+	"internalUsers":                       "name checksum",
+	"fipUsers":                            "name checksum",
+	"secrets":                             "name checksum",
+	"keys":                                "alias keystoreId checksum",
+	"cassandraConnections":                "name checksum",
+	"jmsDestinations":                     "name checksum",
+	"globalPolicies":                      "name tag checksum",
+	"backgroundTaskPolicies":              "name checksum",
+	"scheduledTasks":                      "name checksum",
+	"serverModuleFiles":                   "name checksum",
+	"smConfigs":                           "name checksum",
+	"activeConnectors":                    "name checksum",
+	"emailListeners":                      "name checksum",
+	"listenPorts":                         "name port checksum",
+	"administrativeUserAccountProperties": "name checksum",
+	"revocationCheckPolicies":             "name checksum",
+	"logSinks":                            "name checksum",
+	"httpConfigurations":                  "host port checksum",
+	"customKeyValues":                     "key checksum",
+	"folders":                             "name checksum",
+	"federatedIdps":                       "name checksum",
+	"federatedGroups":                     "name checksum",
+	"federatedUsers":                      "name checksum",
+	"internalIdps":                        "name checksum",
+	"ldapIdps":                            "name checksum",
+	"simpleLdapIdps":                      "name checksum",
+	"policyBackedIdps":                    "name checksum",
+	"policies":                            "name tag checksum",
+	"services":                            "name resolutionPath checksum",
+	"roles":                               "name tag checksum",
+	"genericEntities":                     "name checksum",
+	"auditConfigurations":                 "name checksum",
+}
+
+// summaryQuery builds a query for the summary of each entity type that is present in the bundle
+// an empty string is returned if there is nothing to query
+func summaryQuery(bundle Bundle) string {
+	var selections []string
+	bundleVal := reflect.ValueOf(bundle)
+	for i := 0; i < bundleVal.NumField(); i++ {
+		field := bundleVal.Field(i)
+		if field.Kind() != reflect.Slice || field.Len() == 0 {
+			continue
+		}
+		entityType := strings.Split(bundleVal.Type().Field(i).Tag.Get("json"), ",")[0]
+		if fields, ok := summaryFields[entityType]; ok {
+			selections = append(selections, "    "+entityType+" {"+fields+"}")
+		}
+	}
+	if len(selections) == 0 {
+		return ""
+	}
+	return "query bundleSummary {\n" + strings.Join(selections, "\n") + "\n}"
+}
+
+func bundleSummary(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	query string,
+) (*Bundle, error) {
+	req_ := &graphql.Request{
+		OpName: "bundleSummary",
+		Query:  query,
+	}
+	var err_ error
+
+	var data_ Bundle
+	resp_ := &graphql.Response{Data: &data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+	return &data_, err_
+}
+
+// exportFields are the fields that are required to recreate each entity type on another gateway
+// deprecated entity types are exported as policies and services instead
+var exportFields = map[string]string{
+	"encassConfigs":                       "goid guid name description policyName encassArgs {name type ordinal guiPrompt guiLabel} encassResults {name type} properties {name value} checksum",
+	"clusterProperties":                   "goid name value description hiddenProperty checksum",
+	"jdbcConnections":                     "goid name driverClass jdbcUrl enabled username minPoolSize maxPoolSize properties {name value} checksum",
+	"trustedCerts":                        "goid name certBase64 verifyHostname trustAnchor trustedFor revocationCheckPolicyType revocationCheckPolicyName subjectDn notBefore notAfter thumbprintSha1 checksum",
+	"schemas":                             "goid systemId targetNs description content checksum",
+	"dtds":                                "goid systemId publicId description content checksum",
+	"internalGroups":                      "name goid description checksum",
+	"internalUsers":                       "name goid replaceGroupMemberships memberOf {name goid description checksum} login certBase64 sshPublicKey firstName lastName email enabled checksum",
+	"secrets":                             "name secretType goid checksum variableReferencable description",
+	"keys":                                "keystoreId alias goid subjectDn keyType usageTypes certChain checksum",
+	"cassandraConnections":                "goid name keyspace contactPoints port username securePasswordName compression sslEnabled cipherSuites enabled properties {name value} checksum",
+	"jmsDestinations":                     "goid connectionGoid name direction providerType initialContextFactoryClassname connectionFactoryName jndiUrl jndiUsername jndiSslDetails {sslEnabled sslForAuthenticationOnly sslVerifyServerCertificate sslVerifyServerHostname sslClientKeyAlias} destinationType destinationName destinationUsername destinationSslDetails {sslEnabled sslForAuthenticationOnly sslVerifyServerCertificate sslVerifyServerHostname sslClientKeyAlias} template enabled properties {name value} checksum",
+	"scheduledTasks":                      "goid name policyName jobType cronExpression executeOnSingleNode executeOnCreation executionDate status runAsUser runAsUserProviderName checksum",
+	"serverModuleFiles":                   "goid name moduleType moduleSha256 signature signerCertBase64 properties {name value} checksum",
+	"smConfigs":                           "goid name enabled agentHost agentIP agentHostConfig cryptoMode ipCheckEnabled updateSSOToken clusterFailoverThreshold nonClusterFailover username securePasswordName properties {name value} checksum",
+	"activeConnectors":                    "goid name enabled connectorType hardwiredServiceName properties {name value} advancedProperties {name value} checksum",
+	"emailListeners":                      "goid name enabled hostname port serverType sslEnabled deleteOnReceive folder pollInterval username hardwiredServiceName sizeLimit properties {name value} checksum",
+	"listenPorts":                         "goid name enabled protocol port hardwiredServiceName enabledFeatures tlsSettings {clientAuthentication keystoreId keyAlias tlsVersions cipherSuites useCipherSuitesOrder} properties {name value} checksum",
+	"administrativeUserAccountProperties": "goid name value checksum",
+	"passwordPolicies":                    "goid checksum forcePasswordChangeNewUser noRepeatingCharacters minPasswordLength maxPasswordLength upperMinimum lowerMinimum numberMinimum symbolMinimum nonNumericMinimum charDiffMinimum repeatFrequency passwordExpiry allowableChangesPerDay",
+	"revocationCheckPolicies":             "goid name checksum defaultPolicy defaultSuccess continueOnServerUnavailable revocationCheckPolicyItems {type url allowIssuerSignature nonceUsage signerThumbprintSha1s}",
+	"logSinks":                            "goid name description type enabled severity categories syslogHosts filters {type values} properties {name value} checksum",
+	"httpConfigurations":                  "goid host port protocol path username securePasswordName ntlmHost ntlmDomain tlsVersion tlsKeyUse tlsKeystoreId tlsKeyAlias tlsCipherSuites connectTimeout readTimeout followRedirects proxyUse proxyConfiguration {host port username securePasswordName} checksum",
+	"customKeyValues":                     "goid key value checksum",
+	"serviceResolutionConfigs":            "goid checksum resolutionPathRequired resolutionPathCaseSensitive useL7OriginalUrl useServiceGoid useSoapAction useSoapBodyChildNamespace",
+	"folders":                             "goid name path checksum",
+	"federatedIdps":                       "name goid supportsSAML supportsX509 certValidation trustedCerts {name subjectDn thumbprintSha1} checksum",
+	"federatedGroups":                     "name goid providerName description checksum",
+	"federatedUsers":                      "name goid providerName replaceGroupMemberships memberOf {name goid description providerName checksum} login subjectDn certBase64 firstName lastName email checksum",
+	"internalIdps":                        "goid name checksum certValidation",
+	"ldapIdps":                            "goid name checksum ldapType serverUrls useSslClientAuth sslClientKeyAlias searchBase bindDn writable writeBase specifiedAttributes userMappings {objClass nameAttrName loginAttrName passwdAttrName firstNameAttrName lastNameAttrName emailNameAttrName kerberosAttrName kerberosEnterpriseAttrName userCertAttrName passwdType {val}} groupMappings {objClass nameAttrName memberAttrName memberStrategy {val}} ntlmProperties {name value} properties {name value}",
+	"simpleLdapIdps":                      "goid name checksum serverUrls useSslClientAuth sslClientKeyAlias bindDnPatternPrefix bindDnPatternSuffix properties {name value}",
+	"policyBackedIdps":                    "goid name checksum authPolicyName defaultRoleName properties {name value}",
+	"policies":                            "goid folderPath name guid policy {xml} soap policyType tag subTag checksum",
+	"services":                            "goid guid name resolutionPath resolvers {soapActions baseUri resolutionPath} serviceType checksum enabled folderPath soapVersion methodsAllowed tracingEnabled wssProcessingEnabled laxResolution properties {name value} wsdl wsdlUrl wsdlResources {uri content} policy {xml}",
+	"roles":                               "goid name checksum roleType description tag replaceAssignees userAssignees {name login subjectDn providerName providerType} groupAssignees {name subjectDn providerName providerType}",
+	"genericEntities":                     "goid name checksum description valueXml enabled entityClassName",
+	"auditConfigurations":                 "goid name lookupPolicyName checksum alwaysSaveInternal sinkPolicyName ftpConfig {host port timeout user directory verifyServerCert security enabled}",
+}
+
+// exportSecretFields are only exported when an encryption passphrase is provided
+var exportSecretFields = map[string]string{
+	"secrets":             "secret",
+	"keys":                "p12 pem",
+	"internalUsers":       "password",
+	"jdbcConnections":     "password",
+	"ldapIdps":            "bindPassword",
+	"emailListeners":      "password",
+	"jmsDestinations":     "jndiPassword destinationPassword",
+	"smConfigs":           "agentSecret",
+	"auditConfigurations": "ftpConfig {password}",
+}
+
+// exportQuery builds a query that exports every entity type in exportFields
+// secret values are only included if includeSecrets is true
+func exportQuery(includeSecrets bool) string {
+	var selections []string
+	bundleType := reflect.TypeOf(Bundle{})
+	for i := 0; i < bundleType.NumField(); i++ {
+		entityType := strings.Split(bundleType.Field(i).Tag.Get("json"), ",")[0]
+		fields, ok := exportFields[entityType]
+		if !ok {
+			continue
+		}
+		if secretFields, ok := exportSecretFields[entityType]; ok && includeSecrets {
+			fields = fields + " " + secretFields
+		}
+		selections = append(selections, "    "+entityType+" {"+fields+"}")
+	}
+	return "query bundleExport {\n" + strings.Join(selections, "\n") + "\n}"
+}
+
+// entityFieldValue returns the string value of the field with the json name fieldName
+func entityFieldValue(e reflect.Value, fieldName string) string {
+	e = reflect.Indirect(e)
+	if e.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < e.NumField(); i++ {
+		if strings.Split(e.Type().Field(i).Tag.Get("json"), ",")[0] == fieldName {
+			return fmt.Sprint(e.Field(i).Interface())
+		}
+	}
+	return ""
+}
+
+func bundleExport(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	query string,
+) (*Bundle, error) {
+	req_ := &graphql.Request{
+		OpName: "bundleExport",
+		Query:  query,
+	}
+	var err_ error
+
+	var data_ Bundle
+	resp_ := &graphql.Response{Data: &data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+	return &data_, err_
+}
@@ -1147,3 +1147,131 @@ func RemoveDuplicates(bundleBytes []byte) ([]byte, error) {
 
 	return result, nil
 }
+
+type DriftReason string
+
+const (
+	DriftReasonMissing  DriftReason = "missing"
+	DriftReasonModified DriftReason = "modified"
+)
+
+// DriftedEntity is an entity from a desired bundle that is missing or has been modified on a gateway
+type DriftedEntity struct {
+	Type   string      `json:"type"`
+	Name   string      `json:"name"`
+	Reason DriftReason `json:"reason"`
+}
+
+// CalculateDrift compares the desired state with the live state of a gateway
+// Parameters:
+// - live: entity summaries (identifying fields and checksum) retrieved from the gateway
+// - desired: the desired state
+// Returns:
+// - drifted: entities in desired that are missing from live or have a different checksum
+// - error: any error that occurred during processing
+// Entities that only exist on the gateway are not reported, checksums are compared when the desired entity has one
+func CalculateDrift(live Bundle, desired Bundle) (drifted []DriftedEntity, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic during drift calculation: %v", r)
+			drifted = nil
+		}
+	}()
+
+	getID := func(e reflect.Value, fieldName string) string {
+		if e.Kind() == reflect.Ptr {
+			e = e.Elem()
+		}
+
+		var primaryFields []string
+		switch fieldName {
+		case "Services", "Policies", "PolicyFragments":
+			primaryFields = []string{"Name"}
+		case "HttpConfigurations":
+			primaryFields = []string{"Host", "Port"}
+		case "Keys":
+			primaryFields = []string{"Alias"}
+		case "TrustedCerts":
+			primaryFields = []string{"ThumbprintSha1"}
+		case "Schemas", "Dtds":
+			primaryFields = []string{"SystemId"}
+		case "CustomKeyValues":
+			primaryFields = []string{"Key"}
+		default:
+			primaryFields = []string{"Name"}
+		}
+
+		var parts []string
+		for _, field := range primaryFields {
+			if f := e.FieldByName(field); f.IsValid() {
+				var val string
+				switch f.Kind() {
+				case reflect.String:
+					val = f.String()
+				case reflect.Int, reflect.Int32, reflect.Int64:
+					if f.Int() != 0 {
+						val = fmt.Sprintf("%d", f.Int())
+					}
+				}
+				if val != "" {
+					parts = append(parts, val)
+				}
+			}
+		}
+		return strings.Join(parts, "|")
+	}
+
+	getChecksum := func(e reflect.Value) string {
+		if e.Kind() == reflect.Ptr {
+			e = e.Elem()
+		}
+		if f := e.FieldByName("Checksum"); f.IsValid() && f.Kind() == reflect.String {
+			return f.String()
+		}
+		return ""
+	}
+
+	desiredVal := reflect.ValueOf(desired)
+	liveVal := reflect.ValueOf(live)
+
+	for i := 0; i < desiredVal.NumField(); i++ {
+		fieldName := desiredVal.Type().Field(i).Name
+		if fieldName == "Properties" {
+			continue
+		}
+
+		desiredField := desiredVal.Field(i)
+		liveField := liveVal.Field(i)
+		if desiredField.Kind() != reflect.Slice || desiredField.Len() == 0 {
+			continue
+		}
+
+		entityType := strings.Split(desiredVal.Type().Field(i).Tag.Get("json"), ",")[0]
+
+		liveChecksums := make(map[string]string)
+		for j := 0; j < liveField.Len(); j++ {
+			entity := liveField.Index(j)
+			if id := getID(entity, fieldName); id != "" {
+				liveChecksums[id] = getChecksum(entity)
+			}
+		}
+
+		for j := 0; j < desiredField.Len(); j++ {
+			entity := desiredField.Index(j)
+			id := getID(entity, fieldName)
+			if id == "" {
+				continue
+			}
+			liveChecksum, exists := liveChecksums[id]
+			if !exists {
+				drifted = append(drifted, DriftedEntity{Type: entityType, Name: id, Reason: DriftReasonMissing})
+				continue
+			}
+			if desiredChecksum := getChecksum(entity); desiredChecksum != "" && desiredChecksum != liveChecksum {
+				drifted = append(drifted, DriftedEntity{Type: entityType, Name: id, Reason: DriftReasonModified})
+			}
+		}
+	}
+
+	return drifted, nil
+}
@@ -425,3 +425,62 @@ func TestRemoveDuplicates_RemovesDuplicatesByGoid(t *testing.T) {
 		t.Errorf("Expected 1 service after deduplication by goid, got %d", len(resultBundle.Services))
 	}
 }
+
+func TestCalculateDrift_MissingAndModified(t *testing.T) {
+	desired := Bundle{
+		Services: []*L7ServiceInput{
+			{Name: "api1", ResolutionPath: "/api1", Checksum: "aaa"},
+			{Name: "api2", ResolutionPath: "/api2", Checksum: "bbb"},
+			{Name: "api3", ResolutionPath: "/api3", Checksum: "ccc"},
+		},
+		ClusterProperties: []*ClusterPropertyInput{
+			{Name: "cwp1", Value: "value1"},
+		},
+	}
+
+	live := Bundle{
+		Services: []*L7ServiceInput{
+			{Name: "api1", ResolutionPath: "/api1", Checksum: "aaa"},
+			{Name: "api2", ResolutionPath: "/api2", Checksum: "changed"},
+			{Name: "unmanaged", ResolutionPath: "/unmanaged", Checksum: "ddd"},
+		},
+		ClusterProperties: []*ClusterPropertyInput{
+			{Name: "cwp1", Checksum: "eee"},
+		},
+	}
+
+	drifted, err := CalculateDrift(live, desired)
+	if err != nil {
+		t.Fatalf("CalculateDrift failed: %v", err)
+	}
+
+	if len(drifted) != 2 {
+		t.Fatalf("Expected 2 drifted entities, got %d: %v", len(drifted), drifted)
+	}
+
+	expected := map[string]DriftReason{"api2": DriftReasonModified, "api3": DriftReasonMissing}
+	for _, d := range drifted {
+		if d.Type != "services" {
+			t.Errorf("Expected type 'services', got '%s'", d.Type)
+		}
+		if expected[d.Name] != d.Reason {
+			t.Errorf("Expected %s to be %s, got %s", d.Name, expected[d.Name], d.Reason)
+		}
+	}
+}
+
+func TestSummaryQuery_OnlyIncludesBundleEntityTypes(t *testing.T) {
+	if summaryQuery(Bundle{}) != "" {
+		t.Errorf("Expected empty query for empty bundle")
+	}
+
+	query := summaryQuery(Bundle{
+		Services:           []*L7ServiceInput{{Name: "api1"}},
+		HttpConfigurations: []*HttpConfigurationInput{{Host: "example.com", Port: 443}},
+	})
+
+	expected := "query bundleSummary {\n    httpConfigurations {host port checksum}\n    services {name resolutionPath checksum}\n}"
+	if query != expected {
+		t.Errorf("Expected query %q, got %q", expected, query)
+	}
+}
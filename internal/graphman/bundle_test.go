@@ -29,6 +29,7 @@ package graphman
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -236,3 +237,75 @@ func TestConcatBundle_DuplicatesAcrossFolders(t *testing.T) {
 		t.Errorf("Expected folder path '/folder2' (latest), got '%s'", finalResult.Services[0].FolderPath)
 	}
 }
+
+func TestExplodeBundle_ImplodesToSameEntities(t *testing.T) {
+	path := t.TempDir()
+	bundle := Bundle{
+		ClusterProperties: []*ClusterPropertyInput{
+			{Name: "cluster.hostname", Value: "gateway.example.com"},
+		},
+		Keys: []*KeyInput{
+			{Alias: "ssl", KeystoreId: "00000000000000000000000000000002"},
+		},
+		Policies: []*L7PolicyInput{
+			{Name: "fragment", FolderPath: "/fragments", Policy: &PolicyInput{Xml: "<wsp:Policy/>"}},
+		},
+		Services: []*L7ServiceInput{
+			{Name: "api1", ResolutionPath: "/api1", FolderPath: "/apis", Policy: &PolicyInput{Xml: "<wsp:Policy/>"}},
+			{Name: "api1", ResolutionPath: "/api1/v2", FolderPath: "/apis", Policy: &PolicyInput{Xml: "<wsp:Policy/>"}},
+		},
+	}
+
+	count, err := explodeBundle(bundle, path)
+	if err != nil {
+		t.Fatalf("explodeBundle failed: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("Expected 5 entities to be written, got %d", count)
+	}
+
+	result, err := implodeBundle(path, false)
+	if err != nil {
+		t.Fatalf("implodeBundle failed: %v", err)
+	}
+	if len(result.ClusterProperties) != 1 || result.ClusterProperties[0].Value != "gateway.example.com" {
+		t.Errorf("Expected cluster property to be restored, got %v", result.ClusterProperties)
+	}
+	if len(result.Keys) != 1 || result.Keys[0].Alias != "ssl" {
+		t.Errorf("Expected key to be restored, got %v", result.Keys)
+	}
+	if len(result.Policies) != 1 || result.Policies[0].Policy.Xml != "<wsp:Policy/>" {
+		t.Errorf("Expected policy to be restored, got %v", result.Policies)
+	}
+	if len(result.Services) != 2 {
+		t.Errorf("Expected services with the same name to be written to separate files, got %d", len(result.Services))
+	}
+
+	// entities that have been removed from the gateway are removed on the next export
+	count, err = explodeBundle(Bundle{ClusterProperties: bundle.ClusterProperties}, path)
+	if err != nil {
+		t.Fatalf("explodeBundle failed: %v", err)
+	}
+	result, err = implodeBundle(path, false)
+	if err != nil {
+		t.Fatalf("implodeBundle failed: %v", err)
+	}
+	if count != 1 || len(result.Services) != 0 || len(result.Keys) != 0 {
+		t.Errorf("Expected only the cluster property to remain, got %d services and %d keys", len(result.Services), len(result.Keys))
+	}
+}
+
+func TestExportQuery_SecretsRequireEncryption(t *testing.T) {
+	query := exportQuery(false)
+	if strings.Contains(query, "p12") || strings.Contains(query, "bindPassword") {
+		t.Errorf("Expected secret fields to be excluded without an encryption passphrase")
+	}
+	if strings.Contains(query, "webApiServices") {
+		t.Errorf("Expected deprecated entity types to be excluded")
+	}
+
+	query = exportQuery(true)
+	if !strings.Contains(query, "keys {keystoreId alias goid subjectDn keyType usageTypes certChain checksum p12 pem}") {
+		t.Errorf("Expected secret fields to be included with an encryption passphrase, got %s", query)
+	}
+}
@@ -0,0 +1,263 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package graphman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+)
+
+// bundlePhase is a group of entity types that are applied together
+// phases are applied in order so that entities exist before the entities that reference them
+type bundlePhase struct {
+	name     string
+	entities []string
+}
+
+// bundlePhases is the order that a large bundle is applied in
+// entity types that are not listed are applied in the configuration phase
+var bundlePhases = []bundlePhase{
+	{name: "folders", entities: []string{"folders"}},
+	{name: "keys", entities: []string{"keys", "trustedCerts", "secrets"}},
+	{name: "configuration"},
+	{name: "policies", entities: []string{"policyFragments", "encassConfigs", "policies", "globalPolicies", "backgroundTaskPolicies"}},
+	{name: "services", entities: []string{"services", "webApiServices", "internalWebApiServices", "soapServices", "internalSoapServices"}},
+	{name: "dependents", entities: []string{"scheduledTasks", "listenPorts", "roles"}},
+}
+
+// bundleChunk is part of a bundle that is sent in a single Graphman mutation
+type bundleChunk struct {
+	phase    string
+	entities []string
+	bundle   Bundle
+}
+
+func (c bundleChunk) String() string {
+	return c.phase + ": " + strings.Join(c.entities, ",")
+}
+
+// chunkBundle splits a bundle into dependency ordered chunks of approximately maxSize bytes
+// bundles that are smaller than maxSize are returned as a single chunk
+// an entity that is larger than maxSize on its own is sent in its own chunk
+func chunkBundle(bundle Bundle, maxSize int) ([]bundleChunk, error) {
+	bundleBytes, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := bundleFields()
+	if maxSize <= 0 || len(bundleBytes) <= maxSize {
+		chunk := bundleChunk{phase: "bundle", bundle: bundle}
+		v := reflect.ValueOf(bundle)
+		for _, phase := range phaseEntities(fields) {
+			for _, entity := range phase.entities {
+				if v.Field(fields[entity]).Len() > 0 {
+					chunk.entities = append(chunk.entities, entity)
+				}
+			}
+		}
+		return []bundleChunk{chunk}, nil
+	}
+
+	chunks := []bundleChunk{}
+	v := reflect.ValueOf(bundle)
+	for _, phase := range phaseEntities(fields) {
+		chunk := bundleChunk{phase: phase.name}
+		size := 0
+		for _, entity := range phase.entities {
+			entities := v.Field(fields[entity])
+			for i := 0; i < entities.Len(); i++ {
+				entityBytes, err := json.Marshal(entities.Index(i).Interface())
+				if err != nil {
+					return nil, err
+				}
+				if size > 0 && size+len(entityBytes) > maxSize {
+					chunks = append(chunks, chunk)
+					chunk = bundleChunk{phase: phase.name}
+					size = 0
+				}
+				f := reflect.ValueOf(&chunk.bundle).Elem().Field(fields[entity])
+				f.Set(reflect.Append(f, entities.Index(i)))
+				if !slices.Contains(chunk.entities, entity) {
+					chunk.entities = append(chunk.entities, entity)
+				}
+				size += len(entityBytes)
+			}
+		}
+		if size > 0 {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	if len(chunks) == 0 {
+		return []bundleChunk{{phase: "bundle", bundle: bundle}}, nil
+	}
+
+	if bundle.Properties != nil {
+		chunkMappings(bundle.Properties, chunks)
+	}
+	return chunks, nil
+}
+
+// bundleFields maps the json name of each entity type to its Bundle field index
+func bundleFields() map[string]int {
+	fields := map[string]int{}
+	t := reflect.TypeOf(Bundle{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "properties" {
+			continue
+		}
+		fields[name] = i
+	}
+	return fields
+}
+
+// phaseEntities adds entity types that are not explicitly ordered to the configuration phase
+func phaseEntities(fields map[string]int) []bundlePhase {
+	ordered := map[string]bool{}
+	for _, phase := range bundlePhases {
+		for _, entity := range phase.entities {
+			ordered[entity] = true
+		}
+	}
+	unordered := []string{}
+	for entity := range fields {
+		if !ordered[entity] {
+			unordered = append(unordered, entity)
+		}
+	}
+	slices.SortFunc(unordered, func(a, b string) int { return fields[a] - fields[b] })
+
+	phases := make([]bundlePhase, 0, len(bundlePhases))
+	for _, phase := range bundlePhases {
+		if phase.name == "configuration" {
+			phase.entities = unordered
+		}
+		phases = append(phases, phase)
+	}
+	return phases
+}
+
+// chunkMappings copies the mappings of each entity type into the chunks that contain that entity type
+// mappings for entity types that are not in the bundle are sent with the first chunk
+func chunkMappings(properties *BundleProperties, chunks []bundleChunk) {
+	mappingFields := map[string]int{}
+	t := reflect.TypeOf(BundleMappings{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		mappingFields[name] = i
+	}
+
+	mappings := reflect.ValueOf(properties.Mappings)
+	chunked := map[string]bool{}
+	for i := range chunks {
+		chunks[i].bundle.Properties = &BundleProperties{Meta: properties.Meta, DefaultAction: properties.DefaultAction}
+		target := reflect.ValueOf(&chunks[i].bundle.Properties.Mappings).Elem()
+		for _, entity := range chunks[i].entities {
+			if index, ok := mappingFields[entity]; ok {
+				target.Field(index).Set(mappings.Field(index))
+				chunked[entity] = true
+			}
+		}
+	}
+
+	target := reflect.ValueOf(&chunks[0].bundle.Properties.Mappings).Elem()
+	for entity, index := range mappingFields {
+		if !chunked[entity] && mappings.Field(index).Len() > 0 {
+			target.Field(index).Set(mappings.Field(index))
+		}
+	}
+}
+
+// mergeDetailedStatus appends the detailed status of each entity type in src to dst
+func mergeDetailedStatus(dst *BundleResponseDetailedStatus, src *BundleResponseDetailedStatus) {
+	d := reflect.ValueOf(dst).Elem()
+	s := reflect.ValueOf(src).Elem()
+	for i := 0; i < s.NumField(); i++ {
+		if s.Field(i).IsNil() {
+			continue
+		}
+		if d.Field(i).IsNil() {
+			d.Field(i).Set(reflect.ValueOf(&MutationDetailedStatus{}))
+		}
+		status := d.Field(i).Interface().(*MutationDetailedStatus)
+		status.DetailedStatus = append(status.DetailedStatus, s.Field(i).Interface().(*MutationDetailedStatus).DetailedStatus...)
+	}
+}
+
+type bundleMutation func(context.Context, graphql.Client, *Bundle) (interface{}, error)
+
+// applyBundleChunks sends a bundle in dependency ordered chunks, chunks are sent in reverse order when deleting
+// the detailed status of each chunk is merged, the first chunk that fails stops the remaining chunks from being sent
+func applyBundleChunks(ctx context.Context, client graphql.Client, bundle Bundle, mutation bundleMutation, reverse bool) (interface{}, error) {
+	chunks, err := chunkBundle(bundle, maxBundleSize())
+	if err != nil {
+		return nil, err
+	}
+	if reverse {
+		slices.Reverse(chunks)
+	}
+
+	merged := &BundleResponseDetailedStatus{}
+	for i, chunk := range chunks {
+		resp, applyErr := mutation(ctx, client, &chunk.bundle)
+
+		respBytes, err := json.Marshal(resp)
+		if err != nil {
+			return nil, err
+		}
+
+		if applyErr != nil {
+			bundleApplyErrors, err := CheckDetailedStatus(respBytes)
+			if err != nil {
+				return nil, err
+			}
+			if bundleApplyErrors != nil {
+				bundleApplyErrorBytes, err := json.Marshal(bundleApplyErrors)
+				if err != nil {
+					return nil, err
+				}
+				applyErr = fmt.Errorf("%s", string(bundleApplyErrorBytes))
+			}
+			if len(chunks) == 1 {
+				return nil, applyErr
+			}
+			return nil, fmt.Errorf("bundle chunk %d of %d (%s) failed, %d chunks were applied: %w", i+1, len(chunks), chunk, i, applyErr)
+		}
+
+		if status, ok := resp.(*BundleResponseDetailedStatus); ok {
+			mergeDetailedStatus(merged, status)
+		}
+	}
+	return merged, nil
+}
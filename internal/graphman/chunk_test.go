@@ -0,0 +1,201 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package graphman
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testChunkedBundle() Bundle {
+	bundle := Bundle{
+		Folders:           []*FolderInput{{Name: "apis", Path: "/apis"}},
+		Keys:              []*KeyInput{{Alias: "ssl"}},
+		ClusterProperties: []*ClusterPropertyInput{{Name: "cwp", Value: "true"}},
+		PolicyFragments:   []*PolicyFragmentInput{{Name: "fragment", FolderPath: "/apis"}},
+		Properties: &BundleProperties{
+			DefaultAction: MappingActionNewOrUpdate,
+			Mappings: BundleMappings{
+				Services: []*MappingInstructionInput{{Action: MappingActionDelete, Source: map[string]string{"resolutionPath": "/api-0"}}},
+				Schemas:  []*MappingInstructionInput{{Action: MappingActionIgnore, Default: true}},
+			},
+		},
+	}
+	for i := 0; i < 5; i++ {
+		bundle.Services = append(bundle.Services, &L7ServiceInput{Name: "api-" + strconv.Itoa(i), ResolutionPath: "/api-" + strconv.Itoa(i), FolderPath: "/apis"})
+	}
+	return bundle
+}
+
+func TestChunkBundle(t *testing.T) {
+	bundle := testChunkedBundle()
+	serviceBytes, _ := json.Marshal(bundle.Services[0])
+	maxSize := 2*len(serviceBytes) + len(serviceBytes)/2
+
+	t.Run("should not split small bundles", func(t *testing.T) {
+		chunks, err := chunkBundle(bundle, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(chunks) != 1 {
+			t.Fatalf("expected 1 chunk, actual %d", len(chunks))
+		}
+		if chunks[0].bundle.Properties != bundle.Properties || len(chunks[0].bundle.Services) != 5 {
+			t.Errorf("expected the bundle to be unchanged")
+		}
+	})
+
+	t.Run("should split large bundles in dependency order", func(t *testing.T) {
+		chunks, err := chunkBundle(bundle, maxSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		phases := []string{}
+		for _, chunk := range chunks {
+			phases = append(phases, chunk.phase)
+		}
+		expected := []string{"folders", "keys", "configuration", "policies", "services", "services", "services"}
+		if !slices.Equal(phases, expected) {
+			t.Fatalf("expected phases %v, actual %v", expected, phases)
+		}
+		services := 0
+		for _, chunk := range chunks {
+			chunkBytes, _ := json.Marshal(chunk.bundle.Services)
+			if len(chunk.bundle.Services) > 1 && len(chunkBytes) > maxSize {
+				t.Errorf("expected chunk %s to be at most %d bytes, actual %d", chunk, maxSize, len(chunkBytes))
+			}
+			services += len(chunk.bundle.Services)
+		}
+		if services != 5 {
+			t.Errorf("expected 5 services, actual %d", services)
+		}
+	})
+
+	t.Run("should send mappings with the matching entity types", func(t *testing.T) {
+		chunks, err := chunkBundle(bundle, maxSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(chunks[0].bundle.Properties.Mappings.Schemas) != 1 {
+			t.Errorf("expected mappings without entities to be sent with the first chunk")
+		}
+		for _, chunk := range chunks {
+			if chunk.bundle.Properties.DefaultAction != MappingActionNewOrUpdate {
+				t.Errorf("expected chunk %s to keep the default action", chunk)
+			}
+			hasMapping := len(chunk.bundle.Properties.Mappings.Services) == 1
+			if hasMapping != (chunk.phase == "services") {
+				t.Errorf("expected service mappings only in service chunks, chunk %s", chunk)
+			}
+		}
+	})
+}
+
+func TestApplyBundleChunks(t *testing.T) {
+	bundle := testChunkedBundle()
+	serviceBytes, _ := json.Marshal(bundle.Services[0])
+	config := DefaultClientConfig
+	config.MaxRetries = 0
+	config.MaxBundleSize = 2*len(serviceBytes) + len(serviceBytes)/2
+	SetClientConfig(config)
+	t.Cleanup(func() { SetClientConfig(DefaultClientConfig) })
+
+	received := []string{}
+	failOn := ""
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			Variables map[string]json.RawMessage `json:"variables"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		entities := []string{}
+		for entity := range req.Variables {
+			if entity != "properties" {
+				entities = append(entities, entity)
+			}
+		}
+		slices.Sort(entities)
+		received = append(received, strings.Join(entities, ","))
+
+		w.Header().Set("Content-Type", "application/json")
+		if slices.Contains(entities, failOn) {
+			_, _ = w.Write([]byte(`{"data":{"set` + strings.ToUpper(failOn[:1]) + failOn[1:] + `":{"detailedStatus":[{"action":"NEW_OR_UPDATE","status":"ERROR","description":"invalid policy"}]}},"errors":[{"message":"mutation failed"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"setServices":{"detailedStatus":[{"action":"NEW_OR_UPDATE","status":"CREATED"}]}}}`))
+	}))
+	t.Cleanup(server.Close)
+	bundleBytes, _ := json.Marshal(bundle)
+
+	t.Run("should merge the detailed status of each chunk", func(t *testing.T) {
+		resp, err := ApplyDynamicBundle(context.Background(), "admin", "password", server.URL+"/graphman", "", bundleBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(received) != 7 {
+			t.Fatalf("expected 7 requests, actual %d", len(received))
+		}
+		if received[0] != "folders" || received[6] != "services" {
+			t.Errorf("expected folders first and services last, actual %v", received)
+		}
+		status := resp.(*BundleResponseDetailedStatus)
+		if len(status.Services.DetailedStatus) != 7 {
+			t.Errorf("expected 7 merged statuses, actual %d", len(status.Services.DetailedStatus))
+		}
+	})
+
+	t.Run("should stop on the first failing chunk", func(t *testing.T) {
+		received = []string{}
+		failOn = "policyFragments"
+		_, err := ApplyDynamicBundle(context.Background(), "admin", "password", server.URL+"/graphman", "", bundleBytes)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if len(received) != 4 {
+			t.Errorf("expected the remaining chunks to be skipped, actual %v", received)
+		}
+		if !strings.Contains(err.Error(), "bundle chunk 4 of 7 (policies: policyFragments)") || !strings.Contains(err.Error(), "invalid policy") {
+			t.Errorf("expected the failing chunk and entity to be reported, actual %s", err.Error())
+		}
+	})
+
+	t.Run("should delete in reverse order", func(t *testing.T) {
+		received = []string{}
+		failOn = ""
+		_, err := DeleteDynamicBundle(context.Background(), "admin", "password", server.URL+"/graphman", "", bundleBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(received) != 7 || received[0] != "services" || received[6] != "folders" {
+			t.Errorf("expected services first and folders last, actual %v", received)
+		}
+	})
+}
@@ -0,0 +1,316 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package graphman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ClientConfig limits the Graphman requests that are sent to each Gateway pod
+type ClientConfig struct {
+	// RequestTimeout is the deadline for a single request attempt
+	RequestTimeout time.Duration
+	// MaxRetries is the number of times a request is retried after a connection error or a 429, 502, 503 or 504 response
+	// timed out requests are not retried
+	MaxRetries int
+	// RetryBackoff is the initial delay between retries, it doubles after each attempt
+	RetryBackoff time.Duration
+	// MaxRetryBackoff caps the delay between retries
+	MaxRetryBackoff time.Duration
+	// RequestsPerSecond limits requests to each pod, 0 disables rate limiting
+	RequestsPerSecond float64
+	// FailureThreshold is the number of consecutive failed requests that opens the circuit breaker for a pod
+	// a timed out request opens the circuit breaker straight away
+	FailureThreshold int
+	// OpenDuration is how long requests to a pod are skipped before a trial request is allowed
+	OpenDuration time.Duration
+	// MaxBundleSize is the approximate size in bytes above which bundles are applied in chunks, 0 disables chunking
+	MaxBundleSize int
+}
+
+var DefaultClientConfig = ClientConfig{
+	RequestTimeout:   60 * time.Second,
+	MaxRetries:       3,
+	RetryBackoff:     500 * time.Millisecond,
+	MaxRetryBackoff:  5 * time.Second,
+	FailureThreshold: 5,
+	OpenDuration:     30 * time.Second,
+	MaxBundleSize:    4 << 20,
+}
+
+// ErrCircuitOpen is returned without sending a request when a pod has failed too many consecutive requests
+var ErrCircuitOpen = errors.New("graphman circuit breaker is open")
+
+// targetTTL is how long the state of a pod is kept after its last request
+// pod addresses are reused or disappear when pods are replaced so idle entries are pruned
+const targetTTL = 10 * time.Minute
+
+var (
+	clientConfig = DefaultClientConfig
+	targets      = map[string]*targetState{}
+	targetsMu    sync.Mutex
+	lastPrune    time.Time
+)
+
+// SetClientConfig replaces the configuration that is used by new Graphman requests
+func SetClientConfig(config ClientConfig) {
+	targetsMu.Lock()
+	defer targetsMu.Unlock()
+	clientConfig = config
+	targets = map[string]*targetState{}
+}
+
+func maxBundleSize() int {
+	targetsMu.Lock()
+	defer targetsMu.Unlock()
+	return clientConfig.MaxBundleSize
+}
+
+// targetState tracks consecutive failures for a single Gateway pod
+type targetState struct {
+	mu        sync.Mutex
+	limiter   *rate.Limiter
+	failures  int
+	openUntil time.Time
+	trial     bool
+	lastError string
+	lastUsed  time.Time
+}
+
+func target(host string) (*targetState, ClientConfig) {
+	targetsMu.Lock()
+	defer targetsMu.Unlock()
+	now := time.Now()
+	if now.Sub(lastPrune) >= targetTTL {
+		pruneTargets(now)
+	}
+	ts, ok := targets[host]
+	if !ok {
+		ts = &targetState{}
+		if clientConfig.RequestsPerSecond > 0 {
+			ts.limiter = rate.NewLimiter(rate.Limit(clientConfig.RequestsPerSecond), 1)
+		}
+		targets[host] = ts
+	}
+	ts.lastUsed = now
+	return ts, clientConfig
+}
+
+// pruneTargets removes pods that have not been sent a request within targetTTL, targetsMu must be held
+func pruneTargets(now time.Time) {
+	for host, ts := range targets {
+		if now.Sub(ts.lastUsed) >= targetTTL {
+			delete(targets, host)
+		}
+	}
+	lastPrune = now
+}
+
+// allow returns ErrCircuitOpen while the circuit is open
+// once OpenDuration has passed a single trial request is let through
+func (ts *targetState) allow(config ClientConfig) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if config.FailureThreshold <= 0 || ts.failures < config.FailureThreshold {
+		return nil
+	}
+	if time.Now().Before(ts.openUntil) || ts.trial {
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, ts.lastError)
+	}
+	ts.trial = true
+	return nil
+}
+
+func (ts *targetState) record(config ClientConfig, err error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.trial = false
+	if err == nil {
+		ts.failures = 0
+		ts.lastError = ""
+		return
+	}
+	ts.failures++
+	ts.lastError = err.Error()
+	if config.FailureThreshold > 0 && ts.failures >= config.FailureThreshold {
+		ts.openUntil = time.Now().Add(config.OpenDuration)
+	}
+}
+
+// open records a failure that opens the circuit breaker regardless of the number of consecutive failures
+func (ts *targetState) open(config ClientConfig, err error) {
+	ts.record(config, err)
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if config.FailureThreshold > 0 && ts.failures < config.FailureThreshold {
+		ts.failures = config.FailureThreshold
+		ts.openUntil = time.Now().Add(config.OpenDuration)
+	}
+}
+
+// TargetHealth reports whether requests to a Gateway pod are being skipped by the circuit breaker
+// host is the pod address and Graphman port
+func TargetHealth(host string) (healthy bool, reason string) {
+	targetsMu.Lock()
+	ts, ok := targets[host]
+	config := clientConfig
+	targetsMu.Unlock()
+	if !ok {
+		return true, ""
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if config.FailureThreshold <= 0 || ts.failures < config.FailureThreshold || !time.Now().Before(ts.openUntil) {
+		return true, ""
+	}
+	return false, ts.lastError
+}
+
+// resilientTransport applies per pod rate limits, request deadlines, retries and circuit breaking
+type resilientTransport struct {
+	next http.RoundTripper
+}
+
+func (rt *resilientTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ts, config := target(r.URL.Host)
+	if err := ts.allow(config); err != nil {
+		return nil, err
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		if ts.limiter != nil {
+			if err = ts.limiter.Wait(r.Context()); err != nil {
+				break
+			}
+		}
+
+		req := r
+		if attempt > 0 && r.GetBody != nil {
+			req = r.Clone(r.Context())
+			req.Body, err = r.GetBody()
+			if err != nil {
+				break
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), config.RequestTimeout)
+		resp, err = rt.next.RoundTrip(req.WithContext(ctx))
+		if attempt >= config.MaxRetries || !retryable(r, resp, err) {
+			if resp != nil {
+				resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			} else {
+				cancel()
+			}
+			break
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		cancel()
+
+		select {
+		case <-time.After(backoff(config, attempt)):
+		case <-r.Context().Done():
+			ts.record(config, r.Context().Err())
+			return nil, r.Context().Err()
+		}
+	}
+
+	switch {
+	case err == nil && resp.StatusCode >= http.StatusInternalServerError:
+		ts.record(config, fmt.Errorf("graphman request failed with status %d", resp.StatusCode))
+	case timeout(err) && r.Context().Err() == nil:
+		// a pod that does not respond within the request timeout is treated as down
+		ts.open(config, err)
+	default:
+		ts.record(config, err)
+	}
+	return resp, err
+}
+
+// retryable returns true for connection errors and responses that indicate the pod is temporarily unavailable
+func retryable(r *http.Request, resp *http.Response, err error) bool {
+	if r.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		if timeout(err) {
+			return false
+		}
+		return r.Body == nil || r.GetBody != nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return r.Body == nil || r.GetBody != nil
+	}
+	return false
+}
+
+// timeout returns true if err is a request deadline or network timeout
+func timeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff returns an exponential delay with jitter between half and the full delay
+func backoff(config ClientConfig, attempt int) time.Duration {
+	delay := config.RetryBackoff << attempt
+	if delay <= 0 || delay > config.MaxRetryBackoff {
+		delay = config.MaxRetryBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// cancelBody releases the request deadline once the response has been read
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,165 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package graphman
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientRetries(t *testing.T) {
+	SetClientConfig(ClientConfig{RequestTimeout: time.Second, MaxRetries: 2, RetryBackoff: time.Millisecond, MaxRetryBackoff: 5 * time.Millisecond, FailureThreshold: 5, OpenDuration: time.Minute})
+	t.Cleanup(func() { SetClientConfig(DefaultClientConfig) })
+
+	requests := atomic.Int32{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"clusterInfo":{"name":"cluster"}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	info, err := ServerInfo(context.Background(), "admin", "password", server.URL+"/graphman")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "cluster" {
+		t.Errorf("expected %s, actual %s", "cluster", info.Name)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("expected %d requests, actual %d", 3, requests.Load())
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	SetClientConfig(ClientConfig{RequestTimeout: 50 * time.Millisecond, MaxRetries: 0, FailureThreshold: 2, OpenDuration: 100 * time.Millisecond})
+	t.Cleanup(func() { SetClientConfig(DefaultClientConfig) })
+
+	healthy := atomic.Bool{}
+	requests := atomic.Int32{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			// a hung gateway pod
+			time.Sleep(100 * time.Millisecond)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"clusterInfo":{"name":"cluster"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	if _, err := ServerInfo(context.Background(), "admin", "password", server.URL+"/graphman"); err == nil {
+		t.Fatal("expected request to time out")
+	}
+
+	if ok, reason := TargetHealth(host); ok || reason == "" {
+		t.Errorf("expected target to be unhealthy with a reason")
+	}
+
+	_, err := ServerInfo(context.Background(), "admin", "password", server.URL+"/graphman")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected %v, actual %v", ErrCircuitOpen, err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected a timed out request to open the circuit, actual %d requests", requests.Load())
+	}
+
+	healthy.Store(true)
+	time.Sleep(150 * time.Millisecond)
+	if _, err := ServerInfo(context.Background(), "admin", "password", server.URL+"/graphman"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := TargetHealth(host); !ok {
+		t.Errorf("expected target to recover after a successful trial request")
+	}
+}
+
+func TestClientTimeoutNotRetried(t *testing.T) {
+	SetClientConfig(ClientConfig{RequestTimeout: 50 * time.Millisecond, MaxRetries: 3, RetryBackoff: time.Millisecond, MaxRetryBackoff: 5 * time.Millisecond, FailureThreshold: 5, OpenDuration: time.Minute})
+	t.Cleanup(func() { SetClientConfig(DefaultClientConfig) })
+
+	requests := atomic.Int32{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(100 * time.Millisecond)
+	}))
+	t.Cleanup(server.Close)
+
+	if _, err := ServerInfo(context.Background(), "admin", "password", server.URL+"/graphman"); err == nil {
+		t.Fatal("expected request to time out")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected %d request, actual %d", 1, requests.Load())
+	}
+	if ok, _ := TargetHealth(strings.TrimPrefix(server.URL, "https://")); ok {
+		t.Errorf("expected target to be unhealthy after a timeout")
+	}
+}
+
+func TestPruneTargets(t *testing.T) {
+	SetClientConfig(DefaultClientConfig)
+	t.Cleanup(func() { SetClientConfig(DefaultClientConfig) })
+
+	target("10.0.0.1:9443")
+	target("10.0.0.2:9443")
+
+	targetsMu.Lock()
+	targets["10.0.0.1:9443"].lastUsed = time.Now().Add(-targetTTL)
+	lastPrune = time.Now().Add(-targetTTL)
+	targetsMu.Unlock()
+
+	target("10.0.0.2:9443")
+
+	targetsMu.Lock()
+	defer targetsMu.Unlock()
+	if _, ok := targets["10.0.0.1:9443"]; ok {
+		t.Errorf("expected idle target to be pruned")
+	}
+	if _, ok := targets["10.0.0.2:9443"]; !ok {
+		t.Errorf("expected active target to be kept")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	config := ClientConfig{RetryBackoff: 100 * time.Millisecond, MaxRetryBackoff: time.Second}
+	for attempt, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		delay := backoff(config, attempt)
+		if delay < max/2 || delay > max {
+			t.Errorf("attempt %d: expected a delay between %s and %s, actual %s", attempt, max/2, max, delay)
+		}
+	}
+}
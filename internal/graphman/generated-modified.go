@@ -3759,6 +3759,55 @@ func (v *__installBundleInput) GetAuditConfigurations() []*AuditConfigurationInp
 // GetKerberosConfigs returns __installBundleInput.KerberosConfigs, and is useful for accessing the field via an interface.
 func (v *__installBundleInput) GetKerberosConfigs() []*KerberosConfigInput { return v.KerberosConfigs }
 
+// clusterInfoClusterInfo includes the requested fields of the GraphQL type ClusterInfo.
+type clusterInfoClusterInfo struct {
+	// cluster Name
+	Name string `json:"name"`
+	// cluster Nodes Info
+	Nodes []*clusterInfoClusterInfoNodesClusterNodeInfo `json:"nodes"`
+}
+
+// GetName returns clusterInfoClusterInfo.Name, and is useful for accessing the field via an interface.
+func (v *clusterInfoClusterInfo) GetName() string { return v.Name }
+
+// GetNodes returns clusterInfoClusterInfo.Nodes, and is useful for accessing the field via an interface.
+func (v *clusterInfoClusterInfo) GetNodes() []*clusterInfoClusterInfoNodesClusterNodeInfo {
+	return v.Nodes
+}
+
+// clusterInfoClusterInfoNodesClusterNodeInfo includes the requested fields of the GraphQL type ClusterNodeInfo.
+type clusterInfoClusterInfoNodesClusterNodeInfo struct {
+	// cluster node id
+	NodeId string `json:"nodeId"`
+	// cluster node name
+	Name string `json:"name"`
+	// cluster node IP Address
+	Address string `json:"address"`
+	// cluster node uptime
+	Uptime int64 `json:"uptime"`
+}
+
+// GetNodeId returns clusterInfoClusterInfoNodesClusterNodeInfo.NodeId, and is useful for accessing the field via an interface.
+func (v *clusterInfoClusterInfoNodesClusterNodeInfo) GetNodeId() string { return v.NodeId }
+
+// GetName returns clusterInfoClusterInfoNodesClusterNodeInfo.Name, and is useful for accessing the field via an interface.
+func (v *clusterInfoClusterInfoNodesClusterNodeInfo) GetName() string { return v.Name }
+
+// GetAddress returns clusterInfoClusterInfoNodesClusterNodeInfo.Address, and is useful for accessing the field via an interface.
+func (v *clusterInfoClusterInfoNodesClusterNodeInfo) GetAddress() string { return v.Address }
+
+// GetUptime returns clusterInfoClusterInfoNodesClusterNodeInfo.Uptime, and is useful for accessing the field via an interface.
+func (v *clusterInfoClusterInfoNodesClusterNodeInfo) GetUptime() int64 { return v.Uptime }
+
+// clusterInfoResponse is returned by clusterInfo on success.
+type clusterInfoResponse struct {
+	// Get all cluster nodes information
+	ClusterInfo *clusterInfoClusterInfo `json:"clusterInfo"`
+}
+
+// GetClusterInfo returns clusterInfoResponse.ClusterInfo, and is useful for accessing the field via an interface.
+func (v *clusterInfoResponse) GetClusterInfo() *clusterInfoClusterInfo { return v.ClusterInfo }
+
 // deleteKeysDeleteKeysKeysPayload includes the requested fields of the GraphQL type KeysPayload.
 type deleteKeysDeleteKeysKeysPayload struct {
 	DetailedStatus []*deleteKeysDeleteKeysKeysPayloadDetailedStatusEntityMutationDetailedStatus `json:"detailedStatus"`
@@ -8558,6 +8607,43 @@ func (v *installBundleSetWebApiServicesWebApiServicesPayloadDetailedStatusEntity
 	return v.Value
 }
 
+// The query or mutation executed by clusterInfo.
+const clusterInfo_Operation = `
+query clusterInfo {
+	clusterInfo {
+		name
+		nodes {
+			nodeId
+			name
+			address
+			uptime
+		}
+	}
+}
+`
+
+func clusterInfo(
+	ctx_ context.Context,
+	client_ graphql.Client,
+) (*clusterInfoResponse, error) {
+	req_ := &graphql.Request{
+		OpName: "clusterInfo",
+		Query:  clusterInfo_Operation,
+	}
+	var err_ error
+
+	var data_ clusterInfoResponse
+	resp_ := &graphql.Response{Data: &data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return &data_, err_
+}
+
 // The query or mutation executed by deleteKeys.
 const deleteKeys_Operation = `
 mutation deleteKeys ($keys: [String!]!) {
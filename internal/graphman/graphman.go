@@ -30,6 +30,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // Implode - convert an exploded Graphman directory into a single JSON file.
@@ -59,81 +60,185 @@ func Implode(path string, processNestedRepos bool) ([]byte, error) {
 	return bundleBytes, nil
 }
 
-func RemoveL7PortalApi(username string, password string, target string, apiName string, policyFragmentName string, secretNames []string) ([]byte, error) {
-	resp, err := deleteL7PortalApi(context.Background(), gqlClient(username, password, target, ""), []string{apiName}, []string{policyFragmentName}, secretNames)
+// ExportToPath retrieves the configuration of a gateway and explodes it into path using the layout that Implode reads
+// secrets are only exported if encpass is set, they are encrypted with encpass
+func ExportToPath(ctx context.Context, username string, password string, target string, encpass string, path string) (int, error) {
+	bundle, err := bundleExport(ctx, gqlClient(username, password, target, encpass), exportQuery(encpass != ""))
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	respBytes, err := json.Marshal(resp)
+	return explodeBundle(*bundle, path)
+}
+
+// Export retrieves the full configuration of the entities of entityType with the given names from a gateway
+// names are filtered by the gateway, entities that are not found are left out of the bundle
+// secrets are only exported if encpass is set, they are encrypted with encpass
+func Export(ctx context.Context, username string, password string, target string, encpass string, entityType string, names []string) (*Bundle, error) {
+	query, ok := namedEntityQueries[entityType]
+	if !ok {
+		return nil, fmt.Errorf("entity type %s can not be exported by name", entityType)
+	}
+	fields := query.exportFields
+	if encpass != "" && query.secretFields != "" {
+		fields = fields + " " + query.secretFields
+	}
+	found, err := queryNamedEntities(ctx, gqlClient(username, password, target, encpass), query, fields, names)
 	if err != nil {
 		return nil, err
 	}
+	return namedEntitiesBundle(entityType, found)
+}
 
-	return respBytes, nil
+// Summary retrieves the identifying fields and checksum of the entities of entityType with the given names from a gateway
+// names are filtered by the gateway, entities that are not found are left out of the bundle
+func Summary(ctx context.Context, username string, password string, target string, entityType string, names []string) (*Bundle, error) {
+	query, ok := namedEntityQueries[entityType]
+	if !ok {
+		return nil, fmt.Errorf("entity type %s does not support summaries by name", entityType)
+	}
+	found, err := queryNamedEntities(ctx, gqlClient(username, password, target, ""), query, query.summaryFields, names)
+	if err != nil {
+		return nil, err
+	}
+	return namedEntitiesBundle(entityType, found)
 }
 
-func ApplyDynamicBundle(username string, password string, target string, encpass string, bundleBytes []byte) (interface{}, error) {
+// MissingEntities reports the entities in bundleBytes that do not exist on a gateway as entityType/name
+// only entity types that can be queried by name are checked, entities that are mapped for deletion are skipped
+func MissingEntities(ctx context.Context, username string, password string, target string, bundleBytes []byte) ([]string, error) {
 	bundle := Bundle{}
-
 	err := json.Unmarshal(bundleBytes, &bundle)
 	if err != nil {
 		return nil, err
 	}
-	resp, applyErr := installGenericBundle(context.Background(), gqlClient(username, password, target, encpass), &bundle)
-
-	respBytes, err := json.Marshal(resp)
+	err = ResetMappings(&bundle)
 	if err != nil {
 		return nil, err
 	}
 
-	if applyErr != nil {
-		bundleApplyErrors, err := CheckDetailedStatus(respBytes)
-		if err != nil {
-			return nil, err
+	client := gqlClient(username, password, target, "")
+	missing := []string{}
+	bundleVal := reflect.ValueOf(bundle)
+	for i := 0; i < bundleVal.NumField(); i++ {
+		entityType := strings.Split(bundleVal.Type().Field(i).Tag.Get("json"), ",")[0]
+		query, ok := namedEntityQueries[entityType]
+		if !ok || bundleVal.Field(i).Kind() != reflect.Slice {
+			continue
 		}
-		if bundleApplyErrors == nil {
-			return nil, applyErr
+		entities := bundleVal.Field(i)
+		names := make([]string, entities.Len())
+		for j := range names {
+			names[j] = entityFieldValue(entities.Index(j), query.nameField)
 		}
-		bundleApplyErrorBytes, err := json.Marshal(bundleApplyErrors)
+		found, err := queryNamedEntities(ctx, client, query, query.summaryFields, names)
 		if err != nil {
 			return nil, err
 		}
-		return nil, fmt.Errorf("%s", string(bundleApplyErrorBytes))
+		for j, name := range names {
+			if len(found[j]) == 0 {
+				missing = append(missing, entityType+"/"+name)
+			}
+		}
 	}
+	return missing, nil
+}
 
-	return resp, nil
+// ClusterInfo describes the gateway cluster that a Graphman target belongs to
+type ClusterInfo struct {
+	Name  string            `json:"name,omitempty"`
+	Nodes []ClusterNodeInfo `json:"nodes,omitempty"`
+}
+
+// ClusterNodeInfo is a gateway node, uptime is in milliseconds
+type ClusterNodeInfo struct {
+	NodeId  string `json:"nodeId,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address,omitempty"`
+	Uptime  int64  `json:"uptime,omitempty"`
+}
+
+// ServerInfo retrieves the cluster name and nodes of a gateway
+// the Graphman schema does not expose the gateway version, callers report the version of the Gateway resource
+func ServerInfo(ctx context.Context, username string, password string, target string) (*ClusterInfo, error) {
+	resp, err := clusterInfo(ctx, gqlClient(username, password, target, ""))
+	if err != nil {
+		return nil, err
+	}
+	info := &ClusterInfo{}
+	if resp.ClusterInfo == nil {
+		return info, nil
+	}
+	info.Name = resp.ClusterInfo.Name
+	for _, node := range resp.ClusterInfo.Nodes {
+		if node == nil {
+			continue
+		}
+		info.Nodes = append(info.Nodes, ClusterNodeInfo{NodeId: node.NodeId, Name: node.Name, Address: node.Address, Uptime: node.Uptime})
+	}
+	return info, nil
 }
 
-func DeleteDynamicBundle(username string, password string, target string, encpass string, bundleBytes []byte) (interface{}, error) {
+func RemoveL7PortalApi(username string, password string, target string, apiName string, policyFragmentName string, secretNames []string) ([]byte, error) {
+	resp, err := deleteL7PortalApi(context.Background(), gqlClient(username, password, target, ""), []string{apiName}, []string{policyFragmentName}, secretNames)
+	if err != nil {
+		return nil, err
+	}
+	respBytes, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return respBytes, nil
+}
+
+// ApplyDynamicBundle installs a bundle on a gateway, bundles larger than the configured MaxBundleSize are applied in dependency ordered chunks
+func ApplyDynamicBundle(ctx context.Context, username string, password string, target string, encpass string, bundleBytes []byte) (interface{}, error) {
 	bundle := Bundle{}
 
 	err := json.Unmarshal(bundleBytes, &bundle)
 	if err != nil {
 		return nil, err
 	}
-	resp, applyErr := deleteGenericBundle(context.Background(), gqlClient(username, password, target, encpass), &bundle)
+	return applyBundleChunks(ctx, gqlClient(username, password, target, encpass), bundle, installGenericBundle, false)
+}
 
-	respBytes, err := json.Marshal(resp)
+// DeleteDynamicBundle deletes the entities in a bundle from a gateway, chunks are deleted in reverse dependency order
+func DeleteDynamicBundle(ctx context.Context, username string, password string, target string, encpass string, bundleBytes []byte) (interface{}, error) {
+	bundle := Bundle{}
+
+	err := json.Unmarshal(bundleBytes, &bundle)
 	if err != nil {
 		return nil, err
 	}
+	return applyBundleChunks(ctx, gqlClient(username, password, target, encpass), bundle, deleteGenericBundle, true)
+}
 
-	if applyErr != nil {
-		bundleApplyErrors, err := CheckDetailedStatus(respBytes)
-		if err != nil {
-			return nil, err
-		}
-		if bundleApplyErrors == nil {
-			return nil, applyErr
-		}
-		bundleApplyErrorBytes, err := json.Marshal(bundleApplyErrors)
-		if err != nil {
-			return nil, err
-		}
-		return nil, fmt.Errorf("%s", string(bundleApplyErrorBytes))
+// DetectDrift retrieves a summary of the entities in bundleBytes from a gateway and reports those that are missing or modified
+func DetectDrift(ctx context.Context, username string, password string, target string, bundleBytes []byte) ([]DriftedEntity, error) {
+	desired := Bundle{}
+
+	err := json.Unmarshal(bundleBytes, &desired)
+	if err != nil {
+		return nil, err
+	}
+
+	// entities that are mapped for deletion are not expected on the gateway
+	err = ResetMappings(&desired)
+	if err != nil {
+		return nil, err
+	}
+
+	query := summaryQuery(desired)
+	if query == "" {
+		return nil, nil
+	}
+
+	live, err := bundleSummary(ctx, gqlClient(username, password, target, ""), query)
+	if err != nil {
+		return nil, err
 	}
 
-	return resp, nil
+	return CalculateDrift(*live, desired)
 }
 
 func CheckDetailedStatus(respBytes []byte) (*[]BundleApplyError, error) {
@@ -0,0 +1,204 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package graphman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type testGraphmanRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// newTestGraphmanServer responds to every request with the response of respond
+func newTestGraphmanServer(t *testing.T, respond func(req testGraphmanRequest) string) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := testGraphmanRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(respond(req)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSummary(t *testing.T) {
+	queries := []testGraphmanRequest{}
+	server := newTestGraphmanServer(t, func(req testGraphmanRequest) string {
+		queries = append(queries, req)
+		return `{"data":{"e0":{"goid":"1","name":"cwp1","checksum":"abc"},"e1":null}}`
+	})
+
+	summary, err := Summary(context.Background(), "admin", "password", server.URL+"/graphman", "clusterProperties", []string{"cwp1", "cwp2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 1 || !strings.Contains(queries[0].Query, "e1: clusterPropertyByName(name: $n1) {goid name checksum}") || queries[0].Variables["n1"] != "cwp2" {
+		t.Errorf("expected a single summary query for both names, actual %v", queries)
+	}
+	if len(summary.ClusterProperties) != 1 || summary.ClusterProperties[0].Name != "cwp1" || summary.ClusterProperties[0].Checksum != "abc" {
+		t.Errorf("unexpected cluster properties %v", summary.ClusterProperties)
+	}
+
+	_, err = Summary(context.Background(), "admin", "password", server.URL+"/graphman", "unknown", []string{"name"})
+	if err == nil {
+		t.Errorf("unknown entity types should be rejected")
+	}
+}
+
+func TestSummaryBatches(t *testing.T) {
+	requests := 0
+	server := newTestGraphmanServer(t, func(req testGraphmanRequest) string {
+		requests++
+		return `{"data":{}}`
+	})
+
+	names := make([]string, namedEntityBatchSize+1)
+	for i := range names {
+		names[i] = fmt.Sprintf("cwp%d", i)
+	}
+	if _, err := Summary(context.Background(), "admin", "password", server.URL+"/graphman", "clusterProperties", names); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("expected %d names to be queried in %d requests, actual %d", len(names), 2, requests)
+	}
+}
+
+func TestExport(t *testing.T) {
+	query := testGraphmanRequest{}
+	server := newTestGraphmanServer(t, func(req testGraphmanRequest) string {
+		query = req
+		if strings.Contains(req.Query, "servicesByName") {
+			return `{"data":{"e0":[{"goid":"1","name":"api","resolutionPath":"/api1","serviceType":"WEB_API"},{"goid":"2","name":"api","resolutionPath":"/api2","serviceType":"WEB_API"}]}}`
+		}
+		return `{"data":{"e0":{"goid":"1","name":"secret1","secretType":"PASSWORD","checksum":"abc"}}}`
+	})
+
+	bundle, err := Export(context.Background(), "admin", "password", server.URL+"/graphman", "", "services", []string{"api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Variables["n0"] != "api" {
+		t.Errorf("expected the service name to be filtered by the gateway, actual %v", query.Variables)
+	}
+	if len(bundle.Services) != 2 || bundle.Services[1].ResolutionPath != "/api2" {
+		t.Errorf("expected every service named api to be exported, actual %v", bundle.Services)
+	}
+
+	bundle, err = Export(context.Background(), "admin", "password", server.URL+"/graphman", "", "secrets", []string{"secret1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(query.Query, " secret ") || strings.Contains(query.Query, " secret}") {
+		t.Errorf("secret values should not be exported without an encryption passphrase %v", query.Query)
+	}
+	if len(bundle.Secrets) != 1 || bundle.Secrets[0].Name != "secret1" {
+		t.Errorf("unexpected secrets %v", bundle.Secrets)
+	}
+
+	if _, err := Export(context.Background(), "admin", "password", server.URL+"/graphman", "7layer", "secrets", []string{"secret1"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(query.Query, "checksum secret}") {
+		t.Errorf("secret values should be exported with an encryption passphrase %v", query.Query)
+	}
+}
+
+func TestMissingEntities(t *testing.T) {
+	requests := 0
+	server := newTestGraphmanServer(t, func(req testGraphmanRequest) string {
+		requests++
+		if strings.Contains(req.Query, "keyByAlias") {
+			return `{"data":{"e0":{"goid":"1","alias":"ssl","keystoreId":"00000000000000000000000000000002","checksum":"def"},"e1":null}}`
+		}
+		return `{"data":{"e0":{"goid":"1","name":"cwp1","checksum":"abc"},"e1":null}}`
+	})
+
+	bundle := []byte(`{"clusterProperties":[{"name":"cwp1","value":"one"},{"name":"cwp2","value":"two"}],"keys":[{"alias":"ssl"},{"alias":"mtls"}],"fips":[{"name":"fip1"}]}`)
+	missing, err := MissingEntities(context.Background(), "admin", "password", server.URL+"/graphman", bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 2 || !Contains(missing, "clusterProperties/cwp2") || !Contains(missing, "keys/mtls") {
+		t.Errorf("unexpected missing entities %v", missing)
+	}
+	if requests != 2 {
+		t.Errorf("expected one request per entity type, actual %d", requests)
+	}
+}
+
+func TestServerInfo(t *testing.T) {
+	server := newTestGraphmanServer(t, func(req testGraphmanRequest) string {
+		return `{"data":{"clusterInfo":{"name":"ssg","nodes":[{"nodeId":"node1","name":"gateway-0","address":"10.0.0.1","uptime":1000}]}}}`
+	})
+
+	info, err := ServerInfo(context.Background(), "admin", "password", server.URL+"/graphman")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "ssg" || len(info.Nodes) != 1 || info.Nodes[0].Address != "10.0.0.1" || info.Nodes[0].Uptime != 1000 {
+		t.Errorf("unexpected server info %v", info)
+	}
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	traceparent := ""
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "apply")
+	_, err := ApplyDynamicBundle(ctx, "admin", "password", server.URL+"/graphman", "", []byte(`{"clusterProperties":[{"name":"cwp1","value":"true"}]}`))
+	span.End()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(traceparent, span.SpanContext().TraceID().String()) {
+		t.Errorf("expected traceparent with trace id %s, actual %q", span.SpanContext().TraceID().String(), traceparent)
+	}
+}
@@ -0,0 +1,199 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package graphman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+)
+
+// namedEntityBatchSize is the number of names that are queried in a single request
+const namedEntityBatchSize = 100
+
+// namedEntityQuery retrieves the summary or full configuration of entities of one type by name
+// the name is filtered by the gateway so only matching entities are returned
+type namedEntityQuery struct {
+	// nameField is the json field of the bundle entity that is passed as name
+	nameField string
+	// field is the query field that looks up a single name, arg is its name argument
+	field string
+	arg   string
+	// summaryFields identify an entity and its checksum, exportFields are required to recreate it
+	summaryFields string
+	exportFields  string
+	// secretFields are only exported with an encryption passphrase
+	secretFields string
+}
+
+// namedEntityQueries are the entity types that can be queried by name
+var namedEntityQueries = map[string]namedEntityQuery{
+	"clusterProperties": {
+		nameField:     "name",
+		field:         "clusterPropertyByName",
+		arg:           "name",
+		summaryFields: "goid name checksum",
+		exportFields:  "goid name value description hiddenProperty checksum",
+	},
+	"services": {
+		nameField:     "name",
+		field:         "servicesByName",
+		arg:           "name",
+		summaryFields: "goid name resolutionPath checksum",
+		exportFields:  "goid guid name resolutionPath resolvers {soapActions baseUri resolutionPath} serviceType checksum enabled folderPath soapVersion methodsAllowed tracingEnabled wssProcessingEnabled laxResolution properties {name value} wsdl wsdlUrl wsdlResources {uri content} policy {xml}",
+	},
+	"policies": {
+		nameField:     "name",
+		field:         "policyByName",
+		arg:           "name",
+		summaryFields: "goid name tag checksum",
+		exportFields:  "goid folderPath name guid policy {xml} soap policyType tag subTag checksum",
+	},
+	"encassConfigs": {
+		nameField:     "name",
+		field:         "encassConfigByName",
+		arg:           "name",
+		summaryFields: "goid name checksum",
+		exportFields:  "goid guid name description policyName encassArgs {name type ordinal guiPrompt guiLabel} encassResults {name type} properties {name value} checksum",
+	},
+	"jdbcConnections": {
+		nameField:     "name",
+		field:         "jdbcConnectionByName",
+		arg:           "name",
+		summaryFields: "goid name checksum",
+		exportFields:  "goid name driverClass jdbcUrl enabled username minPoolSize maxPoolSize properties {name value} checksum",
+		secretFields:  "password",
+	},
+	"secrets": {
+		nameField:     "name",
+		field:         "secretByName",
+		arg:           "name",
+		summaryFields: "goid name checksum",
+		exportFields:  "goid name secretType variableReferencable description checksum",
+		secretFields:  "secret",
+	},
+	"keys": {
+		nameField:     "alias",
+		field:         "keyByAlias",
+		arg:           "alias",
+		summaryFields: "goid alias keystoreId checksum",
+		exportFields:  "goid keystoreId alias subjectDn keyType usageTypes certChain checksum",
+		secretFields:  "p12",
+	},
+	"listenPorts": {
+		nameField:     "name",
+		field:         "listenPortByName",
+		arg:           "name",
+		summaryFields: "goid name port checksum",
+		exportFields:  "goid name enabled protocol port hardwiredServiceName enabledFeatures tlsSettings {clientAuthentication keystoreId keyAlias tlsVersions cipherSuites useCipherSuitesOrder} properties {name value} checksum",
+	},
+	"scheduledTasks": {
+		nameField:     "name",
+		field:         "scheduledTaskByName",
+		arg:           "name",
+		summaryFields: "goid name checksum",
+		exportFields:  "goid name policyName jobType cronExpression executeOnSingleNode executeOnCreation executionDate status runAsUser runAsUserProviderName checksum",
+	},
+	"serverModuleFiles": {
+		nameField:     "name",
+		field:         "serverModuleFileByName",
+		arg:           "name",
+		summaryFields: "goid name checksum",
+		exportFields:  "goid name moduleType moduleSha256 signature signerCertBase64 properties {name value} checksum",
+	},
+}
+
+// namedEntitiesQuery looks up every name in a single query, each name is an aliased field
+func namedEntitiesQuery(query namedEntityQuery, fields string, names []string) (string, map[string]interface{}) {
+	params := make([]string, len(names))
+	selections := make([]string, len(names))
+	variables := make(map[string]interface{}, len(names))
+	for i, name := range names {
+		params[i] = fmt.Sprintf("$n%d: String!", i)
+		selections[i] = fmt.Sprintf("    e%d: %s(%s: $n%d) {%s}", i, query.field, query.arg, i, fields)
+		variables[fmt.Sprintf("n%d", i)] = name
+	}
+	return "query " + query.field + " (" + strings.Join(params, ", ") + ") {\n" + strings.Join(selections, "\n") + "\n}", variables
+}
+
+// foundEntities returns the entities in the result of a named entity query
+// queries return a single entity that is null if it was not found or a list for entity types where names are not unique
+func foundEntities(result json.RawMessage) ([]json.RawMessage, error) {
+	switch {
+	case len(result) == 0 || string(result) == "null":
+		return nil, nil
+	case result[0] == '[':
+		entities := []json.RawMessage{}
+		err := json.Unmarshal(result, &entities)
+		return entities, err
+	default:
+		return []json.RawMessage{result}, nil
+	}
+}
+
+// queryNamedEntities looks up names in batches of namedEntityBatchSize and returns the entities found for each name
+func queryNamedEntities(ctx context.Context, client graphql.Client, query namedEntityQuery, fields string, names []string) ([][]json.RawMessage, error) {
+	found := make([][]json.RawMessage, 0, len(names))
+	for start := 0; start < len(names); start += namedEntityBatchSize {
+		batch := names[start:min(start+namedEntityBatchSize, len(names))]
+		document, variables := namedEntitiesQuery(query, fields, batch)
+		data := map[string]json.RawMessage{}
+		err := client.MakeRequest(ctx, &graphql.Request{OpName: query.field, Query: document, Variables: variables}, &graphql.Response{Data: &data})
+		if err != nil {
+			return nil, err
+		}
+		for i := range batch {
+			entities, err := foundEntities(data[fmt.Sprintf("e%d", i)])
+			if err != nil {
+				return nil, err
+			}
+			found = append(found, entities)
+		}
+	}
+	return found, nil
+}
+
+// namedEntitiesBundle returns the entities that were found as a bundle of entityType
+func namedEntitiesBundle(entityType string, found [][]json.RawMessage) (*Bundle, error) {
+	entities := []json.RawMessage{}
+	for _, f := range found {
+		entities = append(entities, f...)
+	}
+
+	bundleBytes, err := json.Marshal(map[string][]json.RawMessage{entityType: entities})
+	if err != nil {
+		return nil, err
+	}
+	bundle := Bundle{}
+	err = json.Unmarshal(bundleBytes, &bundle)
+	if err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
@@ -33,6 +33,7 @@ import (
 	"time"
 
 	"github.com/Khan/genqlient/graphql"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type CustomTransport struct {
@@ -53,9 +54,10 @@ func (ct *CustomTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 }
 
 func gqlClient(username string, password string, target string, encpass string) graphql.Client {
+	// request deadlines are set per attempt by resilientTransport
 	httpClient := &http.Client{
-		Timeout:   time.Second * 60,
-		Transport: &CustomTransport{username: username, password: password, encpass: encpass, r: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, DialContext: dialTimeout}},
+		// otelhttp adds a client span and propagates trace context to the Gateway in the request headers
+		Transport: &CustomTransport{username: username, password: password, encpass: encpass, r: &resilientTransport{next: otelhttp.NewTransport(&http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, DialContext: dialTimeout})}},
 	}
 	return graphql.NewClient(target, httpClient)
 }
@@ -117,7 +117,6 @@ func cloneL7ApiStatus(orig *v1alpha1.L7ApiStatus) (*v1alpha1.L7ApiStatus, error)
 // deploy the L7Api in params to the gateway pods except the pods in which the api has been deployed or the pods aren't ready yet.
 func deployL7ApiToGateway(ctx context.Context, params Params, gateway *v1.Gateway, tag string, updatedStatus *v1alpha1.L7ApiStatus) error {
 	graphmanPort := 9443
-	tryRequest := true
 	checksum := params.Instance.Annotations["app.l7.traceId"]
 	if gateway.Spec.App.Management.Graphman.DynamicSyncPort != 0 {
 		graphmanPort = gateway.Spec.App.Management.Graphman.DynamicSyncPort
@@ -127,10 +126,11 @@ func deployL7ApiToGateway(ctx context.Context, params Params, gateway *v1.Gatewa
 		podList, err := getGatewayPods(ctx, params, gateway)
 		if err != nil {
 			params.Log.V(2).Info("error retrieving gateway pods", "api", params.Instance.Name, "gateway", gateway.Name, "namespace", params.Instance.Namespace)
-			tryRequest = false
 			return nil
 		}
 
+		pruneL7ApiDeploymentStatus(tag, podList, updatedStatus)
+
 		var graphmanBundleBytes []byte
 
 		if params.Instance.Spec.PortalPublished && params.Instance.Spec.L7Portal != "" {
@@ -169,26 +169,10 @@ func deployL7ApiToGateway(ctx context.Context, params Params, gateway *v1.Gatewa
 
 		for _, pod := range podList.Items {
 			// if the checksum is in the pod condition already, it means the deployment has been done on the gateway pod.
-			for _, us := range updatedStatus.Gateways {
-				if us.Name == pod.Name && us.Deployment == tag {
-					for _, condition := range us.Conditions {
-						if condition.Checksum == checksum && condition.Action == DEPLOY {
-							tryRequest = false
-							continue
-						}
-					}
-				}
-			}
-
-			for _, containerStatus := range pod.Status.ContainerStatuses {
-				if containerStatus.Name == "gateway" && !containerStatus.Ready {
-					tryRequest = false
-					continue
-				}
-			}
+			tryRequest := podReady(pod) && !podHasCondition(tag, pod.Name, checksum, DEPLOY, updatedStatus)
 
 			if tryRequest {
-				endpoint := pod.Status.PodIP + ":" + strconv.Itoa(graphmanPort) + "/graphman"
+				endpoint := util.PodIP(pod.Status.PodIP) + ":" + strconv.Itoa(graphmanPort) + "/graphman"
 				var errorMessage string
 				status := SUCCESS
 				name := gateway.Name
@@ -202,7 +186,7 @@ func deployL7ApiToGateway(ctx context.Context, params Params, gateway *v1.Gatewa
 				}
 
 				params.Log.V(2).Info("applying api", "api", params.Instance.Name, "pod", pod.Name, "namespace", params.Instance.Namespace)
-				err = util.ApplyGraphmanBundle(string(gwSecret.Data["SSG_ADMIN_USERNAME"]), string(gwSecret.Data["SSG_ADMIN_PASSWORD"]), endpoint, "", graphmanBundleBytes)
+				err = util.ApplyGraphmanBundle(ctx, string(gwSecret.Data["SSG_ADMIN_USERNAME"]), string(gwSecret.Data["SSG_ADMIN_PASSWORD"]), endpoint, "", graphmanBundleBytes)
 				if err != nil {
 					status = FAILURE
 					errorMessage = err.Error()
@@ -220,7 +204,6 @@ func deployL7ApiToGateway(ctx context.Context, params Params, gateway *v1.Gatewa
 // un-deploy the L7Api in params from the gateway pods except the pods in which the api has been un-deployed or the pods aren't ready yet.
 func undeployL7ApiToGateway(ctx context.Context, params Params, gateway *v1.Gateway, tag string, updatedStatus *v1alpha1.L7ApiStatus) error {
 	graphmanPort := 9443
-	tryRequest := true
 	checksum := params.Instance.Annotations["app.l7.traceId"]
 	secretNames := []string{}
 	if gateway.Spec.App.Management.Graphman.DynamicSyncPort != 0 {
@@ -245,26 +228,10 @@ func undeployL7ApiToGateway(ctx context.Context, params Params, gateway *v1.Gate
 
 		for _, pod := range podList.Items {
 			// if the checksum is in the pod condition already, it means the un-deployment has been done on the gateway pod.
-			for _, us := range updatedStatus.Gateways {
-				if us.Name == pod.Name && us.Deployment == tag {
-					for _, condition := range us.Conditions {
-						if condition.Checksum == checksum && condition.Action == UNDEPLOY {
-							tryRequest = false
-							continue
-						}
-					}
-				}
-			}
-
-			for _, containerStatus := range pod.Status.ContainerStatuses {
-				if containerStatus.Name == "gateway" && !containerStatus.Ready {
-					tryRequest = false
-					continue
-				}
-			}
+			tryRequest := podReady(pod) && !podHasCondition(tag, pod.Name, checksum, UNDEPLOY, updatedStatus)
 
 			if tryRequest {
-				endpoint := pod.Status.PodIP + ":" + strconv.Itoa(graphmanPort) + "/graphman"
+				endpoint := util.PodIP(pod.Status.PodIP) + ":" + strconv.Itoa(graphmanPort) + "/graphman"
 				status := SUCCESS
 				name := gateway.Name
 				if gateway.Spec.App.Management.SecretName != "" {
@@ -324,6 +291,51 @@ func updateL7ApiDeploymentStatusOnPod(tag string, podName string, checksum strin
 	}
 }
 
+// podReady returns true if a Gateway pod can receive Graphman requests
+func podReady(pod corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+		return false
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == "gateway" && !containerStatus.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// podHasCondition returns true if action has already been attempted on a Gateway pod for checksum
+func podHasCondition(tag string, podName string, checksum string, action string, updatedStatus *v1alpha1.L7ApiStatus) bool {
+	for _, us := range updatedStatus.Gateways {
+		if us.Name == podName && us.Deployment == tag {
+			for _, condition := range us.Conditions {
+				if condition.Checksum == checksum && condition.Action == action {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// pruneL7ApiDeploymentStatus removes the status of Gateway pods that no longer exist
+func pruneL7ApiDeploymentStatus(tag string, podList *corev1.PodList, updatedStatus *v1alpha1.L7ApiStatus) {
+	pods := map[string]bool{}
+	for _, pod := range podList.Items {
+		pods[pod.Name] = true
+	}
+	gateways := []v1alpha1.LinkedGatewayStatus{}
+	for _, gs := range updatedStatus.Gateways {
+		if gs.Deployment == tag && !pods[gs.Name] {
+			continue
+		}
+		gateways = append(gateways, gs)
+	}
+	if len(gateways) != len(updatedStatus.Gateways) {
+		updatedStatus.Gateways = gateways
+	}
+}
+
 // TempStorage writes API Metadata to /tmp/portalapis/<l7PortalName>/apiname.json
 // This does not track the deployment tag which will be resolved in a future update
 // l7api and l7portal should have the same deployment tags, l7portal deployment tags are
@@ -0,0 +1,77 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/caapim/layer7-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testGatewayPod(name string, ready bool) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			PodIP:             "10.0.0.1",
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "gateway", Ready: ready}},
+		},
+	}
+}
+
+func TestPodReady(t *testing.T) {
+	terminating := testGatewayPod("ssg-0", true)
+	terminating.DeletionTimestamp = &metav1.Time{}
+	pending := testGatewayPod("ssg-0", true)
+	pending.Status.Phase = corev1.PodPending
+	noIP := testGatewayPod("ssg-0", true)
+	noIP.Status.PodIP = ""
+	otherContainer := testGatewayPod("ssg-0", true)
+	otherContainer.Status.ContainerStatuses = append(otherContainer.Status.ContainerStatuses, corev1.ContainerStatus{Name: "sidecar", Ready: false})
+
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want bool
+	}{
+		{name: "ready", pod: testGatewayPod("ssg-0", true), want: true},
+		{name: "gateway container not ready", pod: testGatewayPod("ssg-0", false), want: false},
+		{name: "terminating", pod: terminating, want: false},
+		{name: "pending", pod: pending, want: false},
+		{name: "no pod ip", pod: noIP, want: false},
+		{name: "ignores other containers", pod: otherContainer, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := podReady(tt.pod); got != tt.want {
+				t.Errorf("podReady() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneL7ApiDeploymentStatus(t *testing.T) {
+	status := &v1alpha1.L7ApiStatus{Gateways: []v1alpha1.LinkedGatewayStatus{
+		{Name: "ssg-0", Deployment: "ssg"},
+		{Name: "ssg-1", Deployment: "ssg"},
+		{Name: "other-0", Deployment: "other"},
+	}}
+	podList := &corev1.PodList{Items: []corev1.Pod{testGatewayPod("ssg-1", true), testGatewayPod("ssg-2", true)}}
+
+	pruneL7ApiDeploymentStatus("ssg", podList, status)
+
+	if len(status.Gateways) != 2 {
+		t.Fatalf("expected %d gateways, actual %d", 2, len(status.Gateways))
+	}
+	if status.Gateways[0].Name != "ssg-1" {
+		t.Errorf("expected the status of ssg-0 to be removed, actual %s", status.Gateways[0].Name)
+	}
+	if status.Gateways[1].Name != "other-0" {
+		t.Errorf("expected the status of other deployment tags to be kept, actual %s", status.Gateways[1].Name)
+	}
+
+	pruneL7ApiDeploymentStatus("ssg", &corev1.PodList{}, status)
+	if len(status.Gateways) != 1 || status.Gateways[0].Deployment != "other" {
+		t.Errorf("expected every ssg pod status to be removed, actual %v", status.Gateways)
+	}
+}
@@ -0,0 +1,221 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	gatewayreconcile "github.com/caapim/layer7-operator/pkg/gateway/reconcile"
+	"github.com/caapim/layer7-operator/pkg/util"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// exportRetryInterval is how long a failed on demand export waits before it is retried
+const exportRetryInterval = time.Minute
+
+// Export pushes the configuration of the referenced Gateway to a Git repository when an export is due
+// the returned duration is the time until the next scheduled export or failed on demand export retry, 0 if no export is pending
+func Export(ctx context.Context, params Params) (time.Duration, error) {
+	export := params.Instance
+	if !export.Spec.Enabled {
+		return 0, nil
+	}
+
+	interval := time.Duration(export.Spec.IntervalSeconds) * time.Second
+	due := export.Status.LastExport == "" || export.Status.ExportRequest != export.Spec.ExportRequest
+	if !due && interval > 0 {
+		lastExport, err := time.Parse(time.RFC3339, export.Status.LastExport)
+		if err == nil && time.Since(lastExport) < interval {
+			return interval - time.Since(lastExport), nil
+		}
+		due = true
+	}
+	if !due {
+		return 0, nil
+	}
+
+	status := export.Status
+	// scheduled exports wait for the next interval after a failure
+	if interval > 0 {
+		status.LastExport = time.Now().Format(time.RFC3339)
+	}
+
+	commit, pod, entities, err := exportGateway(ctx, params)
+	if err != nil {
+		params.Log.Info("failed to export gateway", "name", export.Name, "gateway", export.Spec.GatewayReference, "namespace", export.Namespace, "message", err.Error())
+		status.Ready = false
+		status.Reason = err.Error()
+		if err := updateStatus(ctx, params, status); err != nil {
+			return 0, err
+		}
+		// on demand exports are not recorded until they succeed and are retried until then
+		if interval == 0 {
+			return exportRetryInterval, nil
+		}
+		return interval, nil
+	}
+
+	params.Log.Info("exported gateway", "name", export.Name, "gateway", export.Spec.GatewayReference, "namespace", export.Namespace, "commit", commit, "entities", entities)
+	status.Ready = true
+	status.Reason = ""
+	status.ExportRequest = export.Spec.ExportRequest
+	status.LastExport = time.Now().Format(time.RFC3339)
+	status.Commit = commit
+	status.Pod = pod
+	status.Entities = entities
+
+	return interval, updateStatus(ctx, params, status)
+}
+
+func exportGateway(ctx context.Context, params Params) (commit string, pod string, entities int, err error) {
+	export := params.Instance
+
+	if strings.Contains(export.Spec.RepositoryReference.Path, "..") {
+		return "", "", 0, fmt.Errorf("invalid path %s", export.Spec.RepositoryReference.Path)
+	}
+
+	gateway := &securityv1.Gateway{}
+	err = params.Client.Get(ctx, types.NamespacedName{Name: export.Spec.GatewayReference, Namespace: export.Namespace}, gateway)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to retrieve gateway %s: %w", export.Spec.GatewayReference, err)
+	}
+
+	repository := &securityv1.Repository{}
+	err = params.Client.Get(ctx, types.NamespacedName{Name: export.Spec.RepositoryReference.Name, Namespace: export.Namespace}, repository)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to retrieve repository %s: %w", export.Spec.RepositoryReference.Name, err)
+	}
+	if repository.Spec.Type != securityv1.RepositoryTypeGit {
+		return "", "", 0, fmt.Errorf("repository %s must be of type git", repository.Name)
+	}
+
+	branch := export.Spec.RepositoryReference.Branch
+	if branch == "" {
+		branch = repository.Spec.Branch
+	}
+	if branch == "" {
+		return "", "", 0, fmt.Errorf("a branch is required to export to repository %s", repository.Name)
+	}
+
+	var username, token, sshKeyPass string
+	var sshKey, knownHosts []byte
+	if repository.Spec.Auth != (securityv1.RepositoryAuth{}) && repository.Spec.Auth.Type != securityv1.RepositoryAuthTypeNone {
+		name := repository.Name
+		if repository.Spec.Auth.ExistingSecretName != "" {
+			name = repository.Spec.Auth.ExistingSecretName
+		}
+		repositorySecret := &corev1.Secret{}
+		err = params.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: repository.Namespace}, repositorySecret)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("failed to retrieve repository secret %s: %w", name, err)
+		}
+		token = string(repositorySecret.Data["TOKEN"])
+		if token == "" {
+			token = string(repositorySecret.Data["PASSWORD"])
+		}
+		username = string(repositorySecret.Data["USERNAME"])
+		sshKey = repositorySecret.Data["SSH_KEY"]
+		sshKeyPass = string(repositorySecret.Data["SSH_KEY_PASS"])
+		knownHosts = repositorySecret.Data["KNOWN_HOSTS"]
+	}
+
+	encpass := export.Spec.Encryption.Passphrase
+	if export.Spec.Encryption.ExistingSecret != "" {
+		encryptionSecret := &corev1.Secret{}
+		err = params.Client.Get(ctx, types.NamespacedName{Name: export.Spec.Encryption.ExistingSecret, Namespace: export.Namespace}, encryptionSecret)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("failed to retrieve encryption secret %s: %w", export.Spec.Encryption.ExistingSecret, err)
+		}
+		encpass = string(encryptionSecret.Data[export.Spec.Encryption.Key])
+	}
+
+	gatewayParams := gatewayreconcile.Params{
+		Client:   params.Client,
+		Scheme:   params.Scheme,
+		Log:      params.Log,
+		Instance: gateway,
+		Platform: params.Platform,
+	}
+	gwUsername, gwPassword, endpoint, pod, err := gatewayreconcile.ManagementTarget(ctx, gatewayParams)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	author := object.Signature{Name: export.Spec.RepositoryReference.Author.Name, Email: export.Spec.RepositoryReference.Author.Email}
+	if author.Name == "" {
+		author.Name = "layer7-operator"
+	}
+	if author.Email == "" {
+		author.Email = "layer7-operator@localhost"
+	}
+	details := []string{}
+	if gateway.Status.Version != "" {
+		details = append(details, "Gateway version: "+gateway.Status.Version)
+	}
+	// the cluster info is informational, the export continues if it can not be retrieved
+	clusterInfo, err := util.GraphmanServerInfo(ctx, gwUsername, gwPassword, endpoint)
+	if err != nil {
+		params.Log.V(2).Info("failed to retrieve gateway cluster info", "name", export.Name, "namespace", export.Namespace, "error", err.Error())
+	} else if clusterInfo.Name != "" {
+		details = append(details, fmt.Sprintf("Gateway cluster: %s (%d nodes)", clusterInfo.Name, len(clusterInfo.Nodes)))
+	}
+	message := fmt.Sprintf("Export %s configuration from %s", gateway.Name, pod)
+	if len(details) > 0 {
+		message = message + "\n\n" + strings.Join(details, "\n")
+	}
+
+	commit, err = util.PushRepository(repository.Spec.Endpoint, username, token, sshKey, sshKeyPass, branch, repository.Spec.RemoteName, export.Name, repository.Spec.Auth.Vendor, string(repository.Spec.Auth.Type), knownHosts, export.Namespace, export.Spec.RepositoryReference.Path, message, author, func(dir string) error {
+		entities, err = util.ExportGraphmanTarget(ctx, gwUsername, gwPassword, endpoint, encpass, dir)
+		return err
+	})
+	if err != nil {
+		return "", "", 0, err
+	}
+	return commit, pod, entities, nil
+}
+
+func updateStatus(ctx context.Context, params Params, status securityv1alpha1.GatewayExportStatus) error {
+	if reflect.DeepEqual(params.Instance.Status, status) {
+		return nil
+	}
+
+	params.Instance.Status = status
+	err := params.Client.Status().Update(ctx, params.Instance)
+	if err != nil {
+		params.Log.V(2).Info("failed to update gateway export status", "name", params.Instance.Name, "namespace", params.Instance.Namespace, "message", err.Error())
+		return err
+	}
+	params.Log.V(2).Info("updated gateway export status", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+	return nil
+}
@@ -0,0 +1,171 @@
+package reconcile
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestExport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"clusterInfo":{"name":"ssg","nodes":[]},"clusterProperties":[{"goid":"1","name":"cwp1","value":"one","checksum":"abc"}]}}`))
+	}))
+	defer server.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "https://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	graphmanPort, _ := strconv.Atoi(port)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+	_ = securityv1alpha1.AddToScheme(scheme)
+
+	gateway := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default"}}
+	gateway.Spec.App.Management.Graphman.DynamicSyncPort = graphmanPort
+	repository := &securityv1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "exports", Namespace: "default"},
+		Spec:       securityv1.RepositorySpec{Enabled: true, Type: securityv1.RepositoryTypeGit, Endpoint: util.NewTestGitRemote(t), Branch: "main"},
+	}
+	export := &securityv1alpha1.GatewayExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssg-export", Namespace: "default"},
+		Spec: securityv1alpha1.GatewayExportSpec{
+			Enabled:             true,
+			GatewayReference:    "ssg",
+			RepositoryReference: securityv1alpha1.ExportRepositoryReference{Name: "exports", Path: "ssg"},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		gateway,
+		repository,
+		export,
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default"}, Data: map[string][]byte{"SSG_ADMIN_USERNAME": []byte("admin"), "SSG_ADMIN_PASSWORD": []byte("7layer")}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ssg-0", Namespace: "default", Labels: util.DefaultLabels("ssg", map[string]string{})},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: host},
+		},
+	).WithStatusSubresource(&securityv1alpha1.GatewayExport{}).Build()
+
+	newParams := func(t *testing.T) Params {
+		current := &securityv1alpha1.GatewayExport{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg-export", Namespace: "default"}, current); err != nil {
+			t.Fatal(err)
+		}
+		return Params{
+			Client:   k8sClient,
+			Scheme:   scheme,
+			Log:      zap.New(zap.UseDevMode(true)),
+			Instance: current,
+		}
+	}
+
+	t.Run("should not record a failed on demand export", func(t *testing.T) {
+		params := newParams(t)
+		params.Instance.Spec.GatewayReference = "missing"
+		requeue, err := Export(context.Background(), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if requeue != exportRetryInterval {
+			t.Errorf("expected the export to be retried after %s, actual %s", exportRetryInterval, requeue)
+		}
+		status := newParams(t).Instance.Status
+		if status.Ready || status.Reason == "" || status.LastExport != "" {
+			t.Errorf("expected the failure to be reported without a last export, actual %+v", status)
+		}
+	})
+
+	t.Run("should push the gateway configuration", func(t *testing.T) {
+		if _, err := Export(context.Background(), newParams(t)); err != nil {
+			t.Fatal(err)
+		}
+		status := newParams(t).Instance.Status
+		if !status.Ready || status.Commit == "" || status.Pod != "ssg-0" || status.Entities != 1 || status.LastExport == "" {
+			t.Fatalf("unexpected status %+v", status)
+		}
+
+		r, err := git.PlainOpen(repository.Spec.Endpoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit, err := r.CommitObject(plumbing.NewHash(status.Commit))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(commit.Message, "Gateway cluster: ssg") {
+			t.Errorf("expected the gateway cluster in the commit message, actual %s", commit.Message)
+		}
+		tree, _ := commit.Tree()
+		found := false
+		_ = tree.Files().ForEach(func(f *object.File) error {
+			if strings.HasPrefix(f.Name, "ssg/clusterProperties/") {
+				found = true
+			}
+			return nil
+		})
+		if !found {
+			t.Errorf("expected cluster properties to be exported to ssg/clusterProperties")
+		}
+	})
+
+	t.Run("should wait for the next interval", func(t *testing.T) {
+		params := newParams(t)
+		params.Instance.Spec.IntervalSeconds = 3600
+		requeue, err := Export(context.Background(), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if requeue <= 0 || requeue > time.Hour {
+			t.Errorf("expected the export to be requeued within the interval, actual %s", requeue)
+		}
+	})
+
+	t.Run("should retry a failed export request", func(t *testing.T) {
+		params := newParams(t)
+		lastCommit := params.Instance.Status.Commit
+		params.Instance.Spec.ExportRequest = "release-2"
+		params.Instance.Spec.GatewayReference = "missing"
+		requeue, err := Export(context.Background(), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if requeue != exportRetryInterval {
+			t.Errorf("expected the export request to be retried after %s, actual %s", exportRetryInterval, requeue)
+		}
+		status := newParams(t).Instance.Status
+		if status.Ready || status.ExportRequest == "release-2" || status.Commit != lastCommit {
+			t.Fatalf("expected the failed export request not to be recorded, actual %+v", status)
+		}
+
+		params = newParams(t)
+		params.Instance.Spec.ExportRequest = "release-2"
+		if _, err := Export(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status = newParams(t).Instance.Status
+		if !status.Ready || status.ExportRequest != "release-2" {
+			t.Errorf("expected the export request to be exported on retry, actual %+v", status)
+		}
+	})
+}
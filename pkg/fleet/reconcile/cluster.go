@@ -0,0 +1,91 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+)
+
+type remoteClient struct {
+	resourceVersion string
+	client          client.Client
+}
+
+// remoteClients are reused until the kubeconfig secret changes
+var (
+	remoteClients   = map[string]remoteClient{}
+	muRemoteClients sync.Mutex
+)
+
+// getRemoteClient returns a client for a member cluster using the kubeconfig secret in the GatewayFleet namespace
+func getRemoteClient(ctx context.Context, params Params, cluster securityv1alpha1.RemoteCluster) (client.Client, error) {
+	key := cluster.KubeconfigSecret.Key
+	if key == "" {
+		key = "kubeconfig"
+	}
+
+	kubeconfigSecret := &corev1.Secret{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: cluster.KubeconfigSecret.Name, Namespace: params.Instance.Namespace}, kubeconfigSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve kubeconfig secret %s: %w", cluster.KubeconfigSecret.Name, err)
+	}
+
+	kubeconfig := kubeconfigSecret.Data[key]
+	if len(kubeconfig) == 0 {
+		return nil, fmt.Errorf("kubeconfig secret %s does not contain key %s", cluster.KubeconfigSecret.Name, key)
+	}
+
+	cacheKey := params.Instance.Namespace + "/" + cluster.KubeconfigSecret.Name + "/" + key
+
+	muRemoteClients.Lock()
+	defer muRemoteClients.Unlock()
+
+	if rc, ok := remoteClients[cacheKey]; ok && rc.resourceVersion == kubeconfigSecret.ResourceVersion {
+		return rc.client, nil
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig in secret %s: %w", cluster.KubeconfigSecret.Name, err)
+	}
+
+	c, err := client.New(restConfig, client.Options{Scheme: params.Scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for cluster %s: %w", cluster.Name, err)
+	}
+
+	remoteClients[cacheKey] = remoteClient{resourceVersion: kubeconfigSecret.ResourceVersion, client: c}
+	return c, nil
+}
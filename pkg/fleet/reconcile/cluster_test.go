@@ -0,0 +1,95 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: member
+  cluster:
+    server: https://member.example.com:6443
+contexts:
+- name: member
+  context:
+    cluster: member
+    user: member
+current-context: member
+users:
+- name: member
+  user:
+    token: test
+`
+
+func TestGetRemoteClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1alpha1.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "member-kubeconfig", Namespace: "default"},
+		Data: map[string][]byte{
+			"kubeconfig": []byte(testKubeconfig),
+			"invalid":    []byte("not a kubeconfig"),
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	params := Params{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: &securityv1alpha1.GatewayFleet{ObjectMeta: metav1.ObjectMeta{Name: "fleet", Namespace: "default"}},
+	}
+	ctx := context.Background()
+
+	t.Run("should create and reuse a client", func(t *testing.T) {
+		cluster := securityv1alpha1.RemoteCluster{Name: "member", KubeconfigSecret: securityv1alpha1.KubeconfigSecret{Name: "member-kubeconfig"}}
+		c, err := getRemoteClient(ctx, params, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cached, err := getRemoteClient(ctx, params, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != cached {
+			t.Errorf("expected client to be reused")
+		}
+
+		secret.Data["kubeconfig"] = []byte(testKubeconfig + "\n")
+		if err := k8sClient.Update(ctx, secret); err != nil {
+			t.Fatal(err)
+		}
+		updated, err := getRemoteClient(ctx, params, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c == updated {
+			t.Errorf("expected a new client after the kubeconfig secret changed")
+		}
+	})
+
+	t.Run("should reject missing or invalid kubeconfig", func(t *testing.T) {
+		for _, kubeconfigSecret := range []securityv1alpha1.KubeconfigSecret{
+			{Name: "missing"},
+			{Name: "member-kubeconfig", Key: "missing"},
+			{Name: "member-kubeconfig", Key: "invalid"},
+		} {
+			_, err := getRemoteClient(ctx, params, securityv1alpha1.RemoteCluster{Name: "member", KubeconfigSecret: kubeconfigSecret})
+			if err == nil {
+				t.Errorf("expected error for kubeconfig secret %s key %s", kubeconfigSecret.Name, kubeconfigSecret.Key)
+			}
+		}
+	})
+}
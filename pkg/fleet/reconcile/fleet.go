@@ -0,0 +1,172 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	gatewayreconcile "github.com/caapim/layer7-operator/pkg/gateway/reconcile"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Fleet(ctx context.Context, params Params) error {
+	fleet := params.Instance
+	if !fleet.Spec.Enabled {
+		return nil
+	}
+
+	repository := &securityv1.Repository{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: fleet.Spec.RepositoryReference.Name, Namespace: fleet.Namespace}, repository)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve repository %s: %w", fleet.Spec.RepositoryReference.Name, err)
+	}
+
+	if !repository.Spec.Enabled || !repository.Status.Ready || repository.Status.Commit == "" {
+		params.Log.V(2).Info("repository not ready", "name", fleet.Name, "repository", repository.Name, "namespace", fleet.Namespace)
+		return nil
+	}
+
+	repoRef := fleet.Spec.RepositoryReference
+	repoRef.Enabled = true
+	repoRef.Type = securityv1.RepositoryReferenceTypeDynamic
+
+	// the encryption secret is resolved here, it does not need to exist in member clusters
+	if repoRef.Encryption.ExistingSecret != "" {
+		encryptionSecret := &corev1.Secret{}
+		err := params.Client.Get(ctx, types.NamespacedName{Name: repoRef.Encryption.ExistingSecret, Namespace: fleet.Namespace}, encryptionSecret)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve encryption secret %s: %w", repoRef.Encryption.ExistingSecret, err)
+		}
+		repoRef.Encryption.Passphrase = string(encryptionSecret.Data[repoRef.Encryption.Key])
+		repoRef.Encryption.ExistingSecret = ""
+	}
+
+	status := securityv1alpha1.GatewayFleetStatus{
+		Commit:      repository.Status.Commit,
+		LastUpdated: fleet.Status.LastUpdated,
+	}
+
+	enabledClusters := 0
+	for _, cluster := range fleet.Spec.Clusters {
+		if !cluster.Enabled {
+			continue
+		}
+		enabledClusters = enabledClusters + 1
+		clusterStatus := reconcileCluster(ctx, params, cluster, repository, repoRef)
+		if clusterStatus.Ready {
+			status.UpdatedClusters = status.UpdatedClusters + 1
+		}
+		status.Clusters = append(status.Clusters, clusterStatus)
+	}
+
+	status.Ready = status.UpdatedClusters == enabledClusters
+
+	return updateStatus(ctx, params, status)
+}
+
+func reconcileCluster(ctx context.Context, params Params, cluster securityv1alpha1.RemoteCluster, repository *securityv1.Repository, repoRef securityv1.RepositoryReference) securityv1alpha1.RemoteClusterStatus {
+	clusterStatus := securityv1alpha1.RemoteClusterStatus{Name: cluster.Name}
+
+	remoteClient, err := getRemoteClient(ctx, params, cluster)
+	if err != nil {
+		params.Log.V(2).Info("failed to connect to member cluster", "name", params.Instance.Name, "cluster", cluster.Name, "message", err.Error())
+		clusterStatus.Reason = err.Error()
+		return clusterStatus
+	}
+
+	namespace := cluster.Namespace
+	if namespace == "" {
+		namespace = params.Instance.Namespace
+	}
+
+	updatedGateways := 0
+	for _, gatewayName := range cluster.Gateways {
+		gatewayStatus := reconcileGateway(ctx, params, remoteClient, cluster, types.NamespacedName{Name: gatewayName, Namespace: namespace}, repository, repoRef)
+		if gatewayStatus.Reason == "" && gatewayStatus.Pods > 0 && gatewayStatus.UpdatedPods == gatewayStatus.Pods {
+			updatedGateways = updatedGateways + 1
+		}
+		clusterStatus.Gateways = append(clusterStatus.Gateways, gatewayStatus)
+	}
+
+	clusterStatus.Ready = updatedGateways == len(cluster.Gateways)
+	return clusterStatus
+}
+
+func reconcileGateway(ctx context.Context, params Params, remoteClient client.Client, cluster securityv1alpha1.RemoteCluster, name types.NamespacedName, repository *securityv1.Repository, repoRef securityv1.RepositoryReference) securityv1alpha1.RemoteGatewayStatus {
+	gatewayStatus := securityv1alpha1.RemoteGatewayStatus{Name: name.Name}
+	log := params.Log.WithValues("cluster", cluster.Name, "gateway", name.Name, "gatewayNamespace", name.Namespace)
+
+	gateway := &securityv1.Gateway{}
+	err := remoteClient.Get(ctx, name, gateway)
+	if err != nil {
+		log.V(2).Info("failed to retrieve gateway", "message", err.Error())
+		gatewayStatus.Reason = err.Error()
+		return gatewayStatus
+	}
+
+	gatewayParams := gatewayreconcile.Params{
+		Client:   remoteClient,
+		Scheme:   params.Scheme,
+		Log:      log,
+		Instance: gateway,
+		Platform: params.Platform,
+	}
+
+	err = gatewayreconcile.SyncRepositoryReference(ctx, gatewayParams, params.Client, repository, repoRef)
+	if err != nil {
+		log.V(2).Info("failed to apply repository", "repository", repository.Name, "message", err.Error())
+		gatewayStatus.Reason = err.Error()
+	}
+
+	gatewayStatus.Pods, gatewayStatus.UpdatedPods, err = gatewayreconcile.RepositoryRolloutStatus(ctx, gatewayParams, repoRef, repository.Status.Commit)
+	if err != nil && gatewayStatus.Reason == "" {
+		gatewayStatus.Reason = err.Error()
+	}
+	return gatewayStatus
+}
+
+func updateStatus(ctx context.Context, params Params, status securityv1alpha1.GatewayFleetStatus) error {
+	if reflect.DeepEqual(params.Instance.Status, status) {
+		return nil
+	}
+
+	status.LastUpdated = time.Now().Format(time.RFC3339)
+	params.Instance.Status = status
+	err := params.Client.Status().Update(ctx, params.Instance)
+	if err != nil {
+		params.Log.V(2).Info("failed to update gateway fleet status", "name", params.Instance.Name, "namespace", params.Instance.Namespace, "message", err.Error())
+		return err
+	}
+	params.Log.V(2).Info("updated gateway fleet status", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+	return nil
+}
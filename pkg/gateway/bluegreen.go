@@ -0,0 +1,103 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package gateway
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// ColorLabel identifies the pods of the blue and green Deployments of a blueGreen Gateway
+const ColorLabel = "security.brcmlabs.com/color"
+
+// BlueGreenChecksumAnnotation is the checksum of the Gateway Deployment that a blue or green Deployment runs
+const BlueGreenChecksumAnnotation = "security.brcmlabs.com/blue-green-checksum"
+
+// BlueGreenEnabled is true for ephemeral Gateways with the blueGreen deploymentStrategy
+func BlueGreenEnabled(gw *securityv1.Gateway) bool {
+	return gw.Spec.App.DeploymentStrategy == securityv1.DeploymentStrategyBlueGreen && !gw.Spec.App.Management.Database.Enabled
+}
+
+// BlueGreenDeploymentName is the Deployment of a colour
+func BlueGreenDeploymentName(gw *securityv1.Gateway, color securityv1.BlueGreenColor) string {
+	return gw.Name + "-" + string(color)
+}
+
+// BlueGreenPodLabels selects the pods of a colour
+func BlueGreenPodLabels(gw *securityv1.Gateway, color securityv1.BlueGreenColor) map[string]string {
+	return util.DefaultLabels(gw.Name, map[string]string{ColorLabel: string(color)})
+}
+
+// IdleColor is the colour that changes are rolled out to
+func IdleColor(gw *securityv1.Gateway) securityv1.BlueGreenColor {
+	if gw.Status.BlueGreen.Active == securityv1.BlueGreenColorBlue {
+		return securityv1.BlueGreenColorGreen
+	}
+	return securityv1.BlueGreenColorBlue
+}
+
+// DeploymentName is the Deployment that serves the Gateway, the active colour of a blueGreen Gateway
+func DeploymentName(gw *securityv1.Gateway) string {
+	if BlueGreenEnabled(gw) && gw.Status.BlueGreen.Active != "" {
+		return BlueGreenDeploymentName(gw, gw.Status.BlueGreen.Active)
+	}
+	return gw.Name
+}
+
+// NewBlueGreenDeployment converts a Gateway Deployment into the Deployment of a colour
+func NewBlueGreenDeployment(gw *securityv1.Gateway, dep *appsv1.Deployment, color securityv1.BlueGreenColor) *appsv1.Deployment {
+	colorDeployment := dep.DeepCopy()
+	colorDeployment.Name = BlueGreenDeploymentName(gw, color)
+	colorDeployment.ObjectMeta.Labels = maps.Clone(dep.ObjectMeta.Labels)
+	colorDeployment.ObjectMeta.Labels[ColorLabel] = string(color)
+	colorDeployment.ObjectMeta.Annotations = maps.Clone(dep.ObjectMeta.Annotations)
+	if colorDeployment.ObjectMeta.Annotations == nil {
+		colorDeployment.ObjectMeta.Annotations = map[string]string{}
+	}
+	colorDeployment.ObjectMeta.Annotations[BlueGreenChecksumAnnotation] = BlueGreenChecksum(dep)
+	colorDeployment.Spec.Selector.MatchLabels = BlueGreenPodLabels(gw, color)
+	colorDeployment.Spec.Template.ObjectMeta.Labels = maps.Clone(dep.Spec.Template.ObjectMeta.Labels)
+	colorDeployment.Spec.Template.ObjectMeta.Labels[ColorLabel] = string(color)
+	return colorDeployment
+}
+
+// BlueGreenChecksum covers the pod template and the configuration checksums of a Gateway Deployment,
+// a change to either is rolled out to the idle colour. Replicas are not included
+func BlueGreenChecksum(dep *appsv1.Deployment) string {
+	data, _ := json.Marshal(struct {
+		Labels   map[string]string `json:"labels"`
+		Template any               `json:"template"`
+	}{dep.ObjectMeta.Labels, dep.Spec.Template})
+	h := sha1.New()
+	h.Write(data)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -256,6 +256,10 @@ func NewDeployment(gw *securityv1.Gateway, platform string) *appsv1.Deployment {
 					SubPath:   "redis.crt",
 				})
 
+				if gw.Spec.App.Redis.Default.Ssl.ClientCertSecretName != "" {
+					volumes, volumeMounts = redisClientCertVolume("default-redis-client-ssl", "redis-client", gw.Spec.App.Redis.Default.Ssl.ClientCertSecretName, volumes, volumeMounts)
+				}
+
 			}
 
 			for _, ac := range gw.Spec.App.Redis.AdditionalConfigs {
@@ -286,6 +290,10 @@ func NewDeployment(gw *securityv1.Gateway, platform string) *appsv1.Deployment {
 						MountPath: "/opt/SecureSpan/Gateway/node/default/etc/bootstrap/providers/" + ac.Name + "-redis.crt",
 						SubPath:   ac.Name + "-redis.crt",
 					})
+
+					if ac.Ssl.ClientCertSecretName != "" {
+						volumes, volumeMounts = redisClientCertVolume(ac.Name+"-redis-client-ssl", ac.Name+"-redis-client", ac.Ssl.ClientCertSecretName, volumes, volumeMounts)
+					}
 				}
 			}
 
@@ -777,34 +785,9 @@ func NewDeployment(gw *securityv1.Gateway, platform string) *appsv1.Deployment {
 	}
 
 	otkInstallInitContainer := false
-	otkDbInitContainer := false
 	otkBootstrapDirectory := "/opt/SecureSpan/Gateway/node/default/etc/bootstrap/bundle/000OTK"
 	otkInitContainerVolumeMounts := []corev1.VolumeMount{}
-	otkInitContainerImage := "docker.io/caapim/otk-install:4.6.2_202402"
-	otkInitContainerImagePullPolicy := corev1.PullIfNotPresent
-	otkInitContainerSecurityContext := corev1.SecurityContext{}
-
-	otkInitContainerSecret := gw.Name + "-otk-db-credentials"
-
-	if gw.Spec.App.Otk.Database.Auth.ExistingSecret != "" {
-		otkInitContainerSecret = gw.Spec.App.Otk.Database.Auth.ExistingSecret
-	}
-
-	if gw.Spec.App.Otk.InitContainerImage != "" {
-		otkInitContainerImage = gw.Spec.App.Otk.InitContainerImage
-	}
-
-	if gw.Spec.App.Otk.InitContainerImagePullPolicy != "" {
-		otkInitContainerImagePullPolicy = gw.Spec.App.Otk.InitContainerImagePullPolicy
-	}
-
-	if gw.Spec.App.ContainerSecurityContext != (corev1.SecurityContext{}) {
-		otkInitContainerSecurityContext = gw.Spec.App.ContainerSecurityContext
-	}
-
-	if gw.Spec.App.Otk.InitContainerSecurityContext != (corev1.SecurityContext{}) {
-		otkInitContainerSecurityContext = gw.Spec.App.Otk.InitContainerSecurityContext
-	}
+	otkInitContainerImage, otkInitContainerImagePullPolicy, otkInitContainerSecurityContext, otkInitContainerSecret := otkInitContainerConfig(gw)
 
 	if gw.Spec.App.Otk.Overrides.Enabled {
 		if gw.Spec.App.Otk.Overrides.BootstrapDirectory != "" {
@@ -826,11 +809,6 @@ func NewDeployment(gw *securityv1.Gateway, platform string) *appsv1.Deployment {
 				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
 		})
-		if gw.Spec.App.Otk.Database.Type == securityv1.OtkDatabaseTypeMySQL || gw.Spec.App.Otk.Database.Type == securityv1.OtkDatabaseTypeOracle {
-			if gw.Spec.App.Otk.Database.Create {
-				otkDbInitContainer = true
-			}
-		}
 	}
 
 	if otkInstallInitContainer {
@@ -870,35 +848,6 @@ func NewDeployment(gw *securityv1.Gateway, platform string) *appsv1.Deployment {
 		})
 	}
 
-	if otkDbInitContainer && gw.Spec.App.Otk.Database.Type != securityv1.OtkDatabaseTypeCassandra && (gw.Spec.App.Otk.Type == securityv1.OtkTypeInternal || gw.Spec.App.Otk.Type == securityv1.OtkTypeSingle) {
-		initContainers = append(initContainers, corev1.Container{
-			Name:            "otk-db-init",
-			Image:           otkInitContainerImage,
-			ImagePullPolicy: otkInitContainerImagePullPolicy,
-			SecurityContext: &otkInitContainerSecurityContext,
-			EnvFrom: []corev1.EnvFromSource{
-				{
-					ConfigMapRef: &corev1.ConfigMapEnvSource{
-						LocalObjectReference: corev1.LocalObjectReference{
-							Name: gw.Name + "-otk-db-init-config",
-						},
-						Optional: &optional,
-					},
-				},
-				{
-					SecretRef: &corev1.SecretEnvSource{
-						LocalObjectReference: corev1.LocalObjectReference{
-							Name: otkInitContainerSecret,
-						},
-						Optional: &optional,
-					},
-				},
-			},
-			TerminationMessagePath:   corev1.TerminationMessagePathDefault,
-			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
-		})
-	}
-
 	resources := corev1.ResourceRequirements{
 		Requests: gw.Spec.App.Resources.Requests,
 		Limits:   gw.Spec.App.Resources.Limits,
@@ -1125,3 +1074,46 @@ func NewDeployment(gw *securityv1.Gateway, platform string) *appsv1.Deployment {
 
 	return dep
 }
+
+// redisClientCertVolume mounts the certificate and key from a kubernetes.io/tls secret alongside sharedstate_client.yaml
+func redisClientCertVolume(name string, fileName string, secretName string, volumes []corev1.Volume, volumeMounts []corev1.VolumeMount) ([]corev1.Volume, []corev1.VolumeMount) {
+	optional := false
+	volumes = append(volumes, corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+				Optional:   &optional,
+				Items: []corev1.KeyToPath{
+					{Key: corev1.TLSCertKey, Path: fileName + ".crt"},
+					{Key: corev1.TLSPrivateKeyKey, Path: fileName + ".key"},
+				},
+			},
+		},
+	})
+	for _, ext := range []string{".crt", ".key"} {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: "/opt/SecureSpan/Gateway/node/default/etc/bootstrap/providers/" + fileName + ext,
+			SubPath:   fileName + ext,
+		})
+	}
+	return volumes, volumeMounts
+}
+
+// RedisClientCertSecrets returns the kubernetes.io/tls secrets that are mounted for Redis mutual TLS
+func RedisClientCertSecrets(gw *securityv1.Gateway) []string {
+	secrets := []string{}
+	if !gw.Spec.App.Redis.Enabled || gw.Spec.App.Redis.ExistingSecret != "" {
+		return secrets
+	}
+	if gw.Spec.App.Redis.Default.Ssl.Enabled && gw.Spec.App.Redis.Default.Ssl.ClientCertSecretName != "" {
+		secrets = append(secrets, gw.Spec.App.Redis.Default.Ssl.ClientCertSecretName)
+	}
+	for _, ac := range gw.Spec.App.Redis.AdditionalConfigs {
+		if ac.Enabled && ac.Ssl.Enabled && ac.Ssl.ClientCertSecretName != "" && !util.Contains(secrets, ac.Ssl.ClientCertSecretName) {
+			secrets = append(secrets, ac.Ssl.ClientCertSecretName)
+		}
+	}
+	return secrets
+}
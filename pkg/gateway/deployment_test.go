@@ -75,3 +75,38 @@ func TestDeploymentWithPorts(t *testing.T) {
 		t.Errorf("expected %d, actual %d", 9443, dep.Spec.Template.Spec.Containers[0].Ports[1].ContainerPort)
 	}
 }
+
+func TestDeploymentWithRedisClientCert(t *testing.T) {
+	gateway := getGatewayWitApp()
+	gateway.Name = "test"
+	gateway.Spec.App.Redis.Enabled = true
+	gateway.Spec.App.Redis.Default.Enabled = true
+	gateway.Spec.App.Redis.Default.Ssl.Enabled = true
+	gateway.Spec.App.Redis.Default.Ssl.ClientCertSecretName = "redis-client-tls"
+	gateway.Spec.App.Redis.AdditionalConfigs = []securityv1.RedisConfiguration{
+		{Enabled: true, Name: "cache", Ssl: securityv1.RedisSsl{Enabled: true, ClientCertSecretName: "redis-client-tls"}},
+		{Enabled: true, Name: "session", Ssl: securityv1.RedisSsl{Enabled: true, ClientCertSecretName: "session-client-tls"}},
+	}
+
+	dep := NewDeployment(&gateway, "kubernetes")
+	volumes := map[string]string{}
+	for _, v := range dep.Spec.Template.Spec.Volumes {
+		if v.Secret != nil {
+			volumes[v.Name] = v.Secret.SecretName
+		}
+	}
+	for name, secretName := range map[string]string{"default-redis-client-ssl": "redis-client-tls", "cache-redis-client-ssl": "redis-client-tls", "session-redis-client-ssl": "session-client-tls"} {
+		if volumes[name] != secretName {
+			t.Errorf("expected volume %s to mount %s, actual %s", name, secretName, volumes[name])
+		}
+	}
+
+	secrets := RedisClientCertSecrets(&gateway)
+	if len(secrets) != 2 || secrets[0] != "redis-client-tls" || secrets[1] != "session-client-tls" {
+		t.Errorf("expected %v, actual %v", []string{"redis-client-tls", "session-client-tls"}, secrets)
+	}
+	gateway.Spec.App.Redis.ExistingSecret = "redis-config"
+	if secrets := RedisClientCertSecrets(&gateway); len(secrets) != 0 {
+		t.Errorf("expected no client certificate secrets with an existing secret, actual %v", secrets)
+	}
+}
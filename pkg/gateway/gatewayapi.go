@@ -0,0 +1,170 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package gateway
+
+import (
+	"strconv"
+	"strings"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const GatewayAPIGroup = "gateway.networking.k8s.io"
+
+// GatewayAPIRouteKinds maps the supported Kubernetes Gateway API route kinds to their group version
+// TLSRoute is only available in the experimental channel
+var GatewayAPIRouteKinds = map[string]schema.GroupVersionKind{
+	"HTTPRoute": {Group: GatewayAPIGroup, Version: "v1", Kind: "HTTPRoute"},
+	"GRPCRoute": {Group: GatewayAPIGroup, Version: "v1", Kind: "GRPCRoute"},
+	"TLSRoute":  {Group: GatewayAPIGroup, Version: "v1alpha2", Kind: "TLSRoute"},
+}
+
+// NewGatewayAPIRoutes returns the Kubernetes Gateway API routes for a Gateway
+// the Gateway API types are not vendored so routes are built as unstructured objects
+func NewGatewayAPIRoutes(gw *securityv1.Gateway) []*unstructured.Unstructured {
+	routes := []*unstructured.Unstructured{}
+	routeSpecs := gw.Spec.App.Ingress.GatewayAPI.Routes
+	if len(routeSpecs) == 0 {
+		routeSpecs = []securityv1.GatewayAPIRoute{{Kind: "HTTPRoute"}}
+	}
+
+	managementRoutes := 0
+	for index, routeSpec := range routeSpecs {
+		suffix := strconv.Itoa(index)
+		if routeSpec.Management {
+			// additional management routes keep their index so route names stay unique
+			suffix = "management"
+			if managementRoutes > 0 {
+				suffix = "management-" + strconv.Itoa(index)
+			}
+			managementRoutes++
+		}
+		routes = append(routes, NewGatewayAPIRoute(gw, routeSpec, suffix))
+	}
+	return routes
+}
+
+func NewGatewayAPIRoute(gw *securityv1.Gateway, routeSpec securityv1.GatewayAPIRoute, suffix string) *unstructured.Unstructured {
+	kind := routeSpec.Kind
+	if kind == "" {
+		kind = "HTTPRoute"
+	}
+
+	serviceName := gw.Name
+	port := servicePort(gw.Spec.App.Service.Ports, "https", 8443)
+	if routeSpec.Management {
+		serviceName = gw.Name + "-management-service"
+		port = servicePort(gw.Spec.App.Management.Service.Ports, "management", 9443)
+	}
+	if routeSpec.Port != 0 {
+		port = routeSpec.Port
+	}
+
+	parentRefs := []interface{}{}
+	for _, parentRef := range gw.Spec.App.Ingress.GatewayAPI.ParentRefs {
+		ref := map[string]interface{}{
+			"group": GatewayAPIGroup,
+			"kind":  "Gateway",
+			"name":  parentRef.Name,
+		}
+		if parentRef.Namespace != "" {
+			ref["namespace"] = parentRef.Namespace
+		}
+		if parentRef.SectionName != "" {
+			ref["sectionName"] = parentRef.SectionName
+		}
+		if parentRef.Port != 0 {
+			ref["port"] = int64(parentRef.Port)
+		}
+		parentRefs = append(parentRefs, ref)
+	}
+
+	backendRefs := []interface{}{
+		map[string]interface{}{
+			"group":  "",
+			"kind":   "Service",
+			"name":   serviceName,
+			"port":   int64(port),
+			"weight": int64(1),
+		},
+	}
+
+	rule := map[string]interface{}{
+		"backendRefs": backendRefs,
+	}
+
+	if kind == "HTTPRoute" {
+		path := "/"
+		if routeSpec.Path != "" {
+			path = routeSpec.Path
+		}
+		rule["matches"] = []interface{}{
+			map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":  "PathPrefix",
+					"value": path,
+				},
+			},
+		}
+	}
+
+	spec := map[string]interface{}{
+		"parentRefs": parentRefs,
+		"rules":      []interface{}{rule},
+	}
+
+	if len(routeSpec.Hostnames) > 0 {
+		hostnames := []interface{}{}
+		for _, hostname := range routeSpec.Hostnames {
+			hostnames = append(hostnames, hostname)
+		}
+		spec["hostnames"] = hostnames
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetGroupVersionKind(GatewayAPIRouteKinds[kind])
+	route.SetName(gw.Name + "-" + strings.ToLower(kind) + "-" + suffix)
+	route.SetNamespace(gw.Namespace)
+	route.SetLabels(util.DefaultLabels(gw.Name, gw.Spec.App.Labels))
+	route.SetAnnotations(gw.Spec.App.Ingress.Annotations)
+	return route
+}
+
+// servicePort returns the port called name or the first port if there isn't one
+func servicePort(ports []securityv1.Ports, name string, defaultPort int32) int32 {
+	for _, p := range ports {
+		if p.Name == name {
+			return p.Port
+		}
+	}
+	if len(ports) > 0 {
+		return ports[0].Port
+	}
+	return defaultPort
+}
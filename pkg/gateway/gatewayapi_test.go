@@ -0,0 +1,100 @@
+package gateway
+
+import (
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNewGatewayAPIRoutes(t *testing.T) {
+	gateway := securityv1.Gateway{}
+	gateway.Name = "test"
+	gateway.Namespace = "default"
+	gateway.Spec.App.Service.Ports = []securityv1.Ports{{Name: "https", Port: 8443}}
+	gateway.Spec.App.Management.Service.Ports = []securityv1.Ports{{Name: "management", Port: 9443}}
+	gateway.Spec.App.Ingress = securityv1.Ingress{
+		Enabled:     true,
+		Type:        "gatewayAPI",
+		Annotations: map[string]string{"test": "annotation"},
+		GatewayAPI: securityv1.GatewayAPI{
+			ParentRefs: []securityv1.GatewayAPIParentReference{{Name: "envoy", Namespace: "envoy-gateway-system", SectionName: "https"}},
+		},
+	}
+
+	t.Run("should default to an HTTPRoute for the gateway service", func(t *testing.T) {
+		routes := NewGatewayAPIRoutes(&gateway)
+		if len(routes) != 1 {
+			t.Fatalf("expected %d routes, actual %d", 1, len(routes))
+		}
+		route := routes[0]
+		if route.GetKind() != "HTTPRoute" || route.GetAPIVersion() != "gateway.networking.k8s.io/v1" {
+			t.Errorf("expected gateway.networking.k8s.io/v1 HTTPRoute, actual %s %s", route.GetAPIVersion(), route.GetKind())
+		}
+		if route.GetName() != "test-httproute-0" {
+			t.Errorf("expected %s, actual %s", "test-httproute-0", route.GetName())
+		}
+		if route.GetAnnotations()["test"] != "annotation" {
+			t.Errorf("expected ingress annotations to be set")
+		}
+
+		parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+		if len(parentRefs) != 1 || parentRefs[0].(map[string]interface{})["name"] != "envoy" || parentRefs[0].(map[string]interface{})["sectionName"] != "https" {
+			t.Errorf("unexpected parentRefs %v", parentRefs)
+		}
+
+		backend := backendRef(t, route)
+		if backend["name"] != "test" || backend["port"] != int64(8443) {
+			t.Errorf("unexpected backendRef %v", backend)
+		}
+	})
+
+	t.Run("should route to the management service", func(t *testing.T) {
+		gateway.Spec.App.Ingress.GatewayAPI.Routes = []securityv1.GatewayAPIRoute{
+			{Kind: "GRPCRoute", Hostnames: []string{"grpc.example.com"}},
+			{Kind: "TLSRoute", Management: true, Hostnames: []string{"management.example.com"}},
+		}
+		routes := NewGatewayAPIRoutes(&gateway)
+		if len(routes) != 2 {
+			t.Fatalf("expected %d routes, actual %d", 2, len(routes))
+		}
+
+		rules, _, _ := unstructured.NestedSlice(routes[0].Object, "spec", "rules")
+		if _, found := rules[0].(map[string]interface{})["matches"]; found {
+			t.Errorf("GRPCRoutes should not have path matches")
+		}
+
+		management := routes[1]
+		if management.GetName() != "test-tlsroute-management" || management.GetAPIVersion() != "gateway.networking.k8s.io/v1alpha2" {
+			t.Errorf("unexpected management route %s %s", management.GetAPIVersion(), management.GetName())
+		}
+		hostnames, _, _ := unstructured.NestedStringSlice(management.Object, "spec", "hostnames")
+		if len(hostnames) != 1 || hostnames[0] != "management.example.com" {
+			t.Errorf("unexpected hostnames %v", hostnames)
+		}
+		backend := backendRef(t, management)
+		if backend["name"] != "test-management-service" || backend["port"] != int64(9443) {
+			t.Errorf("unexpected backendRef %v", backend)
+		}
+	})
+
+	t.Run("should give each management route a unique name", func(t *testing.T) {
+		gateway.Spec.App.Ingress.GatewayAPI.Routes = []securityv1.GatewayAPIRoute{
+			{Kind: "HTTPRoute", Management: true, Path: "/graphman"},
+			{Kind: "HTTPRoute", Management: true, Path: "/restman"},
+		}
+		routes := NewGatewayAPIRoutes(&gateway)
+		if routes[0].GetName() != "test-httproute-management" || routes[1].GetName() != "test-httproute-management-1" {
+			t.Errorf("unexpected management route names %s %s", routes[0].GetName(), routes[1].GetName())
+		}
+	})
+}
+
+func backendRef(t *testing.T, route *unstructured.Unstructured) map[string]interface{} {
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if len(rules) != 1 {
+		t.Fatalf("expected %d rule, actual %d", 1, len(rules))
+	}
+	backendRefs := rules[0].(map[string]interface{})["backendRefs"].([]interface{})
+	return backendRefs[0].(map[string]interface{})
+}
@@ -28,7 +28,7 @@ func NewHPA(gw *securityv1.Gateway) *autoscalingv2.HorizontalPodAutoscaler {
 		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
 			APIVersion: "apps/v1",
 			Kind:       "Deployment",
-			Name:       gw.Name,
+			Name:       DeploymentName(gw),
 		},
 		MinReplicas: gw.Spec.App.Autoscaling.HPA.MinReplicas,
 		MaxReplicas: gw.Spec.App.Autoscaling.HPA.MaxReplicas,
@@ -0,0 +1,152 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package gateway
+
+import (
+	"regexp"
+	"strings"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const MonitoringGroup = "monitoring.coreos.com"
+
+// MonitorKinds maps the supported Prometheus Operator monitor types to their group version
+var MonitorKinds = map[string]schema.GroupVersionKind{
+	"serviceMonitor": {Group: MonitoringGroup, Version: "v1", Kind: "ServiceMonitor"},
+	"podMonitor":     {Group: MonitoringGroup, Version: "v1", Kind: "PodMonitor"},
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// NewMonitor returns a ServiceMonitor or PodMonitor for a Gateway
+// the Prometheus Operator types are not vendored so the monitor is built as an unstructured object
+func NewMonitor(gw *securityv1.Gateway) *unstructured.Unstructured {
+	monitoring := gw.Spec.App.Monitoring
+	monitorType := monitoring.Type
+	if monitorType == "" {
+		monitorType = "serviceMonitor"
+	}
+
+	port := "metrics"
+	if monitoring.Port != "" {
+		port = monitoring.Port
+	}
+	path := "/metrics"
+	if monitoring.Path != "" {
+		path = monitoring.Path
+	}
+	interval := "30s"
+	if monitoring.Interval != "" {
+		interval = monitoring.Interval
+	}
+
+	endpoint := map[string]interface{}{
+		"port":        port,
+		"path":        path,
+		"interval":    interval,
+		"relabelings": monitorRelabelings(gw),
+	}
+	if monitoring.ScrapeTimeout != "" {
+		endpoint["scrapeTimeout"] = monitoring.ScrapeTimeout
+	}
+	if monitoring.Scheme != "" {
+		endpoint["scheme"] = monitoring.Scheme
+	}
+	if monitoring.InsecureSkipVerify {
+		endpoint["tlsConfig"] = map[string]interface{}{
+			"insecureSkipVerify": true,
+		}
+	}
+
+	matchLabels := map[string]interface{}{}
+	for k, v := range util.DefaultLabels(gw.Name, nil) {
+		matchLabels[k] = v
+	}
+
+	spec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": matchLabels,
+		},
+		"namespaceSelector": map[string]interface{}{
+			"matchNames": []interface{}{gw.Namespace},
+		},
+	}
+
+	if monitorType == "podMonitor" {
+		spec["podMetricsEndpoints"] = []interface{}{endpoint}
+	} else {
+		spec["endpoints"] = []interface{}{endpoint}
+	}
+
+	labels := util.DefaultLabels(gw.Name, gw.Spec.App.Labels)
+	for k, v := range monitoring.Labels {
+		labels[k] = v
+	}
+
+	monitor := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	monitor.SetGroupVersionKind(MonitorKinds[monitorType])
+	monitor.SetName(gw.Name)
+	monitor.SetNamespace(gw.Namespace)
+	monitor.SetLabels(labels)
+	return monitor
+}
+
+// monitorRelabelings adds the gateway name, version and the commit of each dynamic repository reference to scraped metrics
+// repository commits are read from the pod annotations that are set when a repository is applied
+func monitorRelabelings(gw *securityv1.Gateway) []interface{} {
+	relabelings := []interface{}{
+		map[string]interface{}{
+			"action":      "replace",
+			"targetLabel": "gateway",
+			"replacement": gw.Name,
+		},
+		map[string]interface{}{
+			"action":      "replace",
+			"targetLabel": "version",
+			"replacement": gw.Spec.Version,
+		},
+	}
+
+	for _, repoRef := range gw.Spec.App.RepositoryReferences {
+		if !repoRef.Enabled || repoRef.Type != securityv1.RepositoryReferenceTypeDynamic {
+			continue
+		}
+		annotation := repoRef.Name + "-" + string(repoRef.Type)
+		relabelings = append(relabelings, map[string]interface{}{
+			"action":       "replace",
+			"sourceLabels": []interface{}{"__meta_kubernetes_pod_annotation_security_brcmlabs_com_" + invalidLabelChars.ReplaceAllString(annotation, "_")},
+			// the leader pod commit has a -leader suffix when singleton extraction is enabled
+			"regex":       "([^-]+)(-leader)?",
+			"replacement": "$1",
+			"targetLabel": "repository_" + strings.ToLower(invalidLabelChars.ReplaceAllString(repoRef.Name, "_")) + "_commit",
+		})
+	}
+	return relabelings
+}
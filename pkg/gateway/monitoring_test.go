@@ -0,0 +1,100 @@
+package gateway
+
+import (
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNewMonitor(t *testing.T) {
+	gateway := securityv1.Gateway{}
+	gateway.Name = "test"
+	gateway.Namespace = "default"
+	gateway.Spec.Version = "11.1.2"
+	gateway.Spec.App.RepositoryReferences = []securityv1.RepositoryReference{
+		{Name: "my-repo", Enabled: true, Type: securityv1.RepositoryReferenceTypeDynamic},
+		{Name: "static-repo", Enabled: true, Type: securityv1.RepositoryReferenceTypeStatic},
+	}
+	gateway.Spec.App.Monitoring = securityv1.Monitoring{
+		Enabled:            true,
+		Scheme:             "https",
+		InsecureSkipVerify: true,
+		Labels:             map[string]string{"release": "prometheus"},
+	}
+
+	t.Run("should default to a ServiceMonitor", func(t *testing.T) {
+		monitor := NewMonitor(&gateway)
+		if monitor.GetKind() != "ServiceMonitor" || monitor.GetAPIVersion() != "monitoring.coreos.com/v1" {
+			t.Errorf("expected monitoring.coreos.com/v1 ServiceMonitor, actual %s %s", monitor.GetAPIVersion(), monitor.GetKind())
+		}
+		if monitor.GetName() != "test" {
+			t.Errorf("expected %s, actual %s", "test", monitor.GetName())
+		}
+		if monitor.GetLabels()["release"] != "prometheus" {
+			t.Errorf("expected monitoring labels to be set")
+		}
+		matchLabels, _, _ := unstructured.NestedStringMap(monitor.Object, "spec", "selector", "matchLabels")
+		if matchLabels["app.kubernetes.io/name"] != "test" {
+			t.Errorf("expected selector to match the gateway, actual %v", matchLabels)
+		}
+
+		endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+		if len(endpoints) != 1 {
+			t.Fatalf("expected %d endpoints, actual %d", 1, len(endpoints))
+		}
+		endpoint := endpoints[0].(map[string]interface{})
+		if endpoint["port"] != "metrics" || endpoint["path"] != "/metrics" || endpoint["interval"] != "30s" {
+			t.Errorf("unexpected endpoint defaults %v", endpoint)
+		}
+		if endpoint["scheme"] != "https" {
+			t.Errorf("expected %s, actual %v", "https", endpoint["scheme"])
+		}
+		insecureSkipVerify, _, _ := unstructured.NestedBool(endpoint, "tlsConfig", "insecureSkipVerify")
+		if !insecureSkipVerify {
+			t.Errorf("expected insecureSkipVerify to be set")
+		}
+	})
+
+	t.Run("should add gateway, version and repository commit relabelings", func(t *testing.T) {
+		monitor := NewMonitor(&gateway)
+		endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+		relabelings := endpoints[0].(map[string]interface{})["relabelings"].([]interface{})
+		if len(relabelings) != 3 {
+			t.Fatalf("expected %d relabelings, actual %d", 3, len(relabelings))
+		}
+		targets := map[string]map[string]interface{}{}
+		for _, r := range relabelings {
+			relabeling := r.(map[string]interface{})
+			targets[relabeling["targetLabel"].(string)] = relabeling
+		}
+		if targets["gateway"]["replacement"] != "test" {
+			t.Errorf("expected gateway label %s, actual %v", "test", targets["gateway"]["replacement"])
+		}
+		if targets["version"]["replacement"] != "11.1.2" {
+			t.Errorf("expected version label %s, actual %v", "11.1.2", targets["version"]["replacement"])
+		}
+		commit, ok := targets["repository_my_repo_commit"]
+		if !ok {
+			t.Fatalf("expected repository commit relabeling, actual %v", targets)
+		}
+		sourceLabels := commit["sourceLabels"].([]interface{})
+		if sourceLabels[0] != "__meta_kubernetes_pod_annotation_security_brcmlabs_com_my_repo_dynamic" {
+			t.Errorf("unexpected source label %v", sourceLabels[0])
+		}
+	})
+
+	t.Run("should create a PodMonitor", func(t *testing.T) {
+		podMonitorGateway := gateway.DeepCopy()
+		podMonitorGateway.Spec.App.Monitoring.Type = "podMonitor"
+		podMonitorGateway.Spec.App.Monitoring.Port = "otel-metrics"
+		monitor := NewMonitor(podMonitorGateway)
+		if monitor.GetKind() != "PodMonitor" {
+			t.Errorf("expected %s, actual %s", "PodMonitor", monitor.GetKind())
+		}
+		endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "podMetricsEndpoints")
+		if len(endpoints) != 1 || endpoints[0].(map[string]interface{})["port"] != "otel-metrics" {
+			t.Errorf("unexpected pod metrics endpoints %v", endpoints)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package gateway
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OtkSchemaJobEnabled is true for single and internal OTK Gateways with a MySQL or Oracle database that the Operator manages
+func OtkSchemaJobEnabled(gw *securityv1.Gateway) bool {
+	otk := gw.Spec.App.Otk
+	if !otk.Enabled || (otk.Type != securityv1.OtkTypeSingle && otk.Type != securityv1.OtkTypeInternal) {
+		return false
+	}
+	if otk.Database.Type != securityv1.OtkDatabaseTypeMySQL && otk.Database.Type != securityv1.OtkDatabaseTypeOracle {
+		return false
+	}
+	return otk.Database.Create || otk.Database.Sql.ManageSchema
+}
+
+// OtkSchemaJobLabels selects the schema Jobs of a Gateway, they do not match the Gateway pod selector
+func OtkSchemaJobLabels(gw *securityv1.Gateway) map[string]string {
+	return util.DefaultLabels(gw.Name+"-otk-schema", map[string]string{})
+}
+
+// OtkSchemaJobName is unique for each OTK initContainerImage so that the schema is migrated once per OTK version
+func OtkSchemaJobName(gw *securityv1.Gateway) string {
+	image, _, _, _ := otkInitContainerConfig(gw)
+	h := sha1.New()
+	h.Write([]byte(image))
+	return gw.Name + "-otk-schema-" + fmt.Sprintf("%x", h.Sum(nil))[:10]
+}
+
+// OtkVersion is the tag of the OTK initContainerImage
+func OtkVersion(gw *securityv1.Gateway) string {
+	image, _, _, _ := otkInitContainerConfig(gw)
+	return image[strings.LastIndex(image, ":")+1:]
+}
+
+// NewOtkSchemaJob creates or upgrades the OTK database with the configuration that was previously used by the otk-db-init initContainer
+func NewOtkSchemaJob(gw *securityv1.Gateway) *batchv1.Job {
+	image, imagePullPolicy, securityContext, secretName := otkInitContainerConfig(gw)
+	optional := true
+	backoffLimit := int32(3)
+	labels := OtkSchemaJobLabels(gw)
+
+	serviceAccountName := gw.Spec.App.ServiceAccount.Name
+	if serviceAccountName == "" {
+		serviceAccountName = gw.Name
+	}
+
+	if gw.Spec.App.ServiceAccount == (securityv1.ServiceAccount{}) {
+		serviceAccountName = "default"
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        OtkSchemaJobName(gw),
+			Namespace:   gw.Namespace,
+			Labels:      labels,
+			Annotations: map[string]string{"security.brcmlabs.com/otk-version": OtkVersion(gw)},
+		},
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ImagePullSecrets:   gw.Spec.App.ImagePullSecrets,
+					Tolerations:        gw.Spec.App.Tolerations,
+					NodeSelector:       gw.Spec.App.NodeSelector,
+					Containers: []corev1.Container{{
+						Name:            "otk-db-init",
+						Image:           image,
+						ImagePullPolicy: imagePullPolicy,
+						SecurityContext: &securityContext,
+						EnvFrom: []corev1.EnvFromSource{
+							{
+								ConfigMapRef: &corev1.ConfigMapEnvSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: gw.Name + "-otk-db-init-config",
+									},
+									Optional: &optional,
+								},
+							},
+							{
+								SecretRef: &corev1.SecretEnvSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: secretName,
+									},
+									Optional: &optional,
+								},
+							},
+						},
+						TerminationMessagePath:   corev1.TerminationMessagePathDefault,
+						TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+					}},
+				},
+			},
+		},
+	}
+}
+
+// otkInitContainerConfig returns the image, pull policy, security context and database credentials secret for OTK containers
+func otkInitContainerConfig(gw *securityv1.Gateway) (string, corev1.PullPolicy, corev1.SecurityContext, string) {
+	image := "docker.io/caapim/otk-install:4.6.2_202402"
+	imagePullPolicy := corev1.PullIfNotPresent
+	securityContext := corev1.SecurityContext{}
+	secretName := gw.Name + "-otk-db-credentials"
+
+	if gw.Spec.App.Otk.Database.Auth.ExistingSecret != "" {
+		secretName = gw.Spec.App.Otk.Database.Auth.ExistingSecret
+	}
+
+	if gw.Spec.App.Otk.InitContainerImage != "" {
+		image = gw.Spec.App.Otk.InitContainerImage
+	}
+
+	if gw.Spec.App.Otk.InitContainerImagePullPolicy != "" {
+		imagePullPolicy = gw.Spec.App.Otk.InitContainerImagePullPolicy
+	}
+
+	if gw.Spec.App.ContainerSecurityContext != (corev1.SecurityContext{}) {
+		securityContext = gw.Spec.App.ContainerSecurityContext
+	}
+
+	if gw.Spec.App.Otk.InitContainerSecurityContext != (corev1.SecurityContext{}) {
+		securityContext = gw.Spec.App.Otk.InitContainerSecurityContext
+	}
+	return image, imagePullPolicy, securityContext, secretName
+}
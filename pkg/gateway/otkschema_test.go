@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNewOtkSchemaJob(t *testing.T) {
+	gateway := getGatewayWitApp()
+	gateway.Name = "test"
+	gateway.Namespace = "testNamespace"
+	gateway.Spec.App.Otk = securityv1.Otk{
+		Enabled:            true,
+		Type:               securityv1.OtkTypeInternal,
+		InitContainerImage: "docker.io/caapim/otk-install:4.6.3",
+		Database: securityv1.OtkDatabase{
+			Type: securityv1.OtkDatabaseTypeMySQL,
+			Sql:  securityv1.OtkSql{ManageSchema: true},
+			Auth: securityv1.OtkDatabaseAuth{ExistingSecret: "otk-db-secret"},
+		},
+	}
+
+	if !OtkSchemaJobEnabled(&gateway) {
+		t.Fatal("expected the schema job to be enabled")
+	}
+
+	job := NewOtkSchemaJob(&gateway)
+	if !strings.HasPrefix(job.Name, "test-otk-schema-") {
+		t.Errorf("unexpected job name %s", job.Name)
+	}
+	if job.Annotations["security.brcmlabs.com/otk-version"] != "4.6.3" {
+		t.Errorf("expected the otk version annotation, actual %v", job.Annotations)
+	}
+	if job.Labels["app.kubernetes.io/name"] == gateway.Name {
+		t.Errorf("expected the job labels not to match the gateway pods")
+	}
+	podSpec := job.Spec.Template.Spec
+	if podSpec.RestartPolicy != corev1.RestartPolicyNever || len(podSpec.Containers) != 1 {
+		t.Fatalf("unexpected pod spec %+v", podSpec)
+	}
+	container := podSpec.Containers[0]
+	if container.Image != "docker.io/caapim/otk-install:4.6.3" || container.EnvFrom[0].ConfigMapRef.Name != "test-otk-db-init-config" || container.EnvFrom[1].SecretRef.Name != "otk-db-secret" {
+		t.Errorf("unexpected container %+v", container)
+	}
+
+	gateway.Spec.App.Otk.InitContainerImage = "docker.io/caapim/otk-install:4.6.4"
+	if NewOtkSchemaJob(&gateway).Name == job.Name {
+		t.Errorf("expected a new job for each otk version")
+	}
+
+	gateway.Spec.App.Otk.Type = securityv1.OtkTypeDMZ
+	if OtkSchemaJobEnabled(&gateway) {
+		t.Errorf("expected the schema job to be disabled for dmz gateways")
+	}
+}
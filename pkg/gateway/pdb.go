@@ -22,8 +22,13 @@ func NewPDB(gw *securityv1.Gateway) *policyv1.PodDisruptionBudget {
 			Kind:       "PodDisruptionBudget",
 		},
 		Spec: policyv1.PodDisruptionBudgetSpec{
+			// the pods of an upgrade Deployment are not covered by the budget
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      UpgradeLabel,
+					Operator: metav1.LabelSelectorOpDoesNotExist,
+				}},
 			},
 		},
 	}
@@ -22,4 +22,7 @@ func TestNewPDB(t *testing.T) {
 	if *pdb.Spec.MaxUnavailable != gateway.Spec.App.PodDisruptionBudget.MaxUnavailable {
 		t.Errorf("expected %d", gateway.Spec.App.PodDisruptionBudget.MaxUnavailable.IntVal)
 	}
+	if len(pdb.Spec.Selector.MatchExpressions) != 1 || pdb.Spec.Selector.MatchExpressions[0].Key != UpgradeLabel {
+		t.Errorf("expected upgrade pods to be excluded from the pod disruption budget")
+	}
 }
@@ -0,0 +1,343 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/gateway"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BlueGreen manages the blue and green Deployments of a blueGreen Gateway. Changes are rolled out to the idle colour,
+// the Service is switched to it once its pods have been configured by the repository reference and external entity
+// operations and the smoke tests have passed. The previous colour keeps running so that the Service can be switched back
+func BlueGreen(ctx context.Context, params Params) error {
+	gw := params.Instance
+	status := *gw.Status.BlueGreen.DeepCopy()
+
+	if gw.Spec.App.BlueGreen.Rollback {
+		if status.RolledBack {
+			return nil
+		}
+		return rollbackBlueGreen(ctx, params, status)
+	}
+	status.RolledBack = false
+
+	desiredDeployment, err := desiredGatewayDeployment(ctx, params)
+	if err != nil {
+		return err
+	}
+	checksum := gateway.BlueGreenChecksum(desiredDeployment)
+
+	// a rolled back Deployment is not rolled out again until the Gateway spec changes
+	if status.Rejected != "" && status.Rejected == checksum {
+		status.Pending = ""
+		status.Reason = "the gateway deployment was rolled back, update the gateway spec to roll out again"
+		return updateBlueGreenStatus(ctx, params, status)
+	}
+	status.Rejected = ""
+
+	var activeDeployment *appsv1.Deployment
+	if status.Active != "" {
+		activeDeployment, err = getBlueGreenDeployment(ctx, params, status.Active)
+		if err != nil {
+			return err
+		}
+		// the active colour can not be verified against anything if it has been removed
+		if activeDeployment == nil {
+			if err := params.Client.Create(ctx, gateway.NewBlueGreenDeployment(gw, desiredDeployment, status.Active)); err != nil {
+				return fmt.Errorf("failed creating deployment: %w", err)
+			}
+			params.Log.Info("created deployment", "deployment", gateway.BlueGreenDeploymentName(gw, status.Active), "name", gw.Name, "namespace", gw.Namespace)
+			status.Checksum = checksum
+			status.Pending = ""
+			return updateBlueGreenStatus(ctx, params, status)
+		}
+	}
+
+	if activeDeployment != nil && status.Checksum == checksum {
+		status.Pending = ""
+		status.Reason = ""
+		if err := updateBlueGreenStatus(ctx, params, status); err != nil {
+			return err
+		}
+		// replicas and other Deployment settings that don't roll the pods are applied to the active colour
+		if gw.Spec.App.Autoscaling.Enabled {
+			desiredDeployment.Spec.Replicas = activeDeployment.Spec.Replicas
+		}
+		return updateDeployment(ctx, params, activeDeployment, gateway.NewBlueGreenDeployment(gw, desiredDeployment, status.Active))
+	}
+
+	// until the first switch the Service is pinned to the pods of the Gateway Deployment so that it does not select the new colour
+	if status.Active == "" && status.Revision == "" {
+		revision, found, err := legacyGatewayRevision(ctx, params)
+		if err != nil {
+			return err
+		}
+		if found {
+			if revision == "" {
+				status.Reason = "waiting for the gateway deployment to finish rolling out"
+				return updateBlueGreenStatus(ctx, params, status)
+			}
+			status.Revision = revision
+			if err := updateBlueGreenStatus(ctx, params, status); err != nil {
+				return err
+			}
+			if err := reconcileServices(ctx, params, []*corev1.Service{gateway.NewService(gw)}); err != nil {
+				return fmt.Errorf("failed to pin service: %w", err)
+			}
+		}
+	}
+
+	idle := gateway.IdleColor(gw)
+	if status.Pending != checksum {
+		status.SmokeTests = nil
+	}
+	status.Pending = checksum
+
+	if activeDeployment != nil && gw.Spec.App.Autoscaling.Enabled {
+		desiredDeployment.Spec.Replicas = activeDeployment.Spec.Replicas
+	}
+	desiredIdleDeployment := gateway.NewBlueGreenDeployment(gw, desiredDeployment, idle)
+
+	idleDeployment, err := getBlueGreenDeployment(ctx, params, idle)
+	if err != nil {
+		return err
+	}
+	if idleDeployment == nil {
+		if err := params.Client.Create(ctx, desiredIdleDeployment); err != nil {
+			return fmt.Errorf("failed creating deployment: %w", err)
+		}
+		params.Log.Info("created deployment", "deployment", desiredIdleDeployment.Name, "name", gw.Name, "namespace", gw.Namespace)
+		status.Reason = fmt.Sprintf("waiting for the %s deployment to be ready", idle)
+		return updateBlueGreenStatus(ctx, params, status)
+	}
+	if idleDeployment.Annotations[gateway.BlueGreenChecksumAnnotation] != checksum {
+		if err := updateDeployment(ctx, params, idleDeployment, desiredIdleDeployment); err != nil {
+			return err
+		}
+		status.Reason = fmt.Sprintf("waiting for the %s deployment to be ready", idle)
+		return updateBlueGreenStatus(ctx, params, status)
+	}
+
+	referencePods, idlePods, err := getBlueGreenPods(ctx, params, idle)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !deploymentRolledOut(idleDeployment):
+		status.Reason = fmt.Sprintf("waiting for the %s deployment to be ready", idle)
+	case !podsConfigured(referencePods, idlePods):
+		status.Reason = fmt.Sprintf("waiting for repository references and external entities to be applied to the %s pods", idle)
+	default:
+		status.SmokeTests = runSmokeTests(ctx, gw.Spec.App.BlueGreen.SmokeTests, idlePods, maxConcurrentPodUpdates(gw))
+		for _, result := range status.SmokeTests {
+			if !result.Passed {
+				if status.Reason != "smoke tests failed" {
+					params.Recorder.Eventf(gw, "Warning", "SmokeTestsFailed", "%s in namespace %s: smoke test %s failed against %s %s", gw.Name, gw.Namespace, result.Name, result.Pod, result.Message)
+				}
+				status.Reason = "smoke tests failed"
+				return updateBlueGreenStatus(ctx, params, status)
+			}
+		}
+		return switchBlueGreen(ctx, params, status, idle, checksum)
+	}
+
+	return updateBlueGreenStatus(ctx, params, status)
+}
+
+// BlueGreenInProgress is true while changes are rolled out to the idle colour
+func BlueGreenInProgress(gw *securityv1.Gateway) bool {
+	return gateway.BlueGreenEnabled(gw) && gw.Status.BlueGreen.Pending != "" && !gw.Spec.App.BlueGreen.Rollback
+}
+
+// switchBlueGreen moves the Service to a colour, the Gateway Deployment that was used before blueGreen was enabled is removed
+func switchBlueGreen(ctx context.Context, params Params, status securityv1.BlueGreenStatus, color securityv1.BlueGreenColor, checksum string) error {
+	gw := params.Instance
+	status.Previous = status.Active
+	status.Active = color
+	status.Checksum = checksum
+	status.Pending = ""
+	status.Reason = ""
+	status.Revision = ""
+	status.LastSwitched = time.Now().Format(time.RFC3339)
+	if err := updateBlueGreenStatus(ctx, params, status); err != nil {
+		return err
+	}
+	if err := reconcileServices(ctx, params, []*corev1.Service{gateway.NewService(gw)}); err != nil {
+		return fmt.Errorf("failed to switch service: %w", err)
+	}
+	params.Recorder.Eventf(gw, "Normal", "BlueGreenSwitched", "%s in namespace %s switched to %s", gw.Name, gw.Namespace, color)
+
+	gatewayDeployment := &appsv1.Deployment{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: gw.Name, Namespace: gw.Namespace}, gatewayDeployment)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := params.Client.Delete(ctx, gatewayDeployment, client.PropagationPolicy("Background")); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to remove deployment: %w", err)
+	}
+	params.Log.Info("removed deployment", "deployment", gatewayDeployment.Name, "name", gw.Name, "namespace", gw.Namespace)
+	return nil
+}
+
+// rollbackBlueGreen moves the Service back to the previous colour if it is still ready
+func rollbackBlueGreen(ctx context.Context, params Params, status securityv1.BlueGreenStatus) error {
+	gw := params.Instance
+	if status.Previous == "" {
+		status.Reason = "there is no previous colour to roll back to"
+		return updateBlueGreenStatus(ctx, params, status)
+	}
+	previousDeployment, err := getBlueGreenDeployment(ctx, params, status.Previous)
+	if err != nil {
+		return err
+	}
+	if previousDeployment == nil || !deploymentRolledOut(previousDeployment) {
+		status.Reason = fmt.Sprintf("waiting for the %s deployment to be ready", status.Previous)
+		return updateBlueGreenStatus(ctx, params, status)
+	}
+
+	status.Rejected = status.Checksum
+	status.Active, status.Previous = status.Previous, status.Active
+	status.Checksum = previousDeployment.Annotations[gateway.BlueGreenChecksumAnnotation]
+	status.Pending = ""
+	status.RolledBack = true
+	status.Reason = ""
+	status.LastSwitched = time.Now().Format(time.RFC3339)
+	if err := updateBlueGreenStatus(ctx, params, status); err != nil {
+		return err
+	}
+	if err := reconcileServices(ctx, params, []*corev1.Service{gateway.NewService(gw)}); err != nil {
+		return fmt.Errorf("failed to switch service: %w", err)
+	}
+	params.Recorder.Eventf(gw, "Warning", "BlueGreenRolledBack", "%s in namespace %s rolled back to %s", gw.Name, gw.Namespace, status.Active)
+	return nil
+}
+
+// removeBlueGreenDeployments removes the blue and green Deployments once the Gateway Deployment has been rolled out
+// after the blueGreen deploymentStrategy was disabled
+func removeBlueGreenDeployments(ctx context.Context, params Params, gatewayDeployment *appsv1.Deployment) error {
+	if !deploymentRolledOut(gatewayDeployment) {
+		return nil
+	}
+	for _, color := range []securityv1.BlueGreenColor{securityv1.BlueGreenColorBlue, securityv1.BlueGreenColorGreen} {
+		colorDeployment, err := getBlueGreenDeployment(ctx, params, color)
+		if err != nil {
+			return err
+		}
+		if colorDeployment == nil {
+			continue
+		}
+		if err := params.Client.Delete(ctx, colorDeployment, client.PropagationPolicy("Background")); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to remove deployment: %w", err)
+		}
+		params.Log.Info("removed deployment", "deployment", colorDeployment.Name, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+	}
+	return updateBlueGreenStatus(ctx, params, securityv1.BlueGreenStatus{})
+}
+
+func updateBlueGreenStatus(ctx context.Context, params Params, status securityv1.BlueGreenStatus) error {
+	if reflect.DeepEqual(status, params.Instance.Status.BlueGreen) {
+		return nil
+	}
+	params.Instance.Status.BlueGreen = status
+	if err := params.Client.Status().Update(ctx, params.Instance); err != nil {
+		return fmt.Errorf("failed to update blue green status: %w", err)
+	}
+	params.Log.V(2).Info("updated blue green status", "active", status.Active, "reason", status.Reason, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+	return nil
+}
+
+// getBlueGreenDeployment returns nil if the Deployment of a colour does not exist
+func getBlueGreenDeployment(ctx context.Context, params Params, color securityv1.BlueGreenColor) (*appsv1.Deployment, error) {
+	colorDeployment := &appsv1.Deployment{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: gateway.BlueGreenDeploymentName(params.Instance, color), Namespace: params.Instance.Namespace}, colorDeployment)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return colorDeployment, nil
+}
+
+// legacyGatewayRevision returns the pod-template-hash of the Gateway Deployment that was used before blueGreen was enabled
+// found is false if there is no such Deployment, revision is empty while it is rolling out
+func legacyGatewayRevision(ctx context.Context, params Params) (revision string, found bool, err error) {
+	gatewayDeployment := &appsv1.Deployment{}
+	err = params.Client.Get(ctx, types.NamespacedName{Name: params.Instance.Name, Namespace: params.Instance.Namespace}, gatewayDeployment)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	podList, err := getGatewayPods(ctx, params)
+	if err != nil {
+		return "", true, err
+	}
+	pods := []corev1.Pod{}
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp == nil && pod.Labels[gateway.ColorLabel] == "" {
+			pods = append(pods, pod)
+		}
+	}
+	return gatewayRevision(gatewayDeployment, pods), true, nil
+}
+
+// getBlueGreenPods splits the Gateway pods into the pods of the idle colour and all other Gateway pods
+func getBlueGreenPods(ctx context.Context, params Params, idle securityv1.BlueGreenColor) ([]corev1.Pod, []corev1.Pod, error) {
+	podList, err := getGatewayPods(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	referencePods := []corev1.Pod{}
+	idlePods := []corev1.Pod{}
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if pod.Labels[gateway.ColorLabel] == string(idle) {
+			idlePods = append(idlePods, pod)
+			continue
+		}
+		referencePods = append(referencePods, pod)
+	}
+	return referencePods, idlePods, nil
+}
@@ -0,0 +1,326 @@
+package reconcile
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/gateway"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func testColorPod(t *testing.T, k8sClient client.Client, name string, color securityv1.BlueGreenColor, annotations map[string]string) {
+	pod := testGatewayPod(name, string(color), false, annotations)
+	pod.Labels[gateway.ColorLabel] = string(color)
+	pod.Status.PodIP = "127.0.0.1"
+	if err := k8sClient.Create(context.Background(), pod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBlueGreen(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	healthy := atomic.Bool{}
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("gateway ok"))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverURL.Port())
+
+	gw := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default", UID: "ssg-uid"}}
+	gw.Spec.App.Image = "caapim/gateway:11.1.00"
+	gw.Spec.App.Replicas = 1
+	gw.Spec.App.DeploymentStrategy = securityv1.DeploymentStrategyBlueGreen
+	gw.Spec.App.BlueGreen.SmokeTests = []securityv1.SmokeTest{{Name: "health", Path: "/health", Scheme: "http", Port: int32(port), ExpectedBody: "ok$"}}
+
+	replicas := int32(1)
+	legacy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "gateway", Image: "caapim/gateway:11.1.00"}}}},
+		},
+	}
+	applied := map[string]string{"security.brcmlabs.com/policies-dynamic": "c1-leader"}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		gw,
+		legacy,
+		gateway.NewService(gw),
+		testGatewayPod("ssg-0", "rev1", false, applied),
+	).WithStatusSubresource(&securityv1.Gateway{}, &appsv1.Deployment{}).Build()
+
+	params := Params{
+		Client:   k8sClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: gw,
+	}
+	updateSpec := func(t *testing.T, update func(gw *securityv1.Gateway)) {
+		update(gw)
+		if err := k8sClient.Update(context.Background(), gw); err != nil {
+			t.Fatal(err)
+		}
+	}
+	serviceSelector := func(t *testing.T) map[string]string {
+		svc := &corev1.Service{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg", Namespace: "default"}, svc); err != nil {
+			t.Fatal(err)
+		}
+		return svc.Spec.Selector
+	}
+
+	t.Run("should wait for the gateway deployment to roll out", func(t *testing.T) {
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if gw.Status.BlueGreen.Pending != "" || gw.Status.BlueGreen.Reason == "" {
+			t.Fatalf("unexpected status %+v", gw.Status.BlueGreen)
+		}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg-blue", Namespace: "default"}, &appsv1.Deployment{}); !k8serrors.IsNotFound(err) {
+			t.Errorf("expected the blue deployment not to be created, actual %v", err)
+		}
+	})
+
+	t.Run("should roll out the blue deployment", func(t *testing.T) {
+		rollOut(t, k8sClient, "ssg", "")
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status := gw.Status.BlueGreen
+		if status.Active != "" || status.Pending == "" || status.Revision != "rev1" || !BlueGreenInProgress(gw) {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		if serviceSelector(t)[appsv1.DefaultDeploymentUniqueLabelKey] != "rev1" {
+			t.Errorf("expected the service to be pinned to the gateway pods, actual %v", serviceSelector(t))
+		}
+		blue := &appsv1.Deployment{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg-blue", Namespace: "default"}, blue); err != nil {
+			t.Fatal(err)
+		}
+		if blue.Spec.Template.Labels[gateway.ColorLabel] != "blue" || blue.Spec.Selector.MatchLabels[gateway.ColorLabel] != "blue" || blue.Annotations[gateway.BlueGreenChecksumAnnotation] != status.Pending {
+			t.Errorf("unexpected blue deployment %+v", blue)
+		}
+	})
+
+	t.Run("should switch the service once the blue pods are configured", func(t *testing.T) {
+		rollOut(t, k8sClient, "ssg-blue", "")
+		testColorPod(t, k8sClient, "ssg-blue-0", securityv1.BlueGreenColorBlue, nil)
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if gw.Status.BlueGreen.Active != "" {
+			t.Fatalf("expected to wait for the blue pods to be configured, actual %+v", gw.Status.BlueGreen)
+		}
+
+		pod := &corev1.Pod{}
+		_ = k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg-blue-0", Namespace: "default"}, pod)
+		pod.Annotations = map[string]string{"security.brcmlabs.com/policies-dynamic": "c1"}
+		if err := k8sClient.Update(context.Background(), pod); err != nil {
+			t.Fatal(err)
+		}
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status := gw.Status.BlueGreen
+		if status.Active != securityv1.BlueGreenColorBlue || status.Pending != "" || len(status.SmokeTests) != 1 || !status.SmokeTests[0].Passed {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		if selector := serviceSelector(t); selector[gateway.ColorLabel] != "blue" || selector[appsv1.DefaultDeploymentUniqueLabelKey] != "" {
+			t.Errorf("expected the service to select the blue pods, actual %v", selector)
+		}
+		if gateway.DeploymentName(gw) != "ssg-blue" {
+			t.Errorf("expected ssg-blue to be the gateway deployment, actual %s", gateway.DeploymentName(gw))
+		}
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg", Namespace: "default"}, &appsv1.Deployment{})
+		if !k8serrors.IsNotFound(err) {
+			t.Errorf("expected the previous gateway deployment to be removed, actual %v", err)
+		}
+	})
+
+	t.Run("should keep the service on blue while the green smoke tests fail", func(t *testing.T) {
+		updateSpec(t, func(gw *securityv1.Gateway) { gw.Spec.App.Image = "caapim/gateway:11.1.01" })
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		green := &appsv1.Deployment{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg-green", Namespace: "default"}, green); err != nil {
+			t.Fatal(err)
+		}
+		if gatewayImage(green) != "caapim/gateway:11.1.01" {
+			t.Errorf("expected the change to be rolled out to green, actual %s", gatewayImage(green))
+		}
+		rollOut(t, k8sClient, "ssg-green", "")
+		testColorPod(t, k8sClient, "ssg-green-0", securityv1.BlueGreenColorGreen, map[string]string{"security.brcmlabs.com/policies-dynamic": "c1"})
+
+		healthy.Store(false)
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status := gw.Status.BlueGreen
+		if status.Active != securityv1.BlueGreenColorBlue || status.Reason != "smoke tests failed" || status.SmokeTests[0].Passed {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		if serviceSelector(t)[gateway.ColorLabel] != "blue" {
+			t.Errorf("expected the service to stay on blue, actual %v", serviceSelector(t))
+		}
+	})
+
+	t.Run("should switch to green once the smoke tests pass", func(t *testing.T) {
+		healthy.Store(true)
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status := gw.Status.BlueGreen
+		if status.Active != securityv1.BlueGreenColorGreen || status.Previous != securityv1.BlueGreenColorBlue || BlueGreenInProgress(gw) {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		if serviceSelector(t)[gateway.ColorLabel] != "green" {
+			t.Errorf("expected the service to select the green pods, actual %v", serviceSelector(t))
+		}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg-blue", Namespace: "default"}, &appsv1.Deployment{}); err != nil {
+			t.Errorf("expected the blue deployment to be kept for rollback, actual %v", err)
+		}
+	})
+
+	t.Run("should roll back to blue", func(t *testing.T) {
+		updateSpec(t, func(gw *securityv1.Gateway) { gw.Spec.App.BlueGreen.Rollback = true })
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status := gw.Status.BlueGreen
+		if status.Active != securityv1.BlueGreenColorBlue || status.Previous != securityv1.BlueGreenColorGreen || !status.RolledBack {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		if serviceSelector(t)[gateway.ColorLabel] != "blue" {
+			t.Errorf("expected the service to select the blue pods, actual %v", serviceSelector(t))
+		}
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if BlueGreenInProgress(gw) || gw.Status.BlueGreen.Active != securityv1.BlueGreenColorBlue {
+			t.Errorf("expected changes to be held while rolled back, actual %+v", gw.Status.BlueGreen)
+		}
+	})
+
+	t.Run("should not roll out the rejected deployment again", func(t *testing.T) {
+		updateSpec(t, func(gw *securityv1.Gateway) { gw.Spec.App.BlueGreen.Rollback = false })
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status := gw.Status.BlueGreen
+		if status.Active != securityv1.BlueGreenColorBlue || status.Rejected == "" || status.Reason == "" || BlueGreenInProgress(gw) {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		if serviceSelector(t)[gateway.ColorLabel] != "blue" {
+			t.Errorf("expected the service to stay on blue, actual %v", serviceSelector(t))
+		}
+
+		updateSpec(t, func(gw *securityv1.Gateway) { gw.Spec.App.Image = "caapim/gateway:11.1.02" })
+		if err := BlueGreen(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status = gw.Status.BlueGreen
+		if status.Rejected != "" || !BlueGreenInProgress(gw) {
+			t.Fatalf("expected a changed spec to be rolled out, actual %+v", status)
+		}
+	})
+}
+
+func TestRunSmokeTests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"` + r.Method + `"}`))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverURL.Port())
+
+	pods := []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "ssg-0"}, Status: corev1.PodStatus{PodIP: "127.0.0.1"}}}
+	smokeTests := []securityv1.SmokeTest{
+		{Path: "/status", Method: "post", Scheme: "http", Port: int32(port), Headers: map[string]string{"Authorization": "Bearer token"}, ExpectedBody: `"status":"POST"`},
+		{Name: "unauthorized", Path: "/status", Scheme: "http", Port: int32(port), ExpectedStatus: http.StatusUnauthorized},
+		{Name: "body", Path: "/status", Scheme: "http", Port: int32(port), Headers: map[string]string{"Authorization": "Bearer token"}, ExpectedBody: "POST"},
+	}
+
+	results := runSmokeTests(context.Background(), smokeTests, pods, 2)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, actual %d", len(results))
+	}
+	if !results[0].Passed || results[0].Name != "/status" || results[0].Pod != "ssg-0" {
+		t.Errorf("unexpected result %+v", results[0])
+	}
+	if !results[1].Passed {
+		t.Errorf("unexpected result %+v", results[1])
+	}
+	if results[2].Passed || results[2].Message == "" {
+		t.Errorf("expected the body check to fail, actual %+v", results[2])
+	}
+}
+
+func TestRunSmokeTestsConcurrently(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			previous := maxInFlight.Load()
+			if current <= previous || maxInFlight.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverURL.Port())
+
+	pods := []corev1.Pod{}
+	for i := 0; i < 4; i++ {
+		pods = append(pods, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "ssg-" + strconv.Itoa(i)}, Status: corev1.PodStatus{PodIP: "127.0.0.1"}})
+	}
+	smokeTests := []securityv1.SmokeTest{
+		{Name: "first", Path: "/status", Scheme: "http", Port: int32(port)},
+		{Name: "second", Path: "/status", Scheme: "http", Port: int32(port)},
+	}
+
+	results := runSmokeTests(context.Background(), smokeTests, pods, 3)
+	if len(results) != 8 {
+		t.Fatalf("expected 8 results, actual %d", len(results))
+	}
+	if results[0].Name != "first" || results[0].Pod != "ssg-0" || results[7].Name != "second" || results[7].Pod != "ssg-3" {
+		t.Errorf("expected results in smoke test then pod order, actual %+v", results)
+	}
+	if maxInFlight.Load() < 2 || maxInFlight.Load() > 3 {
+		t.Errorf("expected between 2 and 3 concurrent smoke tests, actual %d", maxInFlight.Load())
+	}
+}
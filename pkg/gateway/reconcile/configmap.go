@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	securityv1 "github.com/caapim/layer7-operator/api/v1"
 	"github.com/caapim/layer7-operator/pkg/gateway"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -34,10 +33,8 @@ func ConfigMaps(ctx context.Context, params Params) error {
 		desiredConfigMaps = append(desiredConfigMaps, gateway.NewConfigMap(params.Instance, params.Instance.Name+"-otk-shared-init-config"))
 	}
 
-	if params.Instance.Spec.App.Otk.Database.Type == securityv1.OtkDatabaseTypeMySQL || params.Instance.Spec.App.Otk.Database.Type == securityv1.OtkDatabaseTypeOracle {
-		if params.Instance.Spec.App.Otk.Database.Sql.ManageSchema {
-			desiredConfigMaps = append(desiredConfigMaps, gateway.NewConfigMap(params.Instance, params.Instance.Name+"-otk-db-init-config"))
-		}
+	if gateway.OtkSchemaJobEnabled(params.Instance) {
+		desiredConfigMaps = append(desiredConfigMaps, gateway.NewConfigMap(params.Instance, params.Instance.Name+"-otk-db-init-config"))
 	}
 
 	if err := reconcileConfigMaps(ctx, params, desiredConfigMaps); err != nil {
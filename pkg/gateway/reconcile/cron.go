@@ -39,17 +39,13 @@ var syncCache = util.NewSyncCache(3 * time.Second)
 
 func ScheduledJobs(ctx context.Context, params Params) error {
 
-	registerJobs(ctx, params)
-
-	for _, j := range s.Jobs() {
-		for _, t := range j.Tags() {
-			if !j.IsRunning() {
-				params.Log.V(2).Info("starting job", "job", t, "namespace", params.Instance.Namespace)
-				err := s.RunByTag(t)
-				if err != nil {
-					params.Log.V(2).Info("no job with given tag", "job", t, "namespace", params.Instance.Namespace)
-				}
-			}
+	// jobs outlive this reconcile so they are not part of its trace
+	// only jobs that were registered by this reconcile are started immediately, registered jobs keep their interval
+	for _, t := range registerJobs(util.WithoutSpan(ctx), params) {
+		params.Log.V(2).Info("starting job", "job", t, "namespace", params.Instance.Namespace)
+		err := s.RunByTag(t)
+		if err != nil {
+			params.Log.V(2).Info("no job with given tag", "job", t, "namespace", params.Instance.Namespace)
 		}
 	}
 	if !s.IsRunning() {
@@ -58,8 +54,10 @@ func ScheduledJobs(ctx context.Context, params Params) error {
 	return nil
 }
 
-func registerJobs(ctx context.Context, params Params) {
+// registerJobs returns the tags of the jobs that were not registered yet or were registered again with a new interval
+func registerJobs(ctx context.Context, params Params) []string {
 	s.TagsUnique()
+	registered := []string{}
 	otkSyncInterval := 10
 
 	if params.Instance.Spec.App.Otk.RuntimeSyncIntervalSeconds != 0 {
@@ -67,22 +65,74 @@ func registerJobs(ctx context.Context, params Params) {
 	}
 
 	if params.Instance.Spec.App.Otk.Enabled && (params.Instance.Spec.App.Otk.Type == securityv1.OtkTypeDMZ || params.Instance.Spec.App.Otk.Type == securityv1.OtkTypeInternal) {
-		_, err := s.Every(otkSyncInterval).Seconds().Tag(params.Instance.Name+"-sync-otk-policies").Do(syncOtkPolicies, ctx, params)
+		tag := params.Instance.Name + "-sync-otk-policies"
+		_, err := s.Every(otkSyncInterval).Seconds().Tag(tag).Do(syncOtkPolicies, ctx, params)
 
 		if err != nil {
 			params.Log.V(2).Info("otk policy sync job already registered", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+		} else {
+			registered = append(registered, tag)
 		}
 		if params.Instance.Spec.App.Otk.Type == securityv1.OtkTypeDMZ || params.Instance.Spec.App.Otk.Type == securityv1.OtkTypeInternal {
-			_, err = s.Every(otkSyncInterval).Seconds().Tag(params.Instance.Name+"-"+params.Instance.Namespace+"-sync-otk-certificates").Do(syncOtkCertificates, ctx, params)
+			tag = params.Instance.Name + "-" + params.Instance.Namespace + "-sync-otk-certificates"
+			_, err = s.Every(otkSyncInterval).Seconds().Tag(tag).Do(syncOtkCertificates, ctx, params)
 			if err != nil {
 				params.Log.V(2).Info("otk certificate sync job already registered", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+			} else {
+				registered = append(registered, tag)
 			}
-			_, err = s.Every(otkSyncInterval).Seconds().Tag(params.Instance.Name+"-"+params.Instance.Namespace+"-sync-otk-certificate-secret").Do(manageCertificateSecrets, ctx, params)
+			tag = params.Instance.Name + "-" + params.Instance.Namespace + "-sync-otk-certificate-secret"
+			_, err = s.Every(otkSyncInterval).Seconds().Tag(tag).Do(manageCertificateSecrets, ctx, params)
 			if err != nil {
 				params.Log.V(2).Info("otk certificate secret sync job already registered", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+			} else {
+				registered = append(registered, tag)
 			}
 		}
 	}
+
+	leaderElectionInterval := 15
+	if params.Instance.Spec.App.Management.LeaderElection.GracePeriodSeconds != 0 {
+		leaderElectionInterval = max(params.Instance.Spec.App.Management.LeaderElection.GracePeriodSeconds/2, 5)
+	}
+	tag := params.Instance.Name + "-" + params.Instance.Namespace + "-leader-election"
+	removeRescheduledJob(tag, leaderElectionInterval)
+	_, err := s.Every(leaderElectionInterval).Seconds().Tag(tag).Do(leaderElection, ctx, params)
+	if err != nil {
+		params.Log.V(2).Info("leader election job already registered", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+	} else {
+		registered = append(registered, tag)
+	}
+
+	if params.Instance.Spec.App.DriftDetection.Enabled {
+		driftInterval := 300
+		if params.Instance.Spec.App.DriftDetection.IntervalSeconds != 0 {
+			driftInterval = params.Instance.Spec.App.DriftDetection.IntervalSeconds
+		}
+		tag := params.Instance.Name + "-" + params.Instance.Namespace + "-drift-detection"
+		removeRescheduledJob(tag, driftInterval)
+		_, err := s.Every(driftInterval).Seconds().Tag(tag).Do(detectDrift, ctx, params)
+		if err != nil {
+			params.Log.V(2).Info("drift detection job already registered", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+		} else {
+			registered = append(registered, tag)
+		}
+	}
+	return registered
+}
+
+// removeRescheduledJob removes the job with tag if its interval no longer matches so that it is registered again
+func removeRescheduledJob(tag string, interval int) {
+	jobs, err := s.FindJobsByTag(tag)
+	if err != nil {
+		return
+	}
+	for _, job := range jobs {
+		if job.ScheduledInterval() != interval {
+			_ = removeJob(tag)
+			return
+		}
+	}
 }
 
 func removeJob(tag string) error {
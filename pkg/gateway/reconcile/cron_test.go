@@ -0,0 +1,72 @@
+package reconcile
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestRegisterJobs(t *testing.T) {
+	gateway := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "cron", Namespace: "default"}}
+	gateway.Spec.App.DriftDetection.Enabled = true
+	gateway.Spec.App.DriftDetection.IntervalSeconds = 60
+	params := Params{
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: gateway,
+	}
+	driftTag := "cron-default-drift-detection"
+	leaderTag := "cron-default-leader-election"
+	t.Cleanup(func() {
+		_ = removeJob(driftTag)
+		_ = removeJob(leaderTag)
+	})
+
+	scheduledInterval := func(tag string) int {
+		jobs, err := s.FindJobsByTag(tag)
+		if err != nil || len(jobs) != 1 {
+			t.Fatalf("expected 1 job with tag %s, actual %d %v", tag, len(jobs), err)
+		}
+		return jobs[0].ScheduledInterval()
+	}
+
+	t.Run("should register the drift detection job", func(t *testing.T) {
+		if registered := registerJobs(context.Background(), params); !slices.Contains(registered, driftTag) {
+			t.Fatalf("expected %s to be registered, actual %v", driftTag, registered)
+		}
+		if interval := scheduledInterval(driftTag); interval != 60 {
+			t.Errorf("expected an interval of 60 seconds, actual %d", interval)
+		}
+	})
+
+	t.Run("should keep the drift detection job if the interval is unchanged", func(t *testing.T) {
+		if registered := registerJobs(context.Background(), params); slices.Contains(registered, driftTag) {
+			t.Errorf("expected %s to keep its registration, actual %v", driftTag, registered)
+		}
+	})
+
+	t.Run("should register the drift detection job again when the interval changes", func(t *testing.T) {
+		gateway.Spec.App.DriftDetection.IntervalSeconds = 120
+		if registered := registerJobs(context.Background(), params); !slices.Contains(registered, driftTag) {
+			t.Fatalf("expected %s to be registered again, actual %v", driftTag, registered)
+		}
+		if interval := scheduledInterval(driftTag); interval != 120 {
+			t.Errorf("expected an interval of 120 seconds, actual %d", interval)
+		}
+	})
+	t.Run("should register the leader election job again when the grace period changes", func(t *testing.T) {
+		if interval := scheduledInterval(leaderTag); interval != 15 {
+			t.Errorf("expected the default interval of 15 seconds, actual %d", interval)
+		}
+		gateway.Spec.App.Management.LeaderElection.GracePeriodSeconds = 60
+		if registered := registerJobs(context.Background(), params); !slices.Contains(registered, leaderTag) {
+			t.Fatalf("expected %s to be registered again, actual %v", leaderTag, registered)
+		}
+		if interval := scheduledInterval(leaderTag); interval != 30 {
+			t.Errorf("expected an interval of 30 seconds, actual %d", interval)
+		}
+	})
+}
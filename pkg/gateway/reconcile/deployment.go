@@ -29,17 +29,20 @@ package reconcile
 import (
 	"context"
 	"crypto/sha1"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
 
 	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
 	"github.com/caapim/layer7-operator/pkg/gateway"
 	"github.com/caapim/layer7-operator/pkg/util"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -199,27 +202,32 @@ func Deployment(ctx context.Context, params Params) error {
 		applyOpenShiftSecurityDefaults(ctx, params)
 	}
 
-	desiredDeployment := gateway.NewDeployment(params.Instance, params.Platform)
-
-	currentDeployment := &appsv1.Deployment{}
+	// Gateway and OTK version changes are rolled out once the OTK schema has been created or upgraded
+	schemaReady, err := otkSchemaReady(ctx, params)
+	if err != nil {
+		return err
+	}
+	if !schemaReady {
+		reportOtkSchemaHold(params)
+	}
 
-	if err := controllerutil.SetControllerReference(params.Instance, desiredDeployment, params.Scheme); err != nil {
-		return fmt.Errorf("failed to set controller reference: %w", err)
+	if gateway.BlueGreenEnabled(params.Instance) {
+		// every change is a rollout to the idle colour
+		if !schemaReady {
+			return nil
+		}
+		return BlueGreen(ctx, params)
 	}
 
-	err := params.Client.Get(ctx, types.NamespacedName{Name: params.Instance.Name, Namespace: params.Instance.Namespace}, currentDeployment)
+	currentDeployment := &appsv1.Deployment{}
+
+	err = params.Client.Get(ctx, types.NamespacedName{Name: params.Instance.Name, Namespace: params.Instance.Namespace}, currentDeployment)
 
 	if err != nil && k8serrors.IsNotFound(err) {
-		desiredDeployment, err = setLabels(ctx, params, desiredDeployment)
-		if err != nil {
-			return err
-		}
-		desiredDeployment, err = setStateStoreConfig(ctx, params, desiredDeployment)
-		if err != nil {
-			return err
+		if !schemaReady {
+			return nil
 		}
-
-		desiredDeployment, err = setGmanInitContainerVolumeMounts(ctx, params, desiredDeployment)
+		desiredDeployment, err := desiredGatewayDeployment(ctx, params)
 		if err != nil {
 			return err
 		}
@@ -235,32 +243,39 @@ func Deployment(ctx context.Context, params Params) error {
 		return err
 	}
 
-	if params.Instance.Spec.App.Autoscaling.Enabled {
-		desiredDeployment.Spec.Replicas = currentDeployment.Spec.Replicas
+	if err := removeBlueGreenDeployments(ctx, params, currentDeployment); err != nil {
+		return err
 	}
 
-	desiredDeployment, err = setLabels(ctx, params, desiredDeployment)
-	if err != nil {
-		return err
+	// image and configuration changes are held while an upgrade is provisioned
+	if upgradeHoldsDeployment(params.Instance) {
+		params.Log.V(2).Info("deployment changes are held until the gateway upgrade is promoted", "phase", params.Instance.Status.GatewayUpgrade.Phase, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+		return nil
 	}
-	desiredDeployment, err = setStateStoreConfig(ctx, params, desiredDeployment)
+
+	desiredDeployment, err := desiredGatewayDeployment(ctx, params)
 	if err != nil {
 		return err
 	}
-	desiredDeployment, err = setGmanInitContainerVolumeMounts(ctx, params, desiredDeployment)
-	if err != nil {
-		return err
+
+	if params.Instance.Spec.App.Autoscaling.Enabled {
+		desiredDeployment.Spec.Replicas = currentDeployment.Spec.Replicas
 	}
 
+	if !schemaReady && !holdVersionChanges(currentDeployment, desiredDeployment) {
+		return nil
+	}
+
+	return updateDeployment(ctx, params, currentDeployment, desiredDeployment)
+}
+
+// updateDeployment merges the desired Deployment into the current Deployment and patches it if anything changed
+func updateDeployment(ctx context.Context, params Params, currentDeployment *appsv1.Deployment, desiredDeployment *appsv1.Deployment) error {
 	// Start with current deployment (preserves API server defaults) and merge in desired changes
 	updatedDeployment := currentDeployment.DeepCopy()
 	updatedDeployment.Spec = desiredDeployment.Spec
 	updatedDeployment.ObjectMeta.OwnerReferences = desiredDeployment.ObjectMeta.OwnerReferences
 
-	if params.Instance.Spec.App.Autoscaling.Enabled {
-		updatedDeployment.Spec.Replicas = currentDeployment.Spec.Replicas
-	}
-
 	for k, v := range desiredDeployment.ObjectMeta.Annotations {
 		updatedDeployment.ObjectMeta.Annotations[k] = v
 	}
@@ -288,8 +303,8 @@ func Deployment(ctx context.Context, params Params) error {
 	// Skip empty patches
 	if string(patchData) == "{}" || string(patchData) == "{\"metadata\":{\"creationTimestamp\":null}}" {
 		params.Log.V(2).Info("no deployment changes detected, skipping patch",
-			"name", params.Instance.Name,
-			"namespace", params.Instance.Namespace)
+			"name", desiredDeployment.Name,
+			"namespace", desiredDeployment.Namespace)
 		return nil
 	}
 
@@ -303,6 +318,25 @@ func Deployment(ctx context.Context, params Params) error {
 
 }
 
+// desiredGatewayDeployment builds the Gateway Deployment with configmap checksums, state store configuration and repository bootstrap volumes
+func desiredGatewayDeployment(ctx context.Context, params Params) (*appsv1.Deployment, error) {
+	desiredDeployment := gateway.NewDeployment(params.Instance, params.Platform)
+
+	if err := controllerutil.SetControllerReference(params.Instance, desiredDeployment, params.Scheme); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	desiredDeployment, err := setLabels(ctx, params, desiredDeployment)
+	if err != nil {
+		return nil, err
+	}
+	desiredDeployment, err = setStateStoreConfig(ctx, params, desiredDeployment)
+	if err != nil {
+		return nil, err
+	}
+	return setGmanInitContainerVolumeMounts(ctx, params, desiredDeployment)
+}
+
 func setLabels(ctx context.Context, params Params, dep *appsv1.Deployment) (*appsv1.Deployment, error) {
 	restartOnConfigChange := false
 	if params.Instance.Spec.App.RestartOnConfigChange {
@@ -314,9 +348,6 @@ func setLabels(ctx context.Context, params Params, dep *appsv1.Deployment) (*app
 
 		if params.Instance.Spec.App.Otk.Enabled && !params.Instance.Spec.App.Management.Database.Enabled {
 			configMaps = append(configMaps, params.Instance.Name+"-otk-shared-init-config", params.Instance.Name+"-otk-install-init-config")
-			if params.Instance.Spec.App.Otk.Database.Type != securityv1.OtkDatabaseTypeCassandra {
-				configMaps = append(configMaps, params.Instance.Name+"-otk-db-init-config")
-			}
 		}
 
 		for _, cmName := range configMaps {
@@ -357,6 +388,19 @@ func setLabels(ctx context.Context, params Params, dep *appsv1.Deployment) (*app
 			}
 		}
 	}
+	// redis client certificates are mounted with subPath, pods only pick up a rotated certificate when they are replaced
+	for _, secretName := range gateway.RedisClientCertSecrets(params.Instance) {
+		secret := corev1.Secret{}
+		err := params.Client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: params.Instance.Namespace}, &secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve secret: %s", secretName)
+		}
+		dataBytes, _ := json.Marshal(secret.Data)
+		h := sha1.New()
+		h.Write(dataBytes)
+		dep.ObjectMeta.Labels[secretName+"-checksum"] = fmt.Sprintf("%x", h.Sum(nil))
+	}
+
 	commits := ""
 	for _, repoRef := range params.Instance.Spec.App.RepositoryReferences {
 		for _, repoStatus := range params.Instance.Status.RepositoryStatus {
@@ -391,6 +435,22 @@ func setStateStoreConfig(ctx context.Context, params Params, dep *appsv1.Deploym
 			}
 		}
 
+		stateStoreSecrets := map[string]string{}
+		stateStoreTlsSecrets := map[string]string{}
+		// a missing state store does not block the Deployment, its default secret is mounted until it is created
+		for _, stateStore := range stateStores {
+			ss := securityv1alpha1.L7StateStore{ObjectMeta: metav1.ObjectMeta{Name: stateStore}}
+			err := params.Client.Get(ctx, types.NamespacedName{Name: stateStore, Namespace: params.Instance.Namespace}, &ss)
+			if err != nil {
+				if !k8serrors.IsNotFound(err) {
+					return nil, fmt.Errorf("failed to retrieve state store %s: %w", stateStore, err)
+				}
+				params.Log.V(2).Info("state store not found, using its default secret", "statestore", stateStore, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+			}
+			stateStoreSecrets[stateStore] = stateStoreCredentialSecretName(ss)
+			stateStoreTlsSecrets[stateStore] = stateStoreTlsSecretName(ss)
+		}
+
 		for i, ic := range dep.Spec.Template.Spec.InitContainers {
 			if strings.Contains(ic.Name, "graphman-static-init") {
 				for _, stateStore := range stateStores {
@@ -399,7 +459,7 @@ func setStateStoreConfig(ctx context.Context, params Params, dep *appsv1.Deploym
 						MountPath: "/graphman/statestore-secret/" + stateStore,
 					})
 					vs := corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
-						SecretName:  stateStore + "-secret",
+						SecretName:  stateStoreSecrets[stateStore],
 						DefaultMode: &defaultMode,
 						Optional:    &optional,
 					}}
@@ -429,6 +489,22 @@ func setStateStoreConfig(ctx context.Context, params Params, dep *appsv1.Deploym
 						VolumeSource: vs,
 					})
 
+					if stateStoreTlsSecrets[stateStore] != "" {
+						dep.Spec.Template.Spec.InitContainers[i].VolumeMounts = append(dep.Spec.Template.Spec.InitContainers[i].VolumeMounts, corev1.VolumeMount{
+							Name:      stateStore + "-tls-secret",
+							MountPath: "/graphman/statestore-tls/" + stateStore,
+						})
+						vs = corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+							SecretName:  stateStoreTlsSecrets[stateStore],
+							DefaultMode: &defaultMode,
+							Optional:    &optional,
+						}}
+						dep.Spec.Template.Spec.Volumes = append(dep.Spec.Template.Spec.Volumes, corev1.Volume{
+							Name:         stateStore + "-tls-secret",
+							VolumeSource: vs,
+						})
+					}
+
 				}
 			}
 		}
@@ -436,6 +512,36 @@ func setStateStoreConfig(ctx context.Context, params Params, dep *appsv1.Deploym
 	return dep, nil
 }
 
+// stateStoreCredentialSecretName returns the secret that Gateway initContainers use to connect to a state store
+// read-only credentials are preferred over the read-write credentials that the Operator uses.
+func stateStoreCredentialSecretName(ss securityv1alpha1.L7StateStore) string {
+	if ss.Spec.Redis.ReadOnly.ExistingSecret != "" {
+		return ss.Spec.Redis.ReadOnly.ExistingSecret
+	}
+	if ss.Spec.Redis.ReadOnly.Username != "" {
+		return ss.Name + "-readonly-secret"
+	}
+	if ss.Spec.Redis.ExistingSecret != "" {
+		return ss.Spec.Redis.ExistingSecret
+	}
+	return ss.Name + "-secret"
+}
+
+// stateStoreTlsSecretName returns the kubernetes.io/tls secret that contains the state store client certificate
+// an empty string is returned if mutual TLS is not configured.
+func stateStoreTlsSecretName(ss securityv1alpha1.L7StateStore) string {
+	if !ss.Spec.Redis.Tls.Enabled {
+		return ""
+	}
+	if ss.Spec.Redis.Tls.ExistingSecret != "" {
+		return ss.Spec.Redis.Tls.ExistingSecret
+	}
+	if ss.Spec.Redis.Tls.ClientCrt != "" {
+		return ss.Name + "-tls-secret"
+	}
+	return ""
+}
+
 func setGmanInitContainerVolumeMounts(ctx context.Context, params Params, dep *appsv1.Deployment) (*appsv1.Deployment, error) {
 
 	var (
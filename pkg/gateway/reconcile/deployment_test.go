@@ -29,9 +29,16 @@ import (
 	"context"
 	"testing"
 
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
-
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
 func TestNewDeployment(t *testing.T) {
@@ -78,3 +85,98 @@ func TestNewDeployment(t *testing.T) {
 		}
 	})
 }
+
+func TestSetStateStoreConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+	_ = securityv1alpha1.AddToScheme(scheme)
+
+	repo := &securityv1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "default"}}
+	repo.Spec.StateStoreReference = "redis"
+	gw := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default"}}
+	gw.Spec.App.RepositoryReferences = []securityv1.RepositoryReference{{Name: "repo", Enabled: true}}
+
+	newDeployment := func() *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "graphman-static-init"}}
+		return dep
+	}
+	secretName := func(dep *appsv1.Deployment, volume string) string {
+		for _, v := range dep.Spec.Template.Spec.Volumes {
+			if v.Name == volume {
+				return v.Secret.SecretName
+			}
+		}
+		return ""
+	}
+
+	t.Run("should use the default secret when the state store is missing", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(repo).Build()
+		params := Params{Client: k8sClient, Scheme: scheme, Log: zap.New(zap.UseDevMode(true)), Instance: gw}
+		dep, err := setStateStoreConfig(context.Background(), params, newDeployment())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := secretName(dep, "redis-secret"); got != "redis-secret" {
+			t.Errorf("Expected %s, Actual %s", "redis-secret", got)
+		}
+		if got := secretName(dep, "redis-tls-secret"); got != "" {
+			t.Errorf("Expected no tls secret, Actual %s", got)
+		}
+	})
+
+	t.Run("should use the state store secrets", func(t *testing.T) {
+		ss := &securityv1alpha1.L7StateStore{ObjectMeta: metav1.ObjectMeta{Name: "redis", Namespace: "default"}}
+		ss.Spec.Redis.ExistingSecret = "redis-credentials"
+		ss.Spec.Redis.Tls.Enabled = true
+		ss.Spec.Redis.Tls.ExistingSecret = "redis-client-tls"
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(repo, ss).Build()
+		params := Params{Client: k8sClient, Scheme: scheme, Log: zap.New(zap.UseDevMode(true)), Instance: gw}
+		dep, err := setStateStoreConfig(context.Background(), params, newDeployment())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := secretName(dep, "redis-secret"); got != "redis-credentials" {
+			t.Errorf("Expected %s, Actual %s", "redis-credentials", got)
+		}
+		if got := secretName(dep, "redis-tls-secret"); got != "redis-client-tls" {
+			t.Errorf("Expected %s, Actual %s", "redis-client-tls", got)
+		}
+	})
+}
+
+func TestSetLabelsRedisClientCertChecksum(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	gw := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default"}}
+	gw.Spec.App.Redis.Enabled = true
+	gw.Spec.App.Redis.Default.Ssl.Enabled = true
+	gw.Spec.App.Redis.Default.Ssl.ClientCertSecretName = "redis-client-tls"
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "redis-client-tls", Namespace: "default"}, Data: map[string][]byte{"tls.crt": []byte("crt"), "tls.key": []byte("key")}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	params := Params{Client: k8sClient, Scheme: scheme, Log: zap.New(zap.UseDevMode(true)), Instance: gw}
+
+	checksum := func() string {
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{}}}
+		dep, err := setLabels(context.Background(), params, dep)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dep.Labels["redis-client-tls-checksum"]
+	}
+
+	before := checksum()
+	if before == "" {
+		t.Fatal("expected a redis-client-tls-checksum label")
+	}
+	secret.Data["tls.crt"] = []byte("rotated")
+	if err := k8sClient.Update(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	if after := checksum(); after == before {
+		t.Errorf("expected the checksum to change when the client certificate is rotated")
+	}
+}
@@ -0,0 +1,241 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func detectDrift(ctx context.Context, params Params) {
+	jobTag := params.Instance.Name + "-" + params.Instance.Namespace + "-drift-detection"
+	gateway := &securityv1.Gateway{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: params.Instance.Name, Namespace: params.Instance.Namespace}, gateway)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			params.Log.Error(err, "gateway not found", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+			_ = removeJob(jobTag)
+		}
+		return
+	}
+
+	if !gateway.Spec.App.DriftDetection.Enabled {
+		_ = removeJob(jobTag)
+		return
+	}
+
+	params.Instance = gateway
+
+	for _, repoRef := range gateway.Spec.App.RepositoryReferences {
+		if !repoRef.Enabled || repoRef.Type != securityv1.RepositoryReferenceTypeDynamic {
+			continue
+		}
+		err = detectRepositoryDrift(ctx, params, repoRef)
+		if err != nil {
+			params.Log.V(2).Info("drift detection failed", "repository", repoRef.Name, "name", gateway.Name, "namespace", gateway.Namespace, "message", err.Error())
+		}
+	}
+}
+
+// detectRepositoryDrift compares the latest applied commit of a repository reference with each Gateway pod
+// database backed Gateways share state so only the management pod is queried
+func detectRepositoryDrift(ctx context.Context, params Params, repoRef securityv1.RepositoryReference) error {
+	gateway := params.Instance
+
+	repository := &securityv1.Repository{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: repoRef.Name, Namespace: gateway.Namespace}, repository)
+	if err != nil {
+		return err
+	}
+
+	if !repository.Status.Ready {
+		return nil
+	}
+
+	// drift is only checked once the latest commit has been applied
+	statusIndex := -1
+	for i, repoStatus := range gateway.Status.RepositoryStatus {
+		if repoStatus.Name == repoRef.Name {
+			statusIndex = i
+		}
+	}
+	if statusIndex == -1 || gateway.Status.RepositoryStatus[statusIndex].Commit != repository.Status.Commit {
+		return nil
+	}
+
+	bundle, err := desiredRepositoryBundle(ctx, params, repoRef, repository)
+	if err != nil {
+		return err
+	}
+
+	username, password, graphmanPort, err := managementCredentials(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	podList, err := getConfiguredPods(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	drifted := map[string]*securityv1.DriftedEntity{}
+	driftedPods := map[string]bool{}
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+
+		leader := pod.ObjectMeta.Labels["management-access"] == "leader"
+		if gateway.Spec.App.Management.Database.Enabled && !leader {
+			continue
+		}
+		singleton := gateway.Spec.App.Management.Database.Enabled || (gateway.Spec.App.SingletonExtraction && leader)
+
+		endpoint := util.PodIP(pod.Status.PodIP) + ":" + strconv.Itoa(graphmanPort) + "/graphman"
+		entities, err := util.DetectGraphmanDrift(ctx, bundle, singleton, username, password, endpoint)
+		if err != nil {
+			params.Log.V(2).Info("failed to query gateway", "repository", repoRef.Name, "pod", pod.Name, "namespace", gateway.Namespace, "message", err.Error())
+			continue
+		}
+
+		for _, e := range entities {
+			key := e.Type + "/" + e.Name + "/" + string(e.Reason)
+			if _, ok := drifted[key]; !ok {
+				drifted[key] = &securityv1.DriftedEntity{Type: e.Type, Name: e.Name, Reason: string(e.Reason)}
+			}
+			drifted[key].Pods = append(drifted[key].Pods, pod.Name)
+			driftedPods[pod.Name] = true
+		}
+	}
+
+	drift := []securityv1.DriftedEntity{}
+	for _, d := range drifted {
+		drift = append(drift, *d)
+	}
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Type != drift[j].Type {
+			return drift[i].Type < drift[j].Type
+		}
+		return drift[i].Name < drift[j].Name
+	})
+
+	_ = captureDriftMetrics(ctx, params, repoRef.Name, len(drift), len(driftedPods))
+
+	repoStatus := gateway.Status.RepositoryStatus[statusIndex]
+	previousDrift := repoStatus.Drift
+	if len(drift) == 0 {
+		drift = nil
+	} else {
+		params.Log.Info("drift detected", "repository", repoRef.Name, "entities", len(drift), "name", gateway.Name, "namespace", gateway.Namespace)
+	}
+	repoStatus.Drift = drift
+
+	if len(drift) > 0 && gateway.Spec.App.DriftDetection.DriftPolicy == securityv1.DriftPolicyRemediate {
+		err = remediateDrift(ctx, params, repoRef, repository.Status.Commit, podList, driftedPods, bundle, username, password, graphmanPort)
+		if err != nil {
+			params.Log.V(2).Info("failed to remediate drift", "repository", repoRef.Name, "name", gateway.Name, "namespace", gateway.Namespace, "message", err.Error())
+		} else {
+			repoStatus.LastDriftRemediation = time.Now().Format(time.RFC3339)
+		}
+	}
+
+	if reflect.DeepEqual(previousDrift, repoStatus.Drift) && repoStatus.LastDriftRemediation == gateway.Status.RepositoryStatus[statusIndex].LastDriftRemediation {
+		return nil
+	}
+
+	gateway.Status.RepositoryStatus[statusIndex] = repoStatus
+	err = params.Client.Status().Update(ctx, gateway)
+	if err != nil {
+		return err
+	}
+	params.Log.V(2).Info("updated gateway drift status", "repository", repoRef.Name, "name", gateway.Name, "namespace", gateway.Namespace)
+	return nil
+}
+
+// remediateDrift reapplies the repository reference to pods that have drifted
+func remediateDrift(ctx context.Context, params Params, repoRef securityv1.RepositoryReference, commit string, podList *corev1.PodList, driftedPods map[string]bool, bundle []byte, username string, password string, graphmanPort int) error {
+	gateway := params.Instance
+
+	graphmanEncryptionPassphrase := repoRef.Encryption.Passphrase
+	if repoRef.Encryption.ExistingSecret != "" {
+		var err error
+		graphmanEncryptionPassphrase, err = getGraphmanEncryptionPassphrase(ctx, params, repoRef.Encryption.ExistingSecret, repoRef.Encryption.Key)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, pod := range podList.Items {
+		if !driftedPods[pod.Name] {
+			continue
+		}
+		leader := pod.ObjectMeta.Labels["management-access"] == "leader"
+		singleton := gateway.Spec.App.Management.Database.Enabled || (gateway.Spec.App.SingletonExtraction && leader)
+		endpoint := util.PodIP(pod.Status.PodIP) + ":" + strconv.Itoa(graphmanPort) + "/graphman"
+
+		start := time.Now()
+		err := util.ApplyToGraphmanTarget(ctx, bundle, singleton, username, password, endpoint, graphmanEncryptionPassphrase, false)
+		if err != nil {
+			_ = captureGraphmanMetrics(ctx, params, start, pod.Name, "drift remediation", repoRef.Name, commit, true)
+			return fmt.Errorf("failed to reapply %s to pod %s: %w", repoRef.Name, pod.Name, err)
+		}
+		_ = captureGraphmanMetrics(ctx, params, start, pod.Name, "drift remediation", repoRef.Name, commit, false)
+		params.Log.Info("remediated drift", "repository", repoRef.Name, "pod", pod.Name, "name", gateway.Name, "namespace", gateway.Namespace)
+	}
+	return nil
+}
+
+// desiredRepositoryBundle returns the full bundle for the current commit of a repository reference
+// delta and delete bundles are not used here, they only describe what changed between commits
+func desiredRepositoryBundle(ctx context.Context, params Params, repoRef securityv1.RepositoryReference, repository *securityv1.Repository) ([]byte, error) {
+	if repository.Spec.Type == securityv1.RepositoryTypeLocal {
+		return readLocalReference(ctx, repository, params)
+	}
+
+	if len(repoRef.Directories) == 0 {
+		repoRef.Directories = []string{"/"}
+	}
+
+	vanillaGateway := params.Instance.DeepCopy()
+	vanillaGateway.Spec.App.RepositoryReferenceDelete = securityv1.RepositoryReferenceDelete{}
+	cachePath, cacheFileName := determineCacheLocation(repository, vanillaGateway)
+
+	bundle, err := buildBundleFromCache(repository, &repoRef, cachePath, cacheFileName)
+	if err != nil {
+		return buildBundleFromStorageSecret(ctx, repository, &repoRef, params)
+	}
+	return bundle, nil
+}
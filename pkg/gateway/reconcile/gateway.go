@@ -35,7 +35,6 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
-	"net"
 	"os"
 	"reflect"
 	"regexp"
@@ -46,10 +45,14 @@ import (
 	securityv1 "github.com/caapim/layer7-operator/api/v1"
 	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
 	"github.com/caapim/layer7-operator/internal/graphman"
+	"github.com/caapim/layer7-operator/pkg/gateway"
 	"github.com/caapim/layer7-operator/pkg/util"
+	"go.opentelemetry.io/otel/attribute"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -75,6 +78,7 @@ type GatewayUpdateRequest struct {
 	podList                      *corev1.PodList
 	deployment                   *appsv1.Deployment
 	externalEntities             []ExternalEntity
+	verification                 *repositoryVerification
 }
 
 type ExternalEntity struct {
@@ -140,7 +144,7 @@ func NewGwUpdateRequest(ctx context.Context, gateway *securityv1.Gateway, params
 		gwUpdReq.ephemeral = true
 	}
 
-	podList, err := getGatewayPods(ctx, params)
+	podList, err := getConfiguredPods(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -221,15 +225,18 @@ func NewGwUpdateRequest(ctx context.Context, gateway *securityv1.Gateway, params
 			gwUpdReq.repositoryReference.Directories = []string{"/"}
 		}
 
-		if gwUpdReq.repository.Spec.Type == securityv1.RepositoryTypeLocal {
-			gwUpdReq.bundle, err = readLocalReference(ctx, gwUpdReq.repository, params)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			gwUpdReq.bundle, err = buildBundle(ctx, params, gwUpdReq.repositoryReference, gwUpdReq.repository, gwUpdReq.gateway, gwUpdReq.delete)
-			if err != nil {
-				return nil, err
+		// bundles for repositories in other clusters are built by the caller
+		if gwUpdReq.bundle == nil {
+			if gwUpdReq.repository.Spec.Type == securityv1.RepositoryTypeLocal {
+				gwUpdReq.bundle, err = readLocalReference(ctx, gwUpdReq.repository, params)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				gwUpdReq.bundle, err = buildBundle(ctx, params, gwUpdReq.repositoryReference, gwUpdReq.repository, gwUpdReq.gateway, gwUpdReq.delete)
+				if err != nil {
+					return nil, err
+				}
 			}
 		}
 
@@ -823,6 +830,10 @@ func checkRetryScenario(gateway *securityv1.Gateway, repoRefName string, current
 			for _, condition := range repoStatus.Conditions {
 				// Look for failures in the reason field
 				reasonLower := strings.ToLower(condition.Reason)
+				// verify results are not apply failures
+				if condition.Pod != "" {
+					continue
+				}
 				if (strings.Contains(reasonLower, "failed") || strings.Contains(reasonLower, "error")) && condition.Status != "success" {
 					// Found a failure - check if commit has changed
 					if repoStatus.Commit == currentCommit {
@@ -1532,7 +1543,7 @@ func updateGatewayDeployment(ctx context.Context, params Params, gwUpdReq *Gatew
 	leaderAvailable := false
 	for _, pod := range gwUpdReq.podList.Items {
 		if pod.ObjectMeta.Labels["management-access"] == "leader" {
-			endpoint = podIP(pod.Status.PodIP) + ":" + strconv.Itoa(gwUpdReq.graphmanPort) + "/graphman"
+			endpoint = util.PodIP(pod.Status.PodIP) + ":" + strconv.Itoa(gwUpdReq.graphmanPort) + "/graphman"
 			leaderAvailable = true
 		}
 	}
@@ -1641,7 +1652,7 @@ func updateGatewayDeployment(ctx context.Context, params Params, gwUpdReq *Gatew
 		}
 
 		params.Log.V(5).Info(logAction+" "+string(gwUpdReq.bundleType)+" "+gwUpdReq.bundleName, "checksum", gwUpdReq.checksum, "deployment", gwUpdReq.deployment.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
-		err = util.ApplyToGraphmanTarget(gwUpdReq.bundle, true, gwUpdReq.username, gwUpdReq.password, endpoint, gwUpdReq.graphmanEncryptionPassphrase, gwUpdReq.delete)
+		err = util.ApplyToGraphmanTarget(ctx, gwUpdReq.bundle, true, gwUpdReq.username, gwUpdReq.password, endpoint, gwUpdReq.graphmanEncryptionPassphrase, gwUpdReq.delete)
 		if err != nil {
 			failedAction := "failed to apply"
 			if gwUpdReq.delete {
@@ -1683,6 +1694,7 @@ func updateGatewayDeployment(ctx context.Context, params Params, gwUpdReq *Gatew
 
 func updateGatewayPods(ctx context.Context, params Params, gwUpdReq *GatewayUpdateRequest) (err error) {
 	updateStatus := false
+	podUpdates := []podUpdate{}
 	for i, pod := range gwUpdReq.podList.Items {
 
 		singleton := false
@@ -1713,6 +1725,16 @@ func updateGatewayPods(ctx context.Context, params Params, gwUpdReq *GatewayUpda
 			checksum = gwUpdReq.checksum + "-leader"
 		}
 
+		// maintenance tasks are singletons that are only applied to the leader
+		if gwUpdReq.bundleType == BundleTypeOTKDatabaseMaintenance {
+			if pod.ObjectMeta.Labels["management-access"] == "leader" {
+				checksum = gwUpdReq.checksum + "-leader"
+				singleton = true
+			} else {
+				continue
+			}
+		}
+
 		// Skip this pod if it already has the correct checksum and no directory change
 		if currentChecksum == checksum && !gwUpdReq.delete {
 			// Check if there's a directory change for repositories
@@ -1734,15 +1756,6 @@ func updateGatewayPods(ctx context.Context, params Params, gwUpdReq *GatewayUpda
 			}
 		}
 
-		if gwUpdReq.bundleType == BundleTypeOTKDatabaseMaintenance {
-			if pod.ObjectMeta.Labels["management-access"] == "leader" {
-				checksum = gwUpdReq.checksum + "-leader"
-				singleton = true
-			} else {
-				continue
-			}
-		}
-
 		if gwUpdReq.bundleType == BundleTypeRepository {
 			// Skip if already deleted (annotation = "deleted" and repo still disabled)
 			if currentChecksum == "deleted" && !gwUpdReq.repositoryReference.Enabled {
@@ -1836,56 +1849,9 @@ func updateGatewayPods(ctx context.Context, params Params, gwUpdReq *GatewayUpda
 			update = true
 		}
 
-		if update && ready {
+		if update && ready && graphmanHealthy(params, pod, gwUpdReq.graphmanPort) {
 			updateStatus = true
-			endpoint := podIP(pod.Status.PodIP) + ":" + strconv.Itoa(gwUpdReq.graphmanPort) + "/graphman"
-			requestCacheEntry := pod.Name + "-" + gwUpdReq.cacheEntry
-			syncRequest, err := syncCache.Read(requestCacheEntry)
-			tryRequest := true
-			if err != nil {
-				params.Log.V(5).Info("request has not been attempted or cache was flushed", "type", gwUpdReq.bundleType, "name", gwUpdReq.bundleName, "pod", pod.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
-			}
-
-			if syncRequest.Attempts > 0 {
-				params.Log.V(5).Info("request has been attempted in the last 3 seconds, backing off", "type", gwUpdReq.bundleType, "name", gwUpdReq.bundleName, "pod", pod.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
-				tryRequest = false
-				return errors.New("request has been attempted in the last 3 seconds, backing off")
-			}
-
-			if tryRequest {
-				syncCache.Update(util.SyncRequest{RequestName: requestCacheEntry, Attempts: 1}, time.Now().Add(3*time.Second).Unix())
-				start := time.Now()
-
-				logAction := "applying latest"
-				if gwUpdReq.delete {
-					logAction = "removing"
-				}
-
-				params.Log.V(5).Info(logAction+" "+string(gwUpdReq.bundleType)+" "+gwUpdReq.bundleName, "checksum", checksum, "pod", pod.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
-				err = util.ApplyToGraphmanTarget(gwUpdReq.bundle, singleton, gwUpdReq.username, gwUpdReq.password, endpoint, gwUpdReq.graphmanEncryptionPassphrase, gwUpdReq.delete)
-				if err != nil {
-					failedAction := "failed to apply"
-					if gwUpdReq.delete {
-						failedAction = "failed to remove"
-					}
-					params.Log.Info(failedAction+" "+string(gwUpdReq.bundleType)+" "+gwUpdReq.bundleName, "checksum", checksum, "pod", pod.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
-					_ = captureGraphmanMetrics(ctx, params, start, pod.Name, string(gwUpdReq.bundleType), gwUpdReq.bundleName, checksum, true)
-					return err
-				}
-
-				successAction := "applied latest"
-				if gwUpdReq.delete {
-					successAction = "removed"
-				}
-				params.Log.Info(successAction+" "+string(gwUpdReq.bundleType)+" "+gwUpdReq.bundleName, "hash", checksum, "pod", pod.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
-				_ = captureGraphmanMetrics(ctx, params, start, pod.Name, string(gwUpdReq.bundleType), gwUpdReq.bundleName, checksum, false)
-
-				if err := params.Client.Patch(ctx, &gwUpdReq.podList.Items[i],
-					client.RawPatch(types.StrategicMergePatchType, []byte(patch))); err != nil {
-					params.Log.Error(err, "failed to update pod label", "Name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
-					return err
-				}
-			}
+			podUpdates = append(podUpdates, podUpdate{index: i, checksum: checksum, singleton: singleton, patch: patch})
 		} else {
 			// Patch annotation for non-ready pods
 			if (!ready && gwUpdReq.bundleType == BundleTypeClusterProp) ||
@@ -1900,6 +1866,19 @@ func updateGatewayPods(ctx context.Context, params Params, gwUpdReq *GatewayUpda
 		}
 	}
 
+	// bundles are applied to pods in parallel, every pod is attempted and the errors are aggregated
+	err = util.RunConcurrently(maxConcurrentPodUpdates(gwUpdReq.gateway), len(podUpdates), func(u int) error {
+		return applyToGatewayPod(ctx, params, gwUpdReq, podUpdates[u])
+	})
+	if err != nil {
+		return err
+	}
+
+	if gwUpdReq.bundleType == BundleTypeRepository && !gwUpdReq.delete && len(podUpdates) > 0 &&
+		gwUpdReq.repositoryReference.Type == securityv1.RepositoryReferenceTypeDynamic && len(gwUpdReq.repositoryReference.Verify) > 0 {
+		verifyRepositoryReference(ctx, params, gwUpdReq, podUpdates)
+	}
+
 	if updateStatus || (!updateStatus && gwUpdReq.bundleType == BundleTypeClusterProp) || (!updateStatus && gwUpdReq.bundleType == BundleTypeListenPort) {
 		err := updateEntityStatus(ctx, string(gwUpdReq.bundleType), gwUpdReq.bundleName, gwUpdReq.bundle, params)
 		if err != nil {
@@ -1909,6 +1888,83 @@ func updateGatewayPods(ctx context.Context, params Params, gwUpdReq *GatewayUpda
 	return nil
 }
 
+// podUpdate is a Graphman bundle application to a single Gateway pod
+type podUpdate struct {
+	index     int
+	checksum  string
+	singleton bool
+	patch     string
+}
+
+// maxConcurrentPodUpdates returns the number of pods that bundles are applied to in parallel
+func maxConcurrentPodUpdates(gateway *securityv1.Gateway) int {
+	if gateway.Spec.App.MaxConcurrentPodUpdates > 0 {
+		return gateway.Spec.App.MaxConcurrentPodUpdates
+	}
+	maxConcurrentPodUpdates, err := util.GetMaxConcurrentPodUpdates()
+	if err == nil && maxConcurrentPodUpdates > 0 {
+		return maxConcurrentPodUpdates
+	}
+	return 5
+}
+
+func applyToGatewayPod(ctx context.Context, params Params, gwUpdReq *GatewayUpdateRequest, u podUpdate) (err error) {
+	pod := &gwUpdReq.podList.Items[u.index]
+	checksum := u.checksum
+	ctx, span := util.StartSpan(ctx, "applyToGatewayPod",
+		attribute.String("gateway_pod", pod.Name),
+		attribute.String("bundle_type", string(gwUpdReq.bundleType)),
+		attribute.String("bundle_name", gwUpdReq.bundleName),
+		attribute.String("checksum", checksum))
+	defer func() { util.EndSpan(span, err) }()
+
+	endpoint := util.PodIP(pod.Status.PodIP) + ":" + strconv.Itoa(gwUpdReq.graphmanPort) + "/graphman"
+	requestCacheEntry := pod.Name + "-" + gwUpdReq.cacheEntry
+	syncRequest, err := syncCache.Read(requestCacheEntry)
+	if err != nil {
+		params.Log.V(5).Info("request has not been attempted or cache was flushed", "type", gwUpdReq.bundleType, "name", gwUpdReq.bundleName, "pod", pod.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
+	}
+
+	if syncRequest.Attempts > 0 {
+		params.Log.V(5).Info("request has been attempted in the last 3 seconds, backing off", "type", gwUpdReq.bundleType, "name", gwUpdReq.bundleName, "pod", pod.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
+		return errors.New("request has been attempted in the last 3 seconds, backing off")
+	}
+
+	syncCache.Update(util.SyncRequest{RequestName: requestCacheEntry, Attempts: 1}, time.Now().Add(3*time.Second).Unix())
+	start := time.Now()
+
+	logAction := "applying latest"
+	if gwUpdReq.delete {
+		logAction = "removing"
+	}
+
+	params.Log.V(5).Info(logAction+" "+string(gwUpdReq.bundleType)+" "+gwUpdReq.bundleName, "checksum", checksum, "pod", pod.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
+	err = util.ApplyToGraphmanTarget(ctx, gwUpdReq.bundle, u.singleton, gwUpdReq.username, gwUpdReq.password, endpoint, gwUpdReq.graphmanEncryptionPassphrase, gwUpdReq.delete)
+	if err != nil {
+		failedAction := "failed to apply"
+		if gwUpdReq.delete {
+			failedAction = "failed to remove"
+		}
+		params.Log.Info(failedAction+" "+string(gwUpdReq.bundleType)+" "+gwUpdReq.bundleName, "checksum", checksum, "pod", pod.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
+		_ = captureGraphmanMetrics(ctx, params, start, pod.Name, string(gwUpdReq.bundleType), gwUpdReq.bundleName, checksum, true)
+		return fmt.Errorf("%s: %w", pod.Name, err)
+	}
+
+	successAction := "applied latest"
+	if gwUpdReq.delete {
+		successAction = "removed"
+	}
+	params.Log.Info(successAction+" "+string(gwUpdReq.bundleType)+" "+gwUpdReq.bundleName, "hash", checksum, "pod", pod.Name, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
+	_ = captureGraphmanMetrics(ctx, params, start, pod.Name, string(gwUpdReq.bundleType), gwUpdReq.bundleName, checksum, false)
+
+	if err := params.Client.Patch(ctx, pod,
+		client.RawPatch(types.StrategicMergePatchType, []byte(u.patch))); err != nil {
+		params.Log.Error(err, "failed to update pod label", "Name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace)
+		return err
+	}
+	return nil
+}
+
 func readLocalReference(ctx context.Context, repository *securityv1.Repository, params Params) ([]byte, error) {
 	if repository.Spec.LocalReference.SecretName == "" {
 		return nil, fmt.Errorf("%s localReference secret name must be set", repository.Name)
@@ -1947,10 +2003,32 @@ func readStorageSecret(ctx context.Context, repository *securityv1.Repository, p
 	return bundleBytes, nil
 }
 
-// GetGatewayPods returns the pods in a Gateway Deployment
+// getGatewayPods returns the Gateway pods, the pods of an upgrade Deployment are excluded because they do not
+// receive traffic until the upgrade is promoted and must not be elected as the management pod
 func getGatewayPods(ctx context.Context, params Params) (*corev1.PodList, error) {
 	podList := &corev1.PodList{}
 
+	notUpgrade, err := labels.NewRequirement(gateway.UpgradeLabel, selection.DoesNotExist, nil)
+	if err != nil {
+		return podList, err
+	}
+	selector := labels.SelectorFromSet(util.DefaultLabels(params.Instance.Name, map[string]string{})).Add(*notUpgrade)
+
+	listOpts := []client.ListOption{
+		client.InNamespace(params.Instance.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	}
+	if err := params.Client.List(ctx, podList, listOpts...); err != nil {
+		return podList, err
+	}
+	return podList, nil
+}
+
+// getConfiguredPods returns the Gateway pods and the pods of an upgrade Deployment,
+// repository references and external entities are applied to both
+func getConfiguredPods(ctx context.Context, params Params) (*corev1.PodList, error) {
+	podList := &corev1.PodList{}
+
 	listOpts := []client.ListOption{
 		client.InNamespace(params.Instance.Namespace),
 		client.MatchingLabels(util.DefaultLabels(params.Instance.Name, map[string]string{})),
@@ -1963,7 +2041,7 @@ func getGatewayPods(ctx context.Context, params Params) (*corev1.PodList, error)
 
 func getGatewayDeployment(ctx context.Context, params Params) (*appsv1.Deployment, error) {
 	gatewayDeployment := &appsv1.Deployment{}
-	err := params.Client.Get(ctx, types.NamespacedName{Name: params.Instance.Name, Namespace: params.Instance.Namespace}, gatewayDeployment)
+	err := params.Client.Get(ctx, types.NamespacedName{Name: gateway.DeploymentName(params.Instance), Namespace: params.Instance.Namespace}, gatewayDeployment)
 	if err != nil {
 		return gatewayDeployment, err
 	}
@@ -2013,6 +2091,61 @@ func parseGatewaySecret(gwSecret *corev1.Secret) (string, string) {
 	return username, password
 }
 
+// ManagementTarget returns credentials and a Graphman endpoint for a running Gateway pod
+// the management pod is preferred because it has singleton entities like scheduled tasks
+func ManagementTarget(ctx context.Context, params Params) (username string, password string, endpoint string, podName string, err error) {
+	gateway := params.Instance
+	username, password, graphmanPort, err := managementCredentials(ctx, params)
+	if err != nil {
+		return "", "", "", "", err
+	}
+
+	podList, err := getGatewayPods(ctx, params)
+	if err != nil {
+		return "", "", "", "", err
+	}
+
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		if endpoint == "" || pod.ObjectMeta.Labels["management-access"] == "leader" {
+			endpoint = util.PodIP(pod.Status.PodIP) + ":" + strconv.Itoa(graphmanPort) + "/graphman"
+			podName = pod.Name
+		}
+	}
+	if endpoint == "" {
+		return "", "", "", "", fmt.Errorf("no running pods found for gateway %s", gateway.Name)
+	}
+	return username, password, endpoint, podName, nil
+}
+
+// managementCredentials returns the Gateway management credentials and graphman port
+func managementCredentials(ctx context.Context, params Params) (username string, password string, graphmanPort int, err error) {
+	gateway := params.Instance
+	name := gateway.Name
+	if gateway.Spec.App.Management.DisklessConfig.Disabled {
+		name = gateway.Name + "-node-properties"
+	}
+	if gateway.Spec.App.Management.SecretName != "" {
+		name = gateway.Spec.App.Management.SecretName
+	}
+	gwSecret, err := getGatewaySecret(ctx, params, name)
+	if err != nil {
+		return "", "", 0, err
+	}
+	username, password = parseGatewaySecret(gwSecret)
+	if username == "" || password == "" {
+		return "", "", 0, fmt.Errorf("could not retrieve gateway credentials for %s", name)
+	}
+
+	graphmanPort = 9443
+	if gateway.Spec.App.Management.Graphman.DynamicSyncPort != 0 {
+		graphmanPort = gateway.Spec.App.Management.Graphman.DynamicSyncPort
+	}
+	return username, password, graphmanPort, nil
+}
+
 func getStateStoreSecret(ctx context.Context, name string, statestore securityv1alpha1.L7StateStore, params Params) (*corev1.Secret, error) {
 	statestoreSecret := &corev1.Secret{}
 
@@ -2044,36 +2177,14 @@ func GatewayLicense(ctx context.Context, params Params) error {
 		}
 		return nil
 	}
-	return err
-}
-
-func ManagementPod(ctx context.Context, params Params) error {
-	podList, err := getGatewayPods(ctx, params)
 	if err != nil {
 		return err
 	}
+	return updateLicenseStatus(ctx, params, gatewayLicense)
+}
 
-	for p := range podList.Items {
-		if podList.Items[p].Labels["management-access"] == "leader" {
-			if podList.Items[p].DeletionTimestamp == nil {
-				return nil
-			}
-		}
-	}
-	tagged := false
-	for p := range podList.Items {
-		if podList.Items[p].Status.Phase == "Running" && podList.Items[p].DeletionTimestamp == nil && !tagged {
-			patch := []byte(`{"metadata":{"labels":{"management-access": "leader"}}}`)
-			if err := params.Client.Patch(ctx, &podList.Items[p],
-				client.RawPatch(types.StrategicMergePatchType, patch)); err != nil {
-				params.Log.Error(err, "failed to update pod label", "namespace", params.Instance.Namespace, "name", params.Instance.Name)
-				return err
-			}
-			params.Log.V(2).Info("new leader elected", "name", params.Instance.Name, "pod", podList.Items[p].Name, "namespace", params.Instance.Namespace)
-			tagged = true
-		}
-	}
-	return nil
+func ManagementPod(ctx context.Context, params Params) error {
+	return electLeader(ctx, params)
 }
 
 func ReconcileEphemeralGateway(ctx context.Context, params Params, kind string, podList corev1.PodList, gateway *securityv1.Gateway, gwSecret *corev1.Secret, graphmanEncryptionPassphrase string, annotation string, sha1Sum string, otkCerts bool, name string, bundle []byte) error {
@@ -2090,6 +2201,7 @@ func ReconcileEphemeralGateway(ctx context.Context, params Params, kind string,
 	}
 
 	updateStatus := false
+	podUpdates := []podUpdate{}
 
 	for i, pod := range podList.Items {
 		currentSha1Sum := pod.ObjectMeta.Annotations[annotation]
@@ -2115,42 +2227,9 @@ func ReconcileEphemeralGateway(ctx context.Context, params Params, kind string,
 			update = true
 		}
 
-		if update && ready {
+		if update && ready && graphmanHealthy(params, pod, graphmanPort) {
 			updateStatus = true
-			endpoint := podIP(pod.Status.PodIP) + ":" + strconv.Itoa(graphmanPort) + "/graphman"
-
-			requestCacheEntry := pod.Name + "-" + gateway.Name + "-" + name + "-" + sha1Sum
-			syncRequest, err := syncCache.Read(requestCacheEntry)
-			tryRequest := true
-			if err != nil {
-				params.Log.V(2).Info("request has not been attempted or cache was flushed", "action", "sync "+kind, "pod", pod.Name, "name", gateway.Name, "namespace", gateway.Namespace)
-			}
-
-			if syncRequest.Attempts > 0 {
-				params.Log.V(2).Info("request has been attempted in the last 3 seconds, backing off", "hash", sha1Sum, "pod", pod.Name, "name", gateway.Name, "namespace", gateway.Namespace)
-				tryRequest = false
-			}
-
-			if tryRequest {
-				syncCache.Update(util.SyncRequest{RequestName: requestCacheEntry, Attempts: 1}, time.Now().Add(3*time.Second).Unix())
-				start := time.Now()
-				params.Log.V(2).Info("applying latest "+kind, "hash", sha1Sum, "pod", pod.Name, "name", gateway.Name, "namespace", gateway.Namespace)
-				err = util.ApplyGraphmanBundle(username, password, endpoint, graphmanEncryptionPassphrase, bundle)
-				if err != nil {
-					params.Log.Info("failed to apply "+kind, "hash", sha1Sum, "pod", pod.Name, "name", gateway.Name, "namespace", gateway.Namespace)
-					_ = captureGraphmanMetrics(ctx, params, start, pod.Name, kind, name, sha1Sum, true)
-					return err
-				}
-				_ = captureGraphmanMetrics(ctx, params, start, pod.Name, kind, name, sha1Sum, false)
-				params.Log.Info("applied latest "+kind, "hash", sha1Sum, "pod", pod.Name, "name", gateway.Name, "namespace", gateway.Namespace)
-
-				if err := params.Client.Patch(ctx, &podList.Items[i],
-					client.RawPatch(types.StrategicMergePatchType, []byte(patch))); err != nil {
-					params.Log.Error(err, "failed to update pod label", "Name", gateway.Name, "namespace", gateway.Namespace)
-					return err
-				}
-
-			}
+			podUpdates = append(podUpdates, podUpdate{index: i, checksum: sha1Sum, singleton: true, patch: patch})
 		}
 
 		// if the Gateway is not ready then cluster properties and listenPorts have already been applied via bootsrap
@@ -2163,6 +2242,28 @@ func ReconcileEphemeralGateway(ctx context.Context, params Params, kind string,
 		}
 	}
 
+	// ephemeral bundles are applied in full to every pod, singleton entities are not removed
+	gwUpdReq := &GatewayUpdateRequest{
+		graphmanPort:                 graphmanPort,
+		graphmanEncryptionPassphrase: graphmanEncryptionPassphrase,
+		bundle:                       bundle,
+		bundleName:                   name,
+		bundleType:                   BundleType(kind),
+		username:                     username,
+		password:                     password,
+		cacheEntry:                   gateway.Name + "-" + name + "-" + sha1Sum,
+		gateway:                      gateway,
+		ephemeral:                    true,
+		otkCerts:                     otkCerts,
+		podList:                      &podList,
+	}
+	err := util.RunConcurrently(maxConcurrentPodUpdates(gateway), len(podUpdates), func(u int) error {
+		return applyToGatewayPod(ctx, params, gwUpdReq, podUpdates[u])
+	})
+	if err != nil {
+		return err
+	}
+
 	if updateStatus || (!updateStatus && kind == "cluster properties") || (!updateStatus && kind == "listen ports") {
 		err := updateEntityStatus(ctx, kind, name, bundle, params)
 		if err != nil {
@@ -2218,7 +2319,7 @@ func ReconcileDBGateway(ctx context.Context, params Params, kind string, gateway
 		start := time.Now()
 		params.Log.V(2).Info("applying latest "+kind, "sha1Sum", sha1Sum, "name", gateway.Name, "namespace", gateway.Namespace)
 
-		err = util.ApplyGraphmanBundle(username, password, endpoint, graphmanEncryptionPassphrase, bundle)
+		err = util.ApplyGraphmanBundle(ctx, username, password, endpoint, graphmanEncryptionPassphrase, bundle)
 		if err != nil {
 			params.Log.Info("failed to apply "+kind, "sha1Sum", sha1Sum, "name", gateway.Name, "namespace", gateway.Namespace)
 			_ = captureGraphmanMetrics(ctx, params, start, gateway.Name, kind, name, sha1Sum, true)
@@ -2242,7 +2343,7 @@ func ReconcileDBGateway(ctx context.Context, params Params, kind string, gateway
 	return nil
 }
 
-func updateRepoRefStatus(ctx context.Context, params Params, repository securityv1.Repository, repoRef securityv1.RepositoryReference, commit string, applyError error, delete bool) (err error) {
+func updateRepoRefStatus(ctx context.Context, params Params, repository securityv1.Repository, repoRef securityv1.RepositoryReference, commit string, applyError error, delete bool, verifyConditions []securityv1.RepositoryCondition) (err error) {
 	gatewayStatus := params.Instance.Status
 
 	// If delete was successful, remove the repository from status
@@ -2341,6 +2442,27 @@ func updateRepoRefStatus(ctx context.Context, params Params, repository security
 			Status: "SUCCESS",
 			Reason: "",
 		})
+		conditions = append(conditions, verifyConditions...)
+		// verify results are kept until the pod is verified again or the commit changes
+		for _, ors := range gatewayStatus.RepositoryStatus {
+			if ors.Name != repository.Name || ors.Commit != commit {
+				continue
+			}
+			for _, condition := range ors.Conditions {
+				if condition.Pod == "" {
+					continue
+				}
+				verified := false
+				for _, verifyCondition := range verifyConditions {
+					if verifyCondition.Pod == condition.Pod {
+						verified = true
+					}
+				}
+				if !verified {
+					conditions = append(conditions, condition)
+				}
+			}
+		}
 	}
 
 	nrs.Conditions = conditions
@@ -2621,14 +2743,11 @@ func getStateStore(ctx context.Context, params Params, stateStoreName string) (s
 	return statestore, nil
 }
 
-func isIPv6(str string) bool {
-	ip := net.ParseIP(str)
-	return ip != nil && strings.Contains(str, ":")
-}
-
-func podIP(podIp string) string {
-	if isIPv6(podIp) {
-		return "[" + podIp + "]"
+// graphmanHealthy returns false while Graphman requests to a pod are skipped after repeated failures
+func graphmanHealthy(params Params, pod corev1.Pod, graphmanPort int) bool {
+	healthy, reason := graphman.TargetHealth(util.PodIP(pod.Status.PodIP) + ":" + strconv.Itoa(graphmanPort))
+	if !healthy {
+		params.Log.Info("skipping unhealthy gateway pod", "pod", pod.Name, "reason", reason, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
 	}
-	return podIp
+	return healthy
 }
@@ -0,0 +1,276 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// testGraphmanServer counts bundle applications and the maximum number of requests in flight
+type testGraphmanServer struct {
+	*httptest.Server
+	requests    atomic.Int32
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	// failures is the number of requests that are rejected before requests succeed again
+	failures atomic.Int32
+}
+
+func newTestGraphmanServer(t *testing.T) *testGraphmanServer {
+	gs := &testGraphmanServer{}
+	gs.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if lookup, ok := graphmanLookupResponse(body); ok {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(lookup)
+			return
+		}
+		gs.requests.Add(1)
+		current := gs.inFlight.Add(1)
+		defer gs.inFlight.Add(-1)
+		for {
+			maxInFlight := gs.maxInFlight.Load()
+			if current <= maxInFlight || gs.maxInFlight.CompareAndSwap(maxInFlight, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		if gs.failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	t.Cleanup(gs.Close)
+	return gs
+}
+
+// graphmanLookupResponse finds every entity in the named lookups that follow a bundle application
+func graphmanLookupResponse(body []byte) ([]byte, bool) {
+	req := struct {
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, false
+	}
+	if !strings.HasSuffix(req.OperationName, "ByName") && !strings.HasSuffix(req.OperationName, "ByAlias") {
+		return nil, false
+	}
+	data := map[string]interface{}{}
+	for variable, name := range req.Variables {
+		data["e"+strings.TrimPrefix(variable, "n")] = map[string]interface{}{"goid": "1", "name": name, "alias": name}
+	}
+	resp, _ := json.Marshal(map[string]interface{}{"data": data})
+	return resp, true
+}
+
+func TestUpdateGatewayPodsConcurrently(t *testing.T) {
+	gs := newTestGraphmanServer(t)
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(gs.URL, "https://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	graphmanPort, _ := strconv.Atoi(port)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	gateway := &securityv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "concurrent", Namespace: "default"},
+	}
+	gateway.Spec.App.MaxConcurrentPodUpdates = 4
+
+	podCount := 40
+	pods := []client.Object{}
+	podList := &corev1.PodList{}
+	for i := 0; i < podCount; i++ {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "concurrent-" + strconv.Itoa(i), Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				PodIP:             host,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "gateway", Ready: true}},
+			},
+		}
+		pods = append(pods, pod.DeepCopy())
+		podList.Items = append(podList.Items, pod)
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pods...).WithObjects(gateway).Build()
+
+	params := Params{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: gateway,
+	}
+
+	repoRef := securityv1.RepositoryReference{Name: "concurrent-repo", Enabled: true, Type: securityv1.RepositoryReferenceTypeDynamic}
+	newRequest := func(checksum string) *GatewayUpdateRequest {
+		return &GatewayUpdateRequest{
+			checksum:            checksum,
+			patchAnnotation:     "security.brcmlabs.com/concurrent-repo-dynamic",
+			graphmanPort:        graphmanPort,
+			bundle:              []byte(`{"clusterProperties":[{"name":"concurrent","value":"true"}]}`),
+			bundleName:          "concurrent-repo",
+			bundleType:          BundleTypeRepository,
+			username:            "admin",
+			password:            "password",
+			cacheEntry:          "concurrent-repo-" + checksum,
+			repositoryReference: &repoRef,
+			gateway:             gateway,
+			podList:             podList.DeepCopy(),
+		}
+	}
+
+	t.Run("should apply to every pod with bounded parallelism", func(t *testing.T) {
+		err := updateGatewayPods(context.Background(), params, newRequest("commit1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gs.requests.Load() != int32(podCount) {
+			t.Errorf("expected %d graphman requests, actual %d", podCount, gs.requests.Load())
+		}
+		if gs.maxInFlight.Load() > 4 {
+			t.Errorf("expected at most 4 concurrent requests, actual %d", gs.maxInFlight.Load())
+		}
+		if gs.maxInFlight.Load() < 2 {
+			t.Errorf("expected pods to be updated in parallel, actual %d", gs.maxInFlight.Load())
+		}
+		for _, p := range pods {
+			pod := &corev1.Pod{}
+			if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: p.GetName(), Namespace: "default"}, pod); err != nil {
+				t.Fatal(err)
+			}
+			if pod.Annotations["security.brcmlabs.com/concurrent-repo-dynamic"] != "commit1" {
+				t.Errorf("expected pod %s to be annotated with commit1, actual %q", pod.Name, pod.Annotations["security.brcmlabs.com/concurrent-repo-dynamic"])
+			}
+		}
+	})
+
+	t.Run("should aggregate per pod errors", func(t *testing.T) {
+		gs.requests.Store(0)
+		gs.failures.Store(2)
+
+		err := updateGatewayPods(context.Background(), params, newRequest("commit2"))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if len(strings.Split(err.Error(), "\n")) != 2 {
+			t.Errorf("expected 2 pod errors, actual %s", err.Error())
+		}
+		if gs.requests.Load() != int32(podCount) {
+			t.Errorf("expected every pod to be attempted, actual %d", gs.requests.Load())
+		}
+
+		updated := 0
+		for _, p := range pods {
+			pod := &corev1.Pod{}
+			if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: p.GetName(), Namespace: "default"}, pod); err != nil {
+				t.Fatal(err)
+			}
+			if pod.Annotations["security.brcmlabs.com/concurrent-repo-dynamic"] == "commit2" {
+				updated++
+			}
+		}
+		if updated != podCount-2 {
+			t.Errorf("expected %d pods to be annotated with commit2, actual %d", podCount-2, updated)
+		}
+	})
+}
+
+func TestReconcileEphemeralGateway(t *testing.T) {
+	gs := newTestGraphmanServer(t)
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(gs.URL, "https://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	graphmanPort, _ := strconv.Atoi(port)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	gateway := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ephemeral", Namespace: "default"}}
+	gateway.Spec.App.Management.Graphman.DynamicSyncPort = graphmanPort
+	gwSecret := &corev1.Secret{Data: map[string][]byte{"SSG_ADMIN_USERNAME": []byte("admin"), "SSG_ADMIN_PASSWORD": []byte("password")}}
+
+	pods := []client.Object{}
+	podList := corev1.PodList{}
+	for i, ready := range []bool{true, true, false} {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ephemeral-" + strconv.Itoa(i), Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				PodIP:             host,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "gateway", Ready: ready}},
+			},
+		}
+		pods = append(pods, pod.DeepCopy())
+		podList.Items = append(podList.Items, pod)
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pods...).WithObjects(gateway).Build()
+	params := Params{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: gateway,
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	annotation := "security.brcmlabs.com/ephemeral-otk-policies"
+	bundle := []byte(`{"scheduledTasks":[{"name":"cleanup","executeOnSingleNode":true}]}`)
+	err = ReconcileEphemeralGateway(context.Background(), params, "otk policies", podList, gateway, gwSecret, "", annotation, "sha1", false, "otk policies", bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gs.requests.Load() != 2 {
+		t.Errorf("expected the bundle to be applied to %d ready pods, actual %d", 2, gs.requests.Load())
+	}
+	podSpans := 0
+	for _, span := range recorder.Ended() {
+		if span.Name() == "applyToGatewayPod" {
+			podSpans++
+		}
+	}
+	if podSpans != 2 {
+		t.Errorf("expected an applyToGatewayPod span for %d ready pods, actual %d", 2, podSpans)
+	}
+	for i, want := range []string{"sha1", "sha1", ""} {
+		pod := &corev1.Pod{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ephemeral-" + strconv.Itoa(i), Namespace: "default"}, pod); err != nil {
+			t.Fatal(err)
+		}
+		if pod.Annotations[annotation] != want {
+			t.Errorf("expected pod %s to be annotated with %q, actual %q", pod.Name, want, pod.Annotations[annotation])
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/caapim/layer7-operator/pkg/gateway"
+	"github.com/caapim/layer7-operator/pkg/util"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func GatewayAPIRoutes(ctx context.Context, params Params) error {
+	desiredRoutes := []*unstructured.Unstructured{}
+	if params.Instance.Spec.App.Ingress.Enabled && strings.ToLower(params.Instance.Spec.App.Ingress.Type) == "gatewayapi" {
+		desiredRoutes = gateway.NewGatewayAPIRoutes(params.Instance)
+	}
+
+	if err := reconcileGatewayAPIRoutes(ctx, params, desiredRoutes); err != nil {
+		return fmt.Errorf("failed to reconcile gateway api routes: %w", err)
+	}
+	if err := pruneGatewayAPIRoutes(ctx, params, desiredRoutes); err != nil {
+		return fmt.Errorf("failed to prune gateway api routes: %w", err)
+	}
+	return nil
+}
+
+func reconcileGatewayAPIRoutes(ctx context.Context, params Params, desiredRoutes []*unstructured.Unstructured) error {
+	for _, desiredRoute := range desiredRoutes {
+		currentRoute := &unstructured.Unstructured{}
+		currentRoute.SetGroupVersionKind(desiredRoute.GroupVersionKind())
+
+		if err := controllerutil.SetControllerReference(params.Instance, desiredRoute, params.Scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+
+		err := params.Client.Get(ctx, types.NamespacedName{Name: desiredRoute.GetName(), Namespace: params.Instance.Namespace}, currentRoute)
+
+		// the Gateway API CRDs for this route kind are not installed
+		if meta.IsNoMatchError(err) {
+			params.Log.Info("gateway api route kind is not available", "kind", desiredRoute.GetKind(), "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+			continue
+		}
+
+		if err != nil && k8serrors.IsNotFound(err) {
+			if err = params.Client.Create(ctx, desiredRoute); err != nil {
+				return err
+			}
+			params.Log.Info("created gateway api route", "kind", desiredRoute.GetKind(), "name", desiredRoute.GetName(), "namespace", params.Instance.Namespace)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if equality.Semantic.DeepEqual(currentRoute.Object["spec"], desiredRoute.Object["spec"]) && equality.Semantic.DeepEqual(currentRoute.GetAnnotations(), desiredRoute.GetAnnotations()) {
+			params.Log.V(2).Info("no gateway api route updates needed", "kind", desiredRoute.GetKind(), "name", desiredRoute.GetName(), "namespace", desiredRoute.GetNamespace())
+			continue
+		}
+
+		updatedRoute := currentRoute.DeepCopy()
+		updatedRoute.Object["spec"] = desiredRoute.Object["spec"]
+		updatedRoute.SetOwnerReferences(desiredRoute.GetOwnerReferences())
+		updatedRoute.SetAnnotations(desiredRoute.GetAnnotations())
+		updatedRoute.SetLabels(desiredRoute.GetLabels())
+
+		patch := client.MergeFrom(currentRoute)
+		if err := params.Client.Patch(ctx, updatedRoute, patch); err != nil {
+			return err
+		}
+		params.Log.Info("gateway api route updated", "kind", desiredRoute.GetKind(), "name", desiredRoute.GetName(), "namespace", desiredRoute.GetNamespace())
+	}
+	return nil
+}
+
+// pruneGatewayAPIRoutes removes routes owned by the Gateway that are no longer desired
+// this covers removed route specs, disabled ingress and switching to another ingress type
+func pruneGatewayAPIRoutes(ctx context.Context, params Params, desiredRoutes []*unstructured.Unstructured) error {
+	desired := map[string]bool{}
+	for _, desiredRoute := range desiredRoutes {
+		desired[desiredRoute.GetKind()+"/"+desiredRoute.GetName()] = true
+	}
+
+	for _, gvk := range gateway.GatewayAPIRouteKinds {
+		routeList := &unstructured.UnstructuredList{}
+		routeList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		err := params.Client.List(ctx, routeList, client.InNamespace(params.Instance.Namespace), client.MatchingLabels(util.DefaultLabels(params.Instance.Name, map[string]string{})))
+		if err != nil {
+			// the Gateway API CRDs for this route kind are not installed
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+
+		for i := range routeList.Items {
+			route := &routeList.Items[i]
+			if desired[gvk.Kind+"/"+route.GetName()] || !metav1.IsControlledBy(route, params.Instance) {
+				continue
+			}
+			if err := params.Client.Delete(ctx, route); err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+			params.Log.Info("removed gateway api route", "kind", gvk.Kind, "name", route.GetName(), "namespace", params.Instance.Namespace)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/gateway"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestGatewayAPIRoutes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range gateway.GatewayAPIRouteKinds {
+		restMapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+
+	gw := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default", UID: "ssg-uid"}}
+	gw.Spec.App.Ingress.Enabled = true
+	gw.Spec.App.Ingress.Type = "gatewayapi"
+	gw.Spec.App.Ingress.GatewayAPI.Routes = []securityv1.GatewayAPIRoute{
+		{Kind: "HTTPRoute"},
+		{Kind: "TLSRoute", Management: true},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(restMapper).WithObjects(gw).Build()
+	params := Params{
+		Client:   k8sClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: gw,
+	}
+	routeNames := func(t *testing.T, kind string) []string {
+		gvk := gateway.GatewayAPIRouteKinds[kind]
+		routeList := &unstructured.UnstructuredList{}
+		routeList.SetGroupVersionKind(gvk.GroupVersion().WithKind(kind + "List"))
+		if err := k8sClient.List(context.Background(), routeList, client.InNamespace("default")); err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, route := range routeList.Items {
+			names = append(names, route.GetName())
+		}
+		return names
+	}
+
+	t.Run("should create the desired routes", func(t *testing.T) {
+		if err := GatewayAPIRoutes(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if names := routeNames(t, "HTTPRoute"); len(names) != 1 || names[0] != "ssg-httproute-0" {
+			t.Errorf("unexpected HTTPRoutes %v", names)
+		}
+		if names := routeNames(t, "TLSRoute"); len(names) != 1 || names[0] != "ssg-tlsroute-management" {
+			t.Errorf("unexpected TLSRoutes %v", names)
+		}
+	})
+
+	t.Run("should prune routes that are no longer desired", func(t *testing.T) {
+		unowned := gateway.NewGatewayAPIRoute(gw, securityv1.GatewayAPIRoute{Kind: "TLSRoute"}, "unowned")
+		if err := k8sClient.Create(context.Background(), unowned); err != nil {
+			t.Fatal(err)
+		}
+		gw.Spec.App.Ingress.GatewayAPI.Routes = []securityv1.GatewayAPIRoute{{Kind: "HTTPRoute"}}
+		if err := GatewayAPIRoutes(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if names := routeNames(t, "HTTPRoute"); len(names) != 1 {
+			t.Errorf("unexpected HTTPRoutes %v", names)
+		}
+		if names := routeNames(t, "TLSRoute"); len(names) != 1 || names[0] != "ssg-tlsroute-unowned" {
+			t.Errorf("expected only the route without a controller reference to remain, got %v", names)
+		}
+	})
+
+	t.Run("should remove routes when the ingress type changes", func(t *testing.T) {
+		gw.Spec.App.Ingress.Type = "route"
+		if err := GatewayAPIRoutes(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if names := routeNames(t, "HTTPRoute"); len(names) != 0 {
+			t.Errorf("expected HTTPRoutes to be removed, got %v", names)
+		}
+	})
+}
@@ -48,7 +48,7 @@ func HorizontalPodAutoscaler(ctx context.Context, params Params) error {
 		return err
 	}
 
-	if reflect.DeepEqual(currentHpa.Spec.Behavior, desiredHpa.Spec.Behavior) && reflect.DeepEqual(currentHpa.Spec.MaxReplicas, desiredHpa.Spec.MaxReplicas) && reflect.DeepEqual(currentHpa.Spec.MinReplicas, desiredHpa.Spec.MinReplicas) && reflect.DeepEqual(currentHpa.Spec.Metrics, desiredHpa.Spec.Metrics) {
+	if reflect.DeepEqual(currentHpa.Spec.ScaleTargetRef, desiredHpa.Spec.ScaleTargetRef) && reflect.DeepEqual(currentHpa.Spec.Behavior, desiredHpa.Spec.Behavior) && reflect.DeepEqual(currentHpa.Spec.MaxReplicas, desiredHpa.Spec.MaxReplicas) && reflect.DeepEqual(currentHpa.Spec.MinReplicas, desiredHpa.Spec.MinReplicas) && reflect.DeepEqual(currentHpa.Spec.Metrics, desiredHpa.Spec.Metrics) {
 		params.Log.V(2).Info("no horizontal pod autoscaler updates needed", "name", desiredHpa.Name, "namespace", desiredHpa.Namespace)
 		return nil
 	}
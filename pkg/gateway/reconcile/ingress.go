@@ -15,7 +15,7 @@ import (
 )
 
 func Ingress(ctx context.Context, params Params) error {
-	if strings.ToLower(params.Instance.Spec.App.Ingress.Type) == "route" {
+	if strings.ToLower(params.Instance.Spec.App.Ingress.Type) == "route" || strings.ToLower(params.Instance.Spec.App.Ingress.Type) == "gatewayapi" {
 		return nil
 	}
 
@@ -30,11 +30,18 @@ import (
 	"crypto/sha1"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	v1 "github.com/caapim/layer7-operator/api/v1"
 	"github.com/caapim/layer7-operator/internal/graphman"
 )
 
+const otkMaintenanceTaskPrefix = "OTK Database Maintenance - "
+
 var scheduledTasks = map[string]string{
 	"OTK Database Maintenance - Client":                "0 */31 * * * ?",
 	"OTK Database Maintenance - id_token":              "0 */29 * * * ?",
@@ -44,26 +51,37 @@ var scheduledTasks = map[string]string{
 	"OTK Database Maintenance - miscellaneous tokens":  "0 */5 * * * ?",
 }
 
+// cassandraScheduledTasks tokens and sessions are removed by Cassandra when their TTL expires
+var cassandraScheduledTasks = map[string]string{
+	"OTK Database Maintenance - Client":                "0 */31 * * * ?",
+	"OTK Database Maintenance - token revocation list": "0 0 */1 * * ?",
+}
+
+// OTKDatabaseMaintenanceTasks schedules the OTK database maintenance tasks on the leader Gateway pod
+// DB backed Gateways share their scheduled tasks and are updated once via the management pod
 func OTKDatabaseMaintenanceTasks(ctx context.Context, params Params) error {
 	gateway := params.Instance
 
-	if gateway.Spec.App.Management.Database.Enabled || !gateway.Spec.App.Otk.Enabled || gateway.Spec.App.Otk.Type != v1.OtkTypeSingle || gateway.Spec.App.Otk.Database.Type == v1.OtkDatabaseTypeCassandra {
+	if !gateway.Spec.App.Otk.Enabled || (gateway.Spec.App.Otk.Type != v1.OtkTypeSingle && gateway.Spec.App.Otk.Type != v1.OtkTypeInternal) {
 		return nil
 	}
 
+	tasks := otkMaintenanceTasks(gateway)
 	bundle := graphman.Bundle{}
-	if gateway.Spec.App.Otk.Database.Type != v1.OtkDatabaseTypeCassandra {
-		for name, schedule := range scheduledTasks {
-			bundle.ScheduledTasks = append(bundle.ScheduledTasks, &graphman.ScheduledTaskInput{
-				Name:                name,
-				PolicyName:          name,
-				JobType:             graphman.JobTypeRecurring,
-				CronExpression:      schedule,
-				ExecuteOnSingleNode: true,
-				ExecuteOnCreation:   false,
-				Status:              graphman.JobStatusScheduled,
-			})
+	for _, task := range tasks {
+		status := graphman.JobStatusScheduled
+		if task.Disabled {
+			status = graphman.JobStatusDisabled
 		}
+		bundle.ScheduledTasks = append(bundle.ScheduledTasks, &graphman.ScheduledTaskInput{
+			Name:                task.Name,
+			PolicyName:          task.Name,
+			JobType:             graphman.JobTypeRecurring,
+			CronExpression:      task.Schedule,
+			ExecuteOnSingleNode: true,
+			ExecuteOnCreation:   false,
+			Status:              status,
+		})
 	}
 
 	bundleBytes, err := json.Marshal(bundle)
@@ -73,6 +91,7 @@ func OTKDatabaseMaintenanceTasks(ctx context.Context, params Params) error {
 
 	h := sha1.New()
 	h.Write([]byte(gateway.Spec.App.Otk.InitContainerImage))
+	h.Write(bundleBytes)
 	checksum := fmt.Sprintf("%x", h.Sum(nil))
 
 	gwUpdReq, err := NewGwUpdateRequest(
@@ -93,9 +112,48 @@ func OTKDatabaseMaintenanceTasks(ctx context.Context, params Params) error {
 	}
 
 	err = SyncGateway(ctx, params, *gwUpdReq)
+
+	status := gateway.Status.OtkMaintenanceTasks
 	if err != nil {
-		return err
+		status.Reason = err.Error()
+	} else {
+		if status.Checksum != checksum {
+			status.LastApplied = time.Now().Format(time.RFC3339)
+		}
+		status.Tasks = tasks
+		status.Checksum = checksum
+		status.Reason = ""
 	}
+	if !reflect.DeepEqual(status, gateway.Status.OtkMaintenanceTasks) {
+		gateway.Status.OtkMaintenanceTasks = status
+		if statusErr := params.Client.Status().Update(ctx, gateway); statusErr != nil {
+			return fmt.Errorf("failed to update otk maintenance tasks status: %w", statusErr)
+		}
+	}
+
+	return err
+}
 
-	return nil
+// otkMaintenanceTasks returns the maintenance tasks for the OTK database type with schedule overrides applied
+func otkMaintenanceTasks(gateway *v1.Gateway) []v1.OtkMaintenanceTask {
+	defaultTasks := scheduledTasks
+	if gateway.Spec.App.Otk.Database.Type == v1.OtkDatabaseTypeCassandra {
+		defaultTasks = cassandraScheduledTasks
+	}
+
+	tasks := []v1.OtkMaintenanceTask{}
+	for _, name := range slices.Sorted(maps.Keys(defaultTasks)) {
+		task := v1.OtkMaintenanceTask{Name: name, Schedule: defaultTasks[name]}
+		for _, override := range gateway.Spec.App.Otk.MaintenanceTasks.Tasks {
+			if !strings.EqualFold(override.Name, name) && !strings.EqualFold(otkMaintenanceTaskPrefix+override.Name, name) {
+				continue
+			}
+			if override.Schedule != "" {
+				task.Schedule = override.Schedule
+			}
+			task.Disabled = override.Disabled
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
 }
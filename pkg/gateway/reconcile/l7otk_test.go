@@ -0,0 +1,57 @@
+package reconcile
+
+import (
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+)
+
+func TestOtkMaintenanceTasks(t *testing.T) {
+	gateway := &securityv1.Gateway{}
+	gateway.Spec.App.Otk = securityv1.Otk{
+		Enabled:  true,
+		Type:     securityv1.OtkTypeInternal,
+		Database: securityv1.OtkDatabase{Type: securityv1.OtkDatabaseTypeMySQL},
+		MaintenanceTasks: securityv1.OtkMaintenanceTasks{Tasks: []securityv1.OtkMaintenanceTask{
+			{Name: "token", Schedule: "0 */10 * * * ?"},
+			{Name: "OTK Database Maintenance - sessions", Disabled: true},
+		}},
+	}
+
+	t.Run("should apply schedule overrides to the default tasks", func(t *testing.T) {
+		tasks := otkMaintenanceTasks(gateway)
+		if len(tasks) != len(scheduledTasks) {
+			t.Fatalf("expected %d tasks, actual %d", len(scheduledTasks), len(tasks))
+		}
+		for _, task := range tasks {
+			switch task.Name {
+			case "OTK Database Maintenance - token":
+				if task.Schedule != "0 */10 * * * ?" || task.Disabled {
+					t.Errorf("expected the token schedule to be overridden, actual %+v", task)
+				}
+			case "OTK Database Maintenance - sessions":
+				if task.Schedule != scheduledTasks[task.Name] || !task.Disabled {
+					t.Errorf("expected the sessions task to be disabled with its default schedule, actual %+v", task)
+				}
+			default:
+				if task.Schedule != scheduledTasks[task.Name] || task.Disabled {
+					t.Errorf("expected the default schedule, actual %+v", task)
+				}
+			}
+		}
+	})
+
+	t.Run("should only schedule cassandra tasks for cassandra", func(t *testing.T) {
+		cassandra := gateway.DeepCopy()
+		cassandra.Spec.App.Otk.Database.Type = securityv1.OtkDatabaseTypeCassandra
+		tasks := otkMaintenanceTasks(cassandra)
+		if len(tasks) != len(cassandraScheduledTasks) {
+			t.Fatalf("expected %d tasks, actual %d", len(cassandraScheduledTasks), len(tasks))
+		}
+		for _, task := range tasks {
+			if _, ok := cassandraScheduledTasks[task.Name]; !ok {
+				t.Errorf("unexpected task %s", task.Name)
+			}
+		}
+	})
+}
@@ -27,9 +27,16 @@ package reconcile
 
 import (
 	"context"
+	"crypto/sha1"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 
 	securityv1 "github.com/caapim/layer7-operator/api/v1"
@@ -64,21 +71,17 @@ func applyOtkCertificates(ctx context.Context, params Params, gateway *securityv
 
 	bundle := graphman.Bundle{}
 	annotation := ""
-	sha1Sum := ""
 
+	var certificates map[string][]byte
+	var errs []error
 	switch gateway.Spec.App.Otk.Type {
 	case securityv1.OtkTypeDMZ:
-		internalSecret, err := getGatewaySecret(ctx, params, gateway.Spec.App.Otk.InternalOtkGatewayReference+"-otk-internal-certificates")
-		sha1Sum = internalSecret.ObjectMeta.Annotations["checksum/data"]
-
-		if err != nil {
-			return err
-		}
 		annotation = "security.brcmlabs.com/" + gateway.Name + "-" + string(gateway.Spec.App.Otk.Type) + "-certificates"
-		for k, v := range internalSecret.Data {
+		certificates, errs = otkGatewayCertificates(ctx, params, gateway, "-otk-internal-certificates")
+		for _, k := range slices.Sorted(maps.Keys(certificates)) {
 			bundle.TrustedCerts = append(bundle.TrustedCerts, &graphman.TrustedCertInput{
 				Name:                      k,
-				CertBase64:                base64.StdEncoding.EncodeToString(v),
+				CertBase64:                base64.StdEncoding.EncodeToString(certificates[k]),
 				TrustAnchor:               true,
 				VerifyHostname:            false,
 				RevocationCheckPolicyType: "USE_DEFAULT",
@@ -90,26 +93,25 @@ func applyOtkCertificates(ctx context.Context, params Params, gateway *securityv
 		}
 
 	case securityv1.OtkTypeInternal:
-		dmzSecret, err := getGatewaySecret(ctx, params, gateway.Spec.App.Otk.DmzOtkGatewayReference+"-otk-dmz-certificates")
-		sha1Sum = dmzSecret.ObjectMeta.Annotations["checksum/data"]
-		if err != nil {
-			return err
-		}
 		annotation = "security.brcmlabs.com/" + gateway.Name + "-" + string(gateway.Spec.App.Otk.Type) + "-fips-users"
-		for k, v := range dmzSecret.Data {
-			bundle.FipUsers = append(bundle.FipUsers, &graphman.FipUserInput{
-				Name:         k,
-				ProviderName: "otk-fips-provider",
-				SubjectDn:    "cn=" + k,
-				CertBase64:   base64.RawStdEncoding.EncodeToString(v),
-			})
-		}
+		certificates, errs = otkGatewayCertificates(ctx, params, gateway, "-otk-dmz-certificates")
+		var fipErrs []error
+		bundle.FipUsers, fipErrs = otkFipUsers(certificates)
+		errs = append(errs, fipErrs...)
+	}
+
+	// certificates that are available are applied, references that could not be resolved are retried on the next sync
+	if len(certificates) == 0 {
+		return errors.Join(errs...)
 	}
 
 	bundleBytes, err := json.Marshal(bundle)
 	if err != nil {
 		return err
 	}
+	h := sha1.New()
+	h.Write(bundleBytes)
+	sha1Sum := fmt.Sprintf("%x", h.Sum(nil))
 
 	name := gateway.Name
 	if gateway.Spec.App.Management.SecretName != "" {
@@ -122,7 +124,7 @@ func applyOtkCertificates(ctx context.Context, params Params, gateway *securityv
 	}
 
 	if !gateway.Spec.App.Management.Database.Enabled {
-		podList, err := getGatewayPods(ctx, params)
+		podList, err := getConfiguredPods(ctx, params)
 		if err != nil {
 			return err
 		}
@@ -141,7 +143,118 @@ func applyOtkCertificates(ctx context.Context, params Params, gateway *securityv
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// otkGatewayReferences returns the internal Gateway of a DMZ Gateway or the DMZ Gateways of an internal Gateway
+// references that only use the deprecated name fields are included
+func otkGatewayReferences(gateway *securityv1.Gateway) []securityv1.OtkGatewayReference {
+	otk := gateway.Spec.App.Otk
+	refs := []securityv1.OtkGatewayReference{}
+	switch otk.Type {
+	case securityv1.OtkTypeDMZ:
+		if otk.InternalOtkGateway.Name != "" || otk.InternalOtkGateway.Host != "" {
+			refs = append(refs, otk.InternalOtkGateway)
+		} else if otk.InternalOtkGatewayReference != "" {
+			refs = append(refs, securityv1.OtkGatewayReference{Name: otk.InternalOtkGatewayReference})
+		}
+	case securityv1.OtkTypeInternal:
+		refs = append(refs, otk.DmzOtkGateways...)
+		if otk.DmzOtkGatewayReference != "" && !slices.ContainsFunc(refs, func(ref securityv1.OtkGatewayReference) bool {
+			return ref.Name == otk.DmzOtkGatewayReference && (ref.Namespace == "" || ref.Namespace == gateway.Namespace)
+		}) {
+			refs = append(refs, securityv1.OtkGatewayReference{Name: otk.DmzOtkGatewayReference})
+		}
+	}
+
+	for i := range refs {
+		if refs[i].Host == "" && refs[i].Namespace == "" {
+			refs[i].Namespace = gateway.Namespace
+		}
+	}
+	return refs
+}
+
+// otkGatewayCertificates collects the certificates of each referenced OTK Gateway keyed by the name they are applied with
+// Operator managed Gateways publish their certificates in a secret with secretSuffix, external Gateways use a CA bundle
+func otkGatewayCertificates(ctx context.Context, params Params, gateway *securityv1.Gateway, secretSuffix string) (map[string][]byte, []error) {
+	certificates := map[string][]byte{}
+	errs := []error{}
+	for _, ref := range otkGatewayReferences(gateway) {
+		if ref.Host != "" {
+			caSecret, err := getGatewaySecret(ctx, params, ref.CaBundleSecretName)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to retrieve ca bundle for otk gateway %s: %w", ref.Host, err))
+				continue
+			}
+			i := 0
+			for _, k := range slices.Sorted(maps.Keys(caSecret.Data)) {
+				for _, cert := range parseCertificates(caSecret.Data[k]) {
+					certificates[ref.Host+"-"+strconv.Itoa(i)] = cert
+					i++
+				}
+			}
+			continue
+		}
+
+		refSecret := &corev1.Secret{}
+		err := params.Client.Get(ctx, types.NamespacedName{Name: ref.Name + secretSuffix, Namespace: ref.Namespace}, refSecret)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to retrieve certificates for otk gateway %s/%s: %w", ref.Namespace, ref.Name, err))
+			continue
+		}
+		for k, v := range refSecret.Data {
+			// certificates are keyed by pod name, Gateways in other namespaces may use the same pod names
+			if ref.Namespace != gateway.Namespace {
+				k = k + "." + ref.Namespace
+			}
+			certificates[k] = v
+		}
+	}
+	return certificates, errs
+}
+
+// otkFipUsers returns a FIP user for each DMZ certificate, the user is named after the certificate key
+// and identified by the subject of the certificate because keys of cross-namespace and external Gateways are not the certificate CN
+func otkFipUsers(certificates map[string][]byte) ([]*graphman.FipUserInput, []error) {
+	fipUsers := []*graphman.FipUserInput{}
+	errs := []error{}
+	for _, k := range slices.Sorted(maps.Keys(certificates)) {
+		cert, err := x509.ParseCertificate(certificates[k])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse otk certificate %s: %w", k, err))
+			continue
+		}
+		fipUsers = append(fipUsers, &graphman.FipUserInput{
+			Name:         k,
+			ProviderName: "otk-fips-provider",
+			SubjectDn:    cert.Subject.String(),
+			CertBase64:   base64.RawStdEncoding.EncodeToString(certificates[k]),
+		})
+	}
+	return fipUsers, errs
+}
+
+// parseCertificates returns the DER encoded certificates in a PEM bundle, data that is not PEM encoded is treated as a single DER certificate
+func parseCertificates(data []byte) [][]byte {
+	certs := [][]byte{}
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			certs = append(certs, block.Bytes)
+		}
+	}
+	if len(certs) == 0 && len(data) > 0 {
+		if _, err := x509.ParseCertificate(data); err == nil {
+			certs = append(certs, data)
+		}
+	}
+	return certs
 }
 
 func manageCertificateSecrets(ctx context.Context, params Params) {
@@ -0,0 +1,155 @@
+package reconcile
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func testCertificate(t *testing.T, cn string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestOtkGatewayCertificates(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	dmzCert := testCertificate(t, "dmz")
+	remoteDmzCert := testCertificate(t, "remote-dmz")
+	externalCerts := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testCertificate(t, "external-1")}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testCertificate(t, "external-2")})...)
+
+	gateway := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "otk"}}
+	gateway.Spec.App.Otk = securityv1.Otk{
+		Enabled:                true,
+		Type:                   securityv1.OtkTypeInternal,
+		DmzOtkGatewayReference: "dmz",
+		DmzOtkGateways: []securityv1.OtkGatewayReference{
+			{Name: "dmz"},
+			{Name: "dmz", Namespace: "edge"},
+			{Host: "dmz.example.com", CaBundleSecretName: "external-dmz-ca"},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "dmz-otk-dmz-certificates", Namespace: "otk"}, Data: map[string][]byte{"dmz-0": dmzCert}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "dmz-otk-dmz-certificates", Namespace: "edge"}, Data: map[string][]byte{"dmz-0": remoteDmzCert}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "external-dmz-ca", Namespace: "otk"}, Data: map[string][]byte{"ca.crt": externalCerts}},
+	).Build()
+	params := Params{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: gateway,
+	}
+
+	t.Run("should combine the deprecated reference with the reference list", func(t *testing.T) {
+		refs := otkGatewayReferences(gateway)
+		if len(refs) != 3 {
+			t.Fatalf("expected 3 references, actual %d", len(refs))
+		}
+		if refs[0].Namespace != "otk" || refs[2].Namespace != "" {
+			t.Errorf("expected operator managed gateways to default to the gateway namespace, actual %+v", refs)
+		}
+	})
+
+	t.Run("should collect certificates of each dmz gateway", func(t *testing.T) {
+		certificates, errs := otkGatewayCertificates(context.Background(), params, gateway, "-otk-dmz-certificates")
+		if len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		if string(certificates["dmz-0"]) != string(dmzCert) {
+			t.Errorf("expected the certificate of the gateway in the same namespace to keep the pod name")
+		}
+		if string(certificates["dmz-0.edge"]) != string(remoteDmzCert) {
+			t.Errorf("expected the certificate of the gateway in another namespace to include the namespace")
+		}
+		if len(certificates["dmz.example.com-0"]) == 0 || len(certificates["dmz.example.com-1"]) == 0 {
+			t.Errorf("expected each certificate in the ca bundle to be included, actual %d certificates", len(certificates))
+		}
+	})
+
+	t.Run("should report references that cannot be resolved", func(t *testing.T) {
+		missing := gateway.DeepCopy()
+		missing.Spec.App.Otk.DmzOtkGateways = append(missing.Spec.App.Otk.DmzOtkGateways, securityv1.OtkGatewayReference{Name: "missing"})
+		certificates, errs := otkGatewayCertificates(context.Background(), params, missing, "-otk-dmz-certificates")
+		if len(errs) != 1 {
+			t.Errorf("expected 1 error, actual %d", len(errs))
+		}
+		if len(certificates) != 4 {
+			t.Errorf("expected the remaining certificates to be collected, actual %d", len(certificates))
+		}
+	})
+
+	t.Run("should identify fip users by the certificate subject", func(t *testing.T) {
+		certificates, errs := otkGatewayCertificates(context.Background(), params, gateway, "-otk-dmz-certificates")
+		if len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		certificates["invalid"] = []byte("not a certificate")
+		fipUsers, errs := otkFipUsers(certificates)
+		if len(errs) != 1 {
+			t.Errorf("expected 1 error, actual %d", len(errs))
+		}
+		subjects := map[string]string{}
+		for _, fipUser := range fipUsers {
+			subjects[fipUser.Name] = fipUser.SubjectDn
+		}
+		expected := map[string]string{
+			"dmz-0":             "CN=dmz",
+			"dmz-0.edge":        "CN=remote-dmz",
+			"dmz.example.com-0": "CN=external-1",
+			"dmz.example.com-1": "CN=external-2",
+		}
+		for name, subject := range expected {
+			if subjects[name] != subject {
+				t.Errorf("expected fip user %s to have subject %s, actual %s", name, subject, subjects[name])
+			}
+		}
+	})
+
+	t.Run("should route to internal gateways in other namespaces or outside of the cluster", func(t *testing.T) {
+		dmz := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "dmz", Namespace: "edge"}}
+		hosts := map[string]securityv1.OtkGatewayReference{
+			"https://internal:9443":                     {Name: "internal", Namespace: "edge"},
+			"https://internal.otk.svc:9443":             {Name: "internal", Namespace: "otk"},
+			"https://internal.example.com:8443":         {Host: "internal.example.com", Port: 8443},
+			"https://internal-default.example.com:9443": {Host: "internal-default.example.com"},
+		}
+		for expected, ref := range hosts {
+			if actual := otkGatewayHost(dmz, ref, 9443); actual != expected {
+				t.Errorf("expected %s, actual %s", expected, actual)
+			}
+		}
+	})
+}
@@ -73,12 +73,13 @@ func applyOtkPolicies(ctx context.Context, params Params, gateway *securityv1.Ga
 	var gatewayHost string
 	switch gateway.Spec.App.Otk.Type {
 	case securityv1.OtkTypeDMZ:
-		// TODO: open this to internal gateways that are fully external or in a different namespace
 		// This routes via 9443 or the management port by default
 		if gateway.Spec.App.Otk.InternalGatewayPort != 0 {
 			internalGatewayPort = gateway.Spec.App.Otk.InternalGatewayPort
 		}
-		gatewayHost = "https://" + gateway.Spec.App.Otk.InternalOtkGatewayReference + ":" + strconv.Itoa(internalGatewayPort)
+		if refs := otkGatewayReferences(gateway); len(refs) > 0 {
+			gatewayHost = otkGatewayHost(gateway, refs[0], internalGatewayPort)
+		}
 	case securityv1.OtkTypeInternal:
 		gatewayHost = "https://" + gateway.Name + ":" + strconv.Itoa(defaultOtkPort)
 	}
@@ -101,7 +102,7 @@ func applyOtkPolicies(ctx context.Context, params Params, gateway *securityv1.Ga
 	annotation := "security.brcmlabs.com/" + gateway.Name + "-" + string(gateway.Spec.App.Otk.Type) + "-policies"
 
 	if !gateway.Spec.App.Management.Database.Enabled {
-		podList, err := getGatewayPods(ctx, params)
+		podList, err := getConfiguredPods(ctx, params)
 		if err != nil {
 			return err
 		}
@@ -122,3 +123,20 @@ func applyOtkPolicies(ctx context.Context, params Params, gateway *securityv1.Ga
 
 	return nil
 }
+
+// otkGatewayHost returns the url of a referenced OTK Gateway
+// Operator managed Gateways in other namespaces are reached via their cluster local service name
+func otkGatewayHost(gateway *securityv1.Gateway, ref securityv1.OtkGatewayReference, defaultPort int) string {
+	port := defaultPort
+	if ref.Host != "" {
+		if ref.Port != 0 {
+			port = ref.Port
+		}
+		return "https://" + ref.Host + ":" + strconv.Itoa(port)
+	}
+	host := ref.Name
+	if ref.Namespace != "" && ref.Namespace != gateway.Namespace {
+		host = ref.Name + "." + ref.Namespace + ".svc"
+	}
+	return "https://" + host + ":" + strconv.Itoa(port)
+}
@@ -32,6 +32,7 @@ import (
 	securityv1 "github.com/caapim/layer7-operator/api/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func ExternalRepository(ctx context.Context, params Params) error {
@@ -139,9 +140,10 @@ func reconcileDynamicRepository(ctx context.Context, params Params, repoRef secu
 		return nil
 	}
 
+	gwUpdReq.verification = &repositoryVerification{}
 	err = SyncGateway(ctx, params, *gwUpdReq)
 
-	_ = updateRepoRefStatus(ctx, params, *gwUpdReq.repository, *gwUpdReq.repositoryReference, gwUpdReq.checksum, err, delete)
+	_ = updateRepoRefStatus(ctx, params, *gwUpdReq.repository, *gwUpdReq.repositoryReference, gwUpdReq.checksum, err, delete, gwUpdReq.verification.conditions)
 	gwUpdReq = nil
 	if err != nil {
 		return err
@@ -149,3 +151,91 @@ func reconcileDynamicRepository(ctx context.Context, params Params, repoRef secu
 
 	return nil
 }
+
+// SyncRepositoryReference applies a repository that is managed outside of the Gateway's namespace or cluster
+// params.Client must be a client for the cluster that the Gateway runs in, repositoryClient is used to read
+// the repository bundle. Gateway status is not updated, pod and deployment annotations track the applied commit
+// in the same way as repository references.
+func SyncRepositoryReference(ctx context.Context, params Params, repositoryClient client.Client, repository *securityv1.Repository, repoRef securityv1.RepositoryReference) error {
+	gateway := params.Instance.DeepCopy()
+	// delta calculation relies on Gateway status which is not tracked for external repository references
+	gateway.Spec.App.RepositoryReferenceDelete = securityv1.RepositoryReferenceDelete{}
+	params.Instance = gateway
+
+	if len(repoRef.Directories) == 0 {
+		repoRef.Directories = []string{"/"}
+	}
+
+	// the bundle is built from the repository namespace
+	bundleGateway := gateway.DeepCopy()
+	bundleGateway.Namespace = repository.Namespace
+	bundleParams := params
+	bundleParams.Client = repositoryClient
+	bundleParams.Instance = bundleGateway
+
+	var bundle []byte
+	var err error
+	if repository.Spec.Type == securityv1.RepositoryTypeLocal {
+		bundle, err = readLocalReference(ctx, repository, bundleParams)
+	} else {
+		bundle, err = buildBundle(ctx, bundleParams, &repoRef, repository, bundleGateway, false)
+	}
+	if err != nil {
+		return err
+	}
+
+	gwUpdReq, err := NewGwUpdateRequest(
+		ctx,
+		gateway,
+		params,
+		WithChecksum(repository.Status.Commit),
+		WithBundleType(BundleTypeRepository),
+		WithRepositoryReference(repoRef),
+		WithRepository(repository),
+		WithBundle(bundle),
+	)
+	if err != nil {
+		return err
+	}
+
+	if gwUpdReq == nil {
+		return nil
+	}
+
+	return SyncGateway(ctx, params, *gwUpdReq)
+}
+
+// RepositoryRolloutStatus returns the number of Gateway pods and the number of those pods that have
+// the given commit of a repository reference applied.
+func RepositoryRolloutStatus(ctx context.Context, params Params, repoRef securityv1.RepositoryReference, commit string) (pods int, updatedPods int, err error) {
+	patchAnnotation := "security.brcmlabs.com/" + repoRef.Name + "-" + string(repoRef.Type)
+
+	podList, err := getGatewayPods(ctx, params)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// database backed gateways track the applied commit on the deployment
+	if params.Instance.Spec.App.Management.Database.Enabled {
+		deployment, err := getGatewayDeployment(ctx, params)
+		if err != nil {
+			return 0, 0, err
+		}
+		pods = int(deployment.Status.Replicas)
+		if deployment.ObjectMeta.Annotations[patchAnnotation] == commit {
+			updatedPods = pods
+		}
+		return pods, updatedPods, nil
+	}
+
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		pods = pods + 1
+		if pod.ObjectMeta.Annotations[patchAnnotation] == commit || pod.ObjectMeta.Annotations[patchAnnotation] == commit+"-leader" {
+			updatedPods = updatedPods + 1
+		}
+	}
+	return pods, updatedPods, nil
+}
@@ -0,0 +1,337 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const leaderElectionHistoryLimit = 10
+
+// electLeader tracks the Gateway management pod in a coordination.k8s.io Lease
+// the current holder keeps the Lease while it is running and has not been unready for longer than the grace period.
+// When a new leader is elected the management-access label is moved to it, the Gateway status is updated
+// and singleton bundles are re-applied so that scheduled tasks and inbound listeners move with the leader
+func electLeader(ctx context.Context, params Params) error {
+	gracePeriod := 30 * time.Second
+	if params.Instance.Spec.App.Management.LeaderElection.GracePeriodSeconds != 0 {
+		gracePeriod = time.Duration(params.Instance.Spec.App.Management.LeaderElection.GracePeriodSeconds) * time.Second
+	}
+
+	podList, err := getGatewayPods(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	lease, err := getLeaderLease(ctx, params, gracePeriod)
+	if err != nil {
+		return err
+	}
+
+	holder := ""
+	if lease.Spec.HolderIdentity != nil {
+		holder = *lease.Spec.HolderIdentity
+	}
+
+	reason := "LeaderNotFound"
+	if holder == "" {
+		reason = "Elected"
+		// adopt a pod that was labelled before the Lease existed
+		for _, pod := range podList.Items {
+			if pod.Labels["management-access"] == "leader" && leaderFailoverReason(pod, gracePeriod) == "" {
+				holder = pod.Name
+			}
+		}
+	}
+	for _, pod := range podList.Items {
+		if pod.Name != holder {
+			continue
+		}
+		reason = leaderFailoverReason(pod, gracePeriod)
+		if reason == "" {
+			return renewLeaderLease(ctx, params, lease, podList, holder)
+		}
+	}
+
+	candidate := leaderCandidate(podList)
+	if candidate == "" {
+		params.Log.V(2).Info("no leader candidates available", "name", params.Instance.Name, "namespace", params.Instance.Namespace, "leader", holder)
+		return nil
+	}
+
+	now := metav1.NowMicro()
+	transitions := int32(0)
+	if lease.Spec.LeaseTransitions != nil {
+		transitions = *lease.Spec.LeaseTransitions
+	}
+	if holder != "" {
+		transitions++
+	}
+	lease.Spec.HolderIdentity = &candidate
+	lease.Spec.AcquireTime = &now
+	lease.Spec.RenewTime = &now
+	lease.Spec.LeaseTransitions = &transitions
+
+	// the update fails with a conflict if another reconcile has already changed the Lease
+	if err := params.Client.Update(ctx, lease); err != nil {
+		return err
+	}
+
+	if err := labelLeader(ctx, params, podList, candidate); err != nil {
+		return err
+	}
+
+	params.Log.Info("new leader elected", "name", params.Instance.Name, "namespace", params.Instance.Namespace, "pod", candidate, "previous", holder, "reason", reason)
+	if holder != "" {
+		params.Recorder.Eventf(params.Instance, "Normal", "LeaderElected", "%s in namespace %s: %s replaced %s (%s)", params.Instance.Name, params.Instance.Namespace, candidate, holder, reason)
+	}
+
+	if err := updateLeaderElectionStatus(ctx, params, candidate, transitions, reason); err != nil {
+		return err
+	}
+
+	reapplySingletonBundles(ctx, params)
+	return nil
+}
+
+// reapplySingletonBundles applies repository references to the new leader straight away instead of waiting
+// for the next repository sync. The leader checksum differs from the other pods so only the leader is updated
+func reapplySingletonBundles(ctx context.Context, params Params) {
+	gateway := params.Instance
+	if !gateway.Spec.App.SingletonExtraction || gateway.Spec.App.Management.Database.Enabled {
+		return
+	}
+	for _, repoRef := range gateway.Spec.App.RepositoryReferences {
+		if !repoRef.Enabled || repoRef.Type != securityv1.RepositoryReferenceTypeDynamic {
+			continue
+		}
+		if err := reconcileDynamicRepository(ctx, params, repoRef, false); err != nil {
+			params.Log.V(2).Info("failed to re-apply singleton bundle", "repository", repoRef.Name, "name", gateway.Name, "namespace", gateway.Namespace, "message", err.Error())
+		}
+	}
+}
+
+// leaderFailoverReason returns why the current leader should be replaced or an empty string if it is still eligible
+func leaderFailoverReason(pod corev1.Pod, gracePeriod time.Duration) string {
+	if pod.DeletionTimestamp != nil {
+		return "LeaderTerminating"
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return "LeaderNotRunning"
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodReady || condition.Status == corev1.ConditionTrue {
+			continue
+		}
+		if time.Since(condition.LastTransitionTime.Time) >= gracePeriod {
+			return "LeaderNotReady"
+		}
+	}
+	return ""
+}
+
+// leaderCandidate returns the best pod to lead the Gateway, ready pods are preferred followed by the oldest pod
+func leaderCandidate(podList *corev1.PodList) string {
+	candidates := []corev1.Pod{}
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp == nil && pod.Status.Phase == corev1.PodRunning {
+			candidates = append(candidates, pod)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		iReady, jReady := podReady(candidates[i]), podReady(candidates[j])
+		if iReady != jReady {
+			return iReady
+		}
+		if !candidates[i].CreationTimestamp.Equal(&candidates[j].CreationTimestamp) {
+			return candidates[i].CreationTimestamp.Before(&candidates[j].CreationTimestamp)
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates[0].Name
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func getLeaderLease(ctx context.Context, params Params, gracePeriod time.Duration) (*coordinationv1.Lease, error) {
+	lease := &coordinationv1.Lease{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: params.Instance.Name + "-leader", Namespace: params.Instance.Namespace}, lease)
+	if err == nil {
+		return lease, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	leaseDuration := int32(gracePeriod.Seconds())
+	lease = &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      params.Instance.Name + "-leader",
+			Namespace: params.Instance.Namespace,
+		},
+		Spec: coordinationv1.LeaseSpec{
+			LeaseDurationSeconds: &leaseDuration,
+		},
+	}
+	if err := controllerutil.SetControllerReference(params.Instance, lease, params.Scheme); err != nil {
+		return nil, err
+	}
+	if err := params.Client.Create(ctx, lease); err != nil {
+		return nil, err
+	}
+	params.Log.Info("created leader lease", "name", lease.Name, "namespace", lease.Namespace)
+	return lease, nil
+}
+
+// renewLeaderLease renews the Lease for the current leader and makes sure that it is the only pod with the leader label
+// the Lease is only written when the holder changes or half of the lease duration has passed since the last renewal
+func renewLeaderLease(ctx context.Context, params Params, lease *coordinationv1.Lease, podList *corev1.PodList, leader string) error {
+	if !leaseValid(lease, leader) {
+		now := metav1.NowMicro()
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != leader {
+			lease.Spec.HolderIdentity = &leader
+			lease.Spec.AcquireTime = &now
+		}
+		lease.Spec.RenewTime = &now
+		if err := params.Client.Update(ctx, lease); err != nil {
+			return err
+		}
+	}
+
+	if err := labelLeader(ctx, params, podList, leader); err != nil {
+		return err
+	}
+
+	if params.Instance.Status.LeaderElection.Leader != leader {
+		transitions := int32(0)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions = *lease.Spec.LeaseTransitions
+		}
+		return updateLeaderElectionStatus(ctx, params, leader, transitions, "Elected")
+	}
+	return nil
+}
+
+// leaseValid returns true if leader holds the Lease and it does not need to be renewed yet
+func leaseValid(lease *coordinationv1.Lease, leader string) bool {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != leader || lease.Spec.RenewTime == nil {
+		return false
+	}
+	leaseDuration := 30 * time.Second
+	if lease.Spec.LeaseDurationSeconds != nil && *lease.Spec.LeaseDurationSeconds > 0 {
+		leaseDuration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	return time.Since(lease.Spec.RenewTime.Time) < leaseDuration/2
+}
+
+// labelLeader adds the management-access label to the leader and removes it from every other pod
+func labelLeader(ctx context.Context, params Params, podList *corev1.PodList, leader string) error {
+	for i, pod := range podList.Items {
+		isLeader := pod.Labels["management-access"] == "leader"
+		patch := []byte{}
+		if pod.Name == leader && !isLeader {
+			patch = []byte(`{"metadata":{"labels":{"management-access": "leader"}}}`)
+		}
+		if pod.Name != leader && isLeader {
+			patch = []byte(`{"metadata":{"labels":{"management-access": null}}}`)
+		}
+		if len(patch) == 0 {
+			continue
+		}
+		if err := params.Client.Patch(ctx, &podList.Items[i],
+			client.RawPatch(types.StrategicMergePatchType, patch)); err != nil {
+			params.Log.Error(err, "failed to update pod label", "namespace", params.Instance.Namespace, "name", params.Instance.Name, "pod", pod.Name)
+			return err
+		}
+	}
+	return nil
+}
+
+func updateLeaderElectionStatus(ctx context.Context, params Params, leader string, transitions int32, reason string) error {
+	gateway := params.Instance
+	leaderElection := gateway.Status.LeaderElection
+	leaderElection.Leader = leader
+	leaderElection.LeaseTransitions = transitions
+	leaderElection.History = append(leaderElection.History, securityv1.LeaderElectionEvent{
+		Pod:    leader,
+		Time:   time.Now().Format(time.RFC3339),
+		Reason: reason,
+	})
+	if len(leaderElection.History) > leaderElectionHistoryLimit {
+		leaderElection.History = leaderElection.History[len(leaderElection.History)-leaderElectionHistoryLimit:]
+	}
+
+	gateway.Status.LeaderElection = leaderElection
+	gateway.Status.ManagementPod = leader
+	if err := params.Client.Status().Update(ctx, gateway); err != nil {
+		params.Log.V(2).Info("failed to update leader election status", "name", gateway.Name, "namespace", gateway.Namespace, "message", err.Error())
+		return err
+	}
+	return nil
+}
+
+// leaderElection renews the leader Lease between reconciles so that an unready leader is replaced
+// once the grace period has elapsed
+func leaderElection(ctx context.Context, params Params) {
+	jobTag := params.Instance.Name + "-" + params.Instance.Namespace + "-leader-election"
+	gateway := &securityv1.Gateway{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: params.Instance.Name, Namespace: params.Instance.Namespace}, gateway)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			params.Log.Error(err, "gateway not found", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+			_ = removeJob(jobTag)
+		}
+		return
+	}
+
+	params.Instance = gateway
+	err = electLeader(ctx, params)
+	if err != nil {
+		params.Log.V(2).Info("leader election failed", "name", gateway.Name, "namespace", gateway.Namespace, "message", err.Error())
+	}
+}
@@ -0,0 +1,179 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/gateway"
+	"github.com/caapim/layer7-operator/pkg/util"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func testLeaderPod(name string, created time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: util.DefaultLabels("ssg", map[string]string{}), CreationTimestamp: metav1.NewTime(created)},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created)}},
+		},
+	}
+}
+
+func setPodReady(t *testing.T, k8sClient client.Client, name string, ready bool, since time.Time) {
+	pod := &corev1.Pod{}
+	if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, pod); err != nil {
+		t.Fatal(err)
+	}
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(since)}}
+	if err := k8sClient.Status().Update(context.Background(), pod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestElectLeader(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	gw := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default", UID: "ssg-uid"}}
+	gw.Spec.App.Management.LeaderElection.GracePeriodSeconds = 30
+
+	now := time.Now()
+	// the pods of an upgrade Deployment are never elected
+	upgradePod := testLeaderPod("ssg-upgrade-0", now.Add(-3*time.Hour))
+	upgradePod.Labels[gateway.UpgradeLabel] = "true"
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		gw,
+		testLeaderPod("ssg-0", now.Add(-2*time.Hour)),
+		testLeaderPod("ssg-1", now.Add(-1*time.Hour)),
+		upgradePod,
+	).WithStatusSubresource(&securityv1.Gateway{}, &corev1.Pod{}).Build()
+
+	recorder := record.NewFakeRecorder(10)
+	params := Params{
+		Client:   k8sClient,
+		Recorder: recorder,
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: gw,
+	}
+	getLease := func(t *testing.T) *coordinationv1.Lease {
+		lease := &coordinationv1.Lease{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg-leader", Namespace: "default"}, lease); err != nil {
+			t.Fatal(err)
+		}
+		return lease
+	}
+	leaderLabel := func(t *testing.T, name string) string {
+		pod := &corev1.Pod{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, pod); err != nil {
+			t.Fatal(err)
+		}
+		return pod.Labels["management-access"]
+	}
+
+	t.Run("should elect the oldest ready pod", func(t *testing.T) {
+		if err := electLeader(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		lease := getLease(t)
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != "ssg-0" {
+			t.Fatalf("expected ssg-0 to hold the lease, got %v", lease.Spec.HolderIdentity)
+		}
+		if leaderLabel(t, "ssg-0") != "leader" || leaderLabel(t, "ssg-1") != "" || leaderLabel(t, "ssg-upgrade-0") != "" {
+			t.Errorf("expected only ssg-0 to be labelled as leader")
+		}
+		if gw.Status.LeaderElection.Leader != "ssg-0" || gw.Status.ManagementPod != "ssg-0" {
+			t.Errorf("unexpected status %+v", gw.Status.LeaderElection)
+		}
+	})
+
+	t.Run("should not update a valid lease", func(t *testing.T) {
+		resourceVersion := getLease(t).ResourceVersion
+		if err := electLeader(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if getLease(t).ResourceVersion != resourceVersion {
+			t.Errorf("expected the lease to be unchanged")
+		}
+	})
+
+	t.Run("should keep a not ready leader within the grace period", func(t *testing.T) {
+		setPodReady(t, k8sClient, "ssg-0", false, time.Now())
+		if err := electLeader(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if *getLease(t).Spec.HolderIdentity != "ssg-0" || leaderLabel(t, "ssg-0") != "leader" {
+			t.Errorf("expected ssg-0 to remain leader")
+		}
+	})
+
+	t.Run("should fail over to a ready pod after the grace period", func(t *testing.T) {
+		setPodReady(t, k8sClient, "ssg-0", false, time.Now().Add(-time.Minute))
+		if err := electLeader(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		lease := getLease(t)
+		if *lease.Spec.HolderIdentity != "ssg-1" || lease.Spec.LeaseTransitions == nil || *lease.Spec.LeaseTransitions != 1 {
+			t.Fatalf("expected ssg-1 to take over the lease, got %v", lease.Spec)
+		}
+		if leaderLabel(t, "ssg-1") != "leader" || leaderLabel(t, "ssg-0") != "" {
+			t.Errorf("expected the leader label to move to ssg-1")
+		}
+		history := gw.Status.LeaderElection.History
+		if gw.Status.LeaderElection.Leader != "ssg-1" || history[len(history)-1].Reason != "LeaderNotReady" {
+			t.Errorf("unexpected status %+v", gw.Status.LeaderElection)
+		}
+		select {
+		case event := <-recorder.Events:
+			if event == "" {
+				t.Errorf("expected a LeaderElected event")
+			}
+		default:
+			t.Errorf("expected a LeaderElected event")
+		}
+	})
+}
+
+func TestLeaderCandidate(t *testing.T) {
+	now := time.Now()
+	notReady := testLeaderPod("ssg-0", now.Add(-2*time.Hour))
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+	terminating := testLeaderPod("ssg-1", now.Add(-2*time.Hour))
+	terminating.DeletionTimestamp = &metav1.Time{Time: now}
+	pending := testLeaderPod("ssg-2", now.Add(-2*time.Hour))
+	pending.Status.Phase = corev1.PodPending
+
+	tests := []struct {
+		name string
+		pods []corev1.Pod
+		want string
+	}{
+		{name: "no pods", pods: nil, want: ""},
+		{name: "prefers ready pods", pods: []corev1.Pod{*notReady, *testLeaderPod("ssg-3", now)}, want: "ssg-3"},
+		{name: "falls back to a not ready pod", pods: []corev1.Pod{*notReady, *terminating, *pending}, want: "ssg-0"},
+		{name: "prefers the oldest pod", pods: []corev1.Pod{*testLeaderPod("ssg-4", now), *testLeaderPod("ssg-5", now.Add(-time.Hour))}, want: "ssg-5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leaderCandidate(&corev1.PodList{Items: tt.pods}); got != tt.want {
+				t.Errorf("leaderCandidate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const licenseValidCondition appsv1.DeploymentConditionType = "LicenseValid"
+
+// updateLicenseStatus parses license.xml and reports the license details and a LicenseValid condition in the Gateway status
+// the condition reports a warning N days before the license expires and is false once it has expired or if the
+// license major version does not match the Gateway version
+func updateLicenseStatus(ctx context.Context, params Params, gatewayLicense *corev1.Secret) error {
+	gateway := params.Instance
+	licenseStatus := securityv1.LicenseStatus{}
+	condition := appsv1.DeploymentCondition{Type: licenseValidCondition, Status: corev1.ConditionTrue, Reason: "LicenseValid"}
+
+	license, err := util.ParseGatewayLicense(gatewayLicense.Data["license.xml"])
+	if err != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "InvalidLicense"
+		condition.Message = err.Error()
+	} else {
+		licenseStatus = securityv1.LicenseStatus{
+			Id:           license.Id,
+			Product:      license.Product,
+			MajorVersion: license.MajorVersion,
+			Licensee:     license.Licensee,
+			FeatureSets:  license.FeatureSets,
+		}
+		condition = licenseCondition(gateway, license, time.Now())
+		if !license.Expires.IsZero() {
+			licenseStatus.Expires = license.Expires.Format(time.RFC3339)
+			licenseStatus.DaysToExpiry = daysToExpiry(license.Expires, time.Now())
+			_ = captureLicenseMetrics(ctx, params, licenseStatus.DaysToExpiry)
+		}
+	}
+
+	conditions, changed := setCondition(gateway.Status.Conditions, condition)
+	if !changed && reflect.DeepEqual(gateway.Status.License, licenseStatus) {
+		return nil
+	}
+
+	if changed && condition.Reason != "LicenseValid" {
+		params.Recorder.Eventf(gateway, "Warning", condition.Reason, "%s in namespace %s: %s", gateway.Name, gateway.Namespace, condition.Message)
+	}
+
+	gateway.Status.License = licenseStatus
+	gateway.Status.Conditions = conditions
+	err = params.Client.Status().Update(ctx, gateway)
+	if err != nil {
+		params.Log.V(2).Info("failed to update gateway license status", "name", gateway.Name, "namespace", gateway.Namespace, "message", err.Error())
+		return err
+	}
+	params.Log.V(2).Info("updated gateway license status", "name", gateway.Name, "namespace", gateway.Namespace, "reason", condition.Reason)
+	return nil
+}
+
+func licenseCondition(gateway *securityv1.Gateway, license util.GatewayLicense, now time.Time) appsv1.DeploymentCondition {
+	condition := appsv1.DeploymentCondition{Type: licenseValidCondition, Status: corev1.ConditionTrue, Reason: "LicenseValid", Message: "license is valid"}
+
+	if license.MajorVersion != "" && license.MajorVersion != "*" {
+		for _, version := range []string{gateway.Spec.Version, imageTag(gateway.Spec.App.Image)} {
+			major := strings.Split(version, ".")[0]
+			if _, err := strconv.Atoi(major); err != nil {
+				continue
+			}
+			if major != license.MajorVersion {
+				condition.Status = corev1.ConditionFalse
+				condition.Reason = "VersionMismatch"
+				condition.Message = fmt.Sprintf("license is valid for version %s, gateway version is %s", license.MajorVersion, version)
+				return condition
+			}
+		}
+	}
+
+	if license.Expires.IsZero() {
+		return condition
+	}
+
+	days := daysToExpiry(license.Expires, now)
+	warningDays := gateway.Spec.License.ExpiryWarningDays
+	if warningDays == 0 {
+		warningDays = 30
+	}
+
+	switch {
+	case !now.Before(license.Expires):
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "LicenseExpired"
+		condition.Message = "license expired on " + license.Expires.Format(time.RFC3339)
+	case days <= warningDays:
+		condition.Reason = "LicenseExpiringSoon"
+		condition.Message = fmt.Sprintf("license expires in %d days", days)
+	}
+	return condition
+}
+
+func daysToExpiry(expires time.Time, now time.Time) int {
+	return int(math.Floor(expires.Sub(now).Hours() / 24))
+}
+
+// imageTag returns the tag of an image reference, registry ports and digests are ignored
+func imageTag(image string) string {
+	image = strings.Split(image, "@")[0]
+	i := strings.LastIndex(image, ":")
+	if i == -1 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
+
+// setCondition adds or replaces a condition of the same type
+// LastTransitionTime is only updated if the status changes
+func setCondition(conditions []appsv1.DeploymentCondition, condition appsv1.DeploymentCondition) ([]appsv1.DeploymentCondition, bool) {
+	now := metav1.Now()
+	for i, c := range conditions {
+		if c.Type != condition.Type {
+			continue
+		}
+		if c.Status == condition.Status && c.Reason == condition.Reason && c.Message == condition.Message {
+			return conditions, false
+		}
+		condition.LastUpdateTime = now
+		condition.LastTransitionTime = c.LastTransitionTime
+		if c.Status != condition.Status {
+			condition.LastTransitionTime = now
+		}
+		updated := append([]appsv1.DeploymentCondition{}, conditions...)
+		updated[i] = condition
+		return updated, true
+	}
+	condition.LastUpdateTime = now
+	condition.LastTransitionTime = now
+	return append(conditions, condition), true
+}
@@ -0,0 +1,97 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestLicenseCondition(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	gateway := func(image string, version string, warningDays int) *securityv1.Gateway {
+		gw := &securityv1.Gateway{}
+		gw.Spec.App.Image = image
+		gw.Spec.Version = version
+		gw.Spec.License.ExpiryWarningDays = warningDays
+		return gw
+	}
+
+	tests := []struct {
+		name       string
+		gateway    *securityv1.Gateway
+		license    util.GatewayLicense
+		wantStatus corev1.ConditionStatus
+		wantReason string
+	}{
+		{name: "valid", gateway: gateway("caapim/gateway:11.1.3", "", 0), license: util.GatewayLicense{MajorVersion: "11", Expires: now.AddDate(1, 0, 0)}, wantStatus: corev1.ConditionTrue, wantReason: "LicenseValid"},
+		{name: "does not expire", gateway: gateway("caapim/gateway:11.1.3", "", 0), license: util.GatewayLicense{MajorVersion: "*"}, wantStatus: corev1.ConditionTrue, wantReason: "LicenseValid"},
+		{name: "expiring within the default warning period", gateway: gateway("caapim/gateway:11.1.3", "", 0), license: util.GatewayLicense{Expires: now.AddDate(0, 0, 10)}, wantStatus: corev1.ConditionTrue, wantReason: "LicenseExpiringSoon"},
+		{name: "outside a custom warning period", gateway: gateway("caapim/gateway:11.1.3", "", 5), license: util.GatewayLicense{Expires: now.AddDate(0, 0, 10)}, wantStatus: corev1.ConditionTrue, wantReason: "LicenseValid"},
+		{name: "expired", gateway: gateway("caapim/gateway:11.1.3", "", 0), license: util.GatewayLicense{Expires: now.Add(-time.Hour)}, wantStatus: corev1.ConditionFalse, wantReason: "LicenseExpired"},
+		{name: "image version mismatch", gateway: gateway("caapim/gateway:11.1.3", "", 0), license: util.GatewayLicense{MajorVersion: "10", Expires: now.AddDate(1, 0, 0)}, wantStatus: corev1.ConditionFalse, wantReason: "VersionMismatch"},
+		{name: "spec version mismatch", gateway: gateway("caapim/gateway:latest", "10.1", 0), license: util.GatewayLicense{MajorVersion: "11"}, wantStatus: corev1.ConditionFalse, wantReason: "VersionMismatch"},
+		{name: "image without a version", gateway: gateway("caapim/gateway:latest", "", 0), license: util.GatewayLicense{MajorVersion: "11"}, wantStatus: corev1.ConditionTrue, wantReason: "LicenseValid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := licenseCondition(tt.gateway, tt.license, now)
+			if condition.Status != tt.wantStatus || condition.Reason != tt.wantReason {
+				t.Errorf("licenseCondition() = %s %s, want %s %s", condition.Status, condition.Reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestUpdateLicenseStatusInvalidLicense(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	gw := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw).WithStatusSubresource(gw).Build()
+	recorder := record.NewFakeRecorder(10)
+	params := Params{Client: k8sClient, Recorder: recorder, Scheme: scheme, Log: zap.New(zap.UseDevMode(true)), Instance: gw}
+
+	license := &corev1.Secret{Data: map[string][]byte{"license.xml": []byte("not a license")}}
+	if err := updateLicenseStatus(context.Background(), params, license); err != nil {
+		t.Fatal(err)
+	}
+	if len(gw.Status.Conditions) != 1 || gw.Status.Conditions[0].Status != corev1.ConditionFalse || gw.Status.Conditions[0].Reason != "InvalidLicense" {
+		t.Fatalf("unexpected conditions %+v", gw.Status.Conditions)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expected an InvalidLicense event")
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "caapim/gateway:11.1.3", want: "11.1.3"},
+		{image: "docker.io/caapim/gateway:11.1.3", want: "11.1.3"},
+		{image: "registry.example.com:5000/caapim/gateway:11.1.3", want: "11.1.3"},
+		{image: "registry.example.com:5000/caapim/gateway", want: ""},
+		{image: "caapim/gateway", want: ""},
+		{image: "caapim/gateway:11.1.3@sha256:0123456789abcdef", want: "11.1.3"},
+		{image: "caapim/gateway@sha256:0123456789abcdef", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := imageTag(tt.image); got != tt.want {
+				t.Errorf("imageTag(%q) = %q, want %q", tt.image, got, tt.want)
+			}
+		})
+	}
+}
@@ -5,12 +5,32 @@ import (
 	"strings"
 	"time"
 
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
 	"github.com/caapim/layer7-operator/pkg/util"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
+// appliedCommitInfo reports the commit or checksum of the last bundle of each type and name applied to a Gateway
+var appliedCommitInfo = util.NewInfoGauge("layer7-operator-graphman-metrics", "operator_gateway_applied_commit_info", "commit or checksum of the last bundle applied to a gateway")
+
+// repositoryCommitAge and repositoryPodsBehind report the state of each dynamic repository reference of a Gateway
+var repositoryCommitAge = util.NewGauge("layer7-operator-repository-reference-metrics", "operator_gateway_repository_commit_age", "time since the synced repository commit was made", "s")
+var repositoryPodsBehind = util.NewGauge("layer7-operator-repository-reference-metrics", "operator_gateway_repository_pods_behind", "gateway pods that have not applied the latest repository commit", "")
+
+// externalEntities reports the external secrets, keys and certs applied to a Gateway by type
+var externalEntities = util.NewGauge("layer7-operator-external-entity-metrics", "operator_gateway_external_entities", "external secrets, keys and certs applied to the gateway", "")
+
+// RemoveGatewayMetrics stops reporting gauges for a Gateway that has been removed
+func RemoveGatewayMetrics(namespace string, name string) {
+	prefix := namespace + "/" + name + "/"
+	appliedCommitInfo.DeletePrefix(prefix)
+	repositoryCommitAge.DeletePrefix(prefix)
+	repositoryPodsBehind.DeletePrefix(prefix)
+	externalEntities.DeletePrefix(prefix)
+}
+
 func captureGraphmanMetrics(ctx context.Context, params Params, start time.Time, podName string, bundleType string, bundleName string, sha1sum string, hasError bool) error {
 	operatorNamespace, err := util.GetOperatorNamespace()
 	if err != nil {
@@ -95,26 +115,313 @@ func captureGraphmanMetrics(ctx context.Context, params Params, start time.Time,
 				attribute.String("k8s.pod.name", hostname),
 				attribute.String("k8s.namespace.name", operatorNamespace),
 				attribute.String("gateway_namespace", gateway.Namespace),
-				attribute.String("sha1sum", sha1sum),
 				attribute.String("bundle_type", bundleType),
 				attribute.String("bundle_name", bundleName),
 				attribute.String("gateway_pod", podName),
 				attribute.String("gateway_name", gateway.Name),
-				attribute.String("gateway_version", strings.Split(gateway.Spec.App.Image, ":")[1]),
-				attribute.String("applied_time", time.Now().UTC().String())))
+				attribute.String("gateway_version", strings.Split(gateway.Spec.App.Image, ":")[1])))
 	} else {
 		graphmanRequestSuccess.Add(ctx, 1,
 			metric.WithAttributes(
 				attribute.String("k8s.pod.name", hostname),
 				attribute.String("k8s.namespace.name", operatorNamespace),
 				attribute.String("gateway_namespace", gateway.Namespace),
-				attribute.String("sha1sum", sha1sum),
 				attribute.String("bundle_type", bundleType),
 				attribute.String("bundle_name", bundleName),
 				attribute.String("gateway_pod", podName),
 				attribute.String("gateway_name", gateway.Name),
-				attribute.String("gateway_version", strings.Split(gateway.Spec.App.Image, ":")[1]),
-				attribute.String("applied_time", time.Now().UTC().String())))
+				attribute.String("gateway_version", strings.Split(gateway.Spec.App.Image, ":")[1])))
+
+		// commits are reported as an info metric rather than a label on the request counters
+		if err := appliedCommitInfo.Set(gateway.Namespace+"/"+gateway.Name+"/"+bundleType+"/"+bundleName,
+			attribute.String("gateway_namespace", gateway.Namespace),
+			attribute.String("gateway_name", gateway.Name),
+			attribute.String("bundle_type", bundleType),
+			attribute.String("bundle_name", bundleName),
+			attribute.String("commit", sha1sum)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func captureDriftMetrics(ctx context.Context, params Params, repositoryReference string, driftedEntities int, driftedPods int) error {
+	operatorNamespace, err := util.GetOperatorNamespace()
+	if err != nil {
+		params.Log.Info("could not determine operator namespace")
+		return err
+	}
+	gateway := params.Instance
+	otelEnabled, err := util.GetOtelEnabled()
+	if err != nil {
+		params.Log.Info("could not determine if OTel is enabled")
+		return err
+	}
+
+	if !otelEnabled {
+		return nil
+	}
+
+	otelMetricPrefix, err := util.GetOtelMetricPrefix()
+	if err != nil {
+		params.Log.Info("could not determine otel metric prefix")
+		return err
+	}
+
+	if otelMetricPrefix == "" {
+		otelMetricPrefix = "layer7_"
+	}
+
+	hostname, err := util.GetHostname()
+	if err != nil {
+		params.Log.Error(err, "failed to retrieve operator hostname")
+		return err
+	}
+
+	meter := otel.Meter("layer7-operator-drift-metrics")
+
+	driftedEntitiesGauge, err := meter.Int64Gauge(otelMetricPrefix+"operator_drifted_entities",
+		metric.WithDescription("entities that are missing or modified on the gateway"))
+	if err != nil {
+		return err
+	}
+
+	driftedPodsGauge, err := meter.Int64Gauge(otelMetricPrefix+"operator_drifted_pods",
+		metric.WithDescription("gateway pods with one or more drifted entities"))
+	if err != nil {
+		return err
+	}
+
+	attributes := metric.WithAttributes(
+		attribute.String("k8s.pod.name", hostname),
+		attribute.String("k8s.namespace.name", operatorNamespace),
+		attribute.String("gateway_namespace", gateway.Namespace),
+		attribute.String("gateway_name", gateway.Name),
+		attribute.String("repository_name", repositoryReference))
+
+	driftedEntitiesGauge.Record(ctx, int64(driftedEntities), attributes)
+	driftedPodsGauge.Record(ctx, int64(driftedPods), attributes)
+
+	return nil
+}
+
+func captureLicenseMetrics(ctx context.Context, params Params, daysToExpiry int) error {
+	operatorNamespace, err := util.GetOperatorNamespace()
+	if err != nil {
+		params.Log.Info("could not determine operator namespace")
+		return err
+	}
+	gateway := params.Instance
+	otelEnabled, err := util.GetOtelEnabled()
+	if err != nil {
+		params.Log.Info("could not determine if OTel is enabled")
+		return err
+	}
+
+	if !otelEnabled {
+		return nil
+	}
+
+	otelMetricPrefix, err := util.GetOtelMetricPrefix()
+	if err != nil {
+		params.Log.Info("could not determine otel metric prefix")
+		return err
+	}
+
+	if otelMetricPrefix == "" {
+		otelMetricPrefix = "layer7_"
+	}
+
+	hostname, err := util.GetHostname()
+	if err != nil {
+		params.Log.Error(err, "failed to retrieve operator hostname")
+		return err
+	}
+
+	meter := otel.Meter("layer7-operator-license-metrics")
+
+	daysToExpiryGauge, err := meter.Int64Gauge(otelMetricPrefix+"operator_gateway_license_days_to_expiry",
+		metric.WithDescription("days until the gateway license expires"))
+	if err != nil {
+		return err
+	}
+
+	daysToExpiryGauge.Record(ctx, int64(daysToExpiry), metric.WithAttributes(
+		attribute.String("k8s.pod.name", hostname),
+		attribute.String("k8s.namespace.name", operatorNamespace),
+		attribute.String("gateway_namespace", gateway.Namespace),
+		attribute.String("gateway_name", gateway.Name)))
+
+	return nil
+}
+
+func captureRepositoryReferenceMetrics(ctx context.Context, params Params, repositoryReference string, commitAge time.Duration, podsBehind int) error {
+	operatorNamespace, err := util.GetOperatorNamespace()
+	if err != nil {
+		params.Log.Info("could not determine operator namespace")
+		return err
+	}
+	gateway := params.Instance
+	otelEnabled, err := util.GetOtelEnabled()
+	if err != nil {
+		params.Log.Info("could not determine if OTel is enabled")
+		return err
+	}
+
+	if !otelEnabled {
+		return nil
+	}
+
+	hostname, err := util.GetHostname()
+	if err != nil {
+		params.Log.Error(err, "failed to retrieve operator hostname")
+		return err
+	}
+
+	attributes := []attribute.KeyValue{
+		attribute.String("k8s.pod.name", hostname),
+		attribute.String("k8s.namespace.name", operatorNamespace),
+		attribute.String("gateway_namespace", gateway.Namespace),
+		attribute.String("gateway_name", gateway.Name),
+		attribute.String("repository_name", repositoryReference)}
+
+	key := gateway.Namespace + "/" + gateway.Name + "/" + repositoryReference
+	if err := repositoryCommitAge.Set(key, int64(commitAge.Seconds()), attributes...); err != nil {
+		return err
+	}
+	return repositoryPodsBehind.Set(key, int64(podsBehind), attributes...)
+}
+
+func captureRepositoryVerifyMetrics(ctx context.Context, params Params, repositoryReference string, results []securityv1.SmokeTestResult, rolledBackPods int) error {
+	operatorNamespace, err := util.GetOperatorNamespace()
+	if err != nil {
+		params.Log.Info("could not determine operator namespace")
+		return err
+	}
+	gateway := params.Instance
+	otelEnabled, err := util.GetOtelEnabled()
+	if err != nil {
+		params.Log.Info("could not determine if OTel is enabled")
+		return err
+	}
+
+	if !otelEnabled {
+		return nil
+	}
+
+	otelMetricPrefix, err := util.GetOtelMetricPrefix()
+	if err != nil {
+		params.Log.Info("could not determine otel metric prefix")
+		return err
+	}
+
+	if otelMetricPrefix == "" {
+		otelMetricPrefix = "layer7_"
+	}
+
+	hostname, err := util.GetHostname()
+	if err != nil {
+		params.Log.Error(err, "failed to retrieve operator hostname")
+		return err
+	}
+
+	meter := otel.Meter("layer7-operator-repository-reference-metrics")
+
+	verifySuccess, err := meter.Int64Counter(otelMetricPrefix+"operator_gateway_repository_verify_success",
+		metric.WithDescription("repository reference verify checks that passed"))
+	if err != nil {
+		return err
+	}
+
+	verifyFailure, err := meter.Int64Counter(otelMetricPrefix+"operator_gateway_repository_verify_failure",
+		metric.WithDescription("repository reference verify checks that failed"))
+	if err != nil {
+		return err
+	}
+
+	rollbacks, err := meter.Int64Counter(otelMetricPrefix+"operator_gateway_repository_rollback",
+		metric.WithDescription("gateway pods that were rolled back to the previous repository commit"))
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		attributes := metric.WithAttributes(
+			attribute.String("k8s.pod.name", hostname),
+			attribute.String("k8s.namespace.name", operatorNamespace),
+			attribute.String("gateway_namespace", gateway.Namespace),
+			attribute.String("gateway_name", gateway.Name),
+			attribute.String("gateway_pod", result.Pod),
+			attribute.String("repository_name", repositoryReference),
+			attribute.String("check_name", result.Name))
+		if result.Passed {
+			verifySuccess.Add(ctx, 1, attributes)
+		} else {
+			verifyFailure.Add(ctx, 1, attributes)
+		}
+	}
+
+	if rolledBackPods > 0 {
+		rollbacks.Add(ctx, int64(rolledBackPods), metric.WithAttributes(
+			attribute.String("k8s.pod.name", hostname),
+			attribute.String("k8s.namespace.name", operatorNamespace),
+			attribute.String("gateway_namespace", gateway.Namespace),
+			attribute.String("gateway_name", gateway.Name),
+			attribute.String("repository_name", repositoryReference)))
+	}
+
+	return nil
+}
+
+func captureExternalEntityMetrics(ctx context.Context, params Params) error {
+	operatorNamespace, err := util.GetOperatorNamespace()
+	if err != nil {
+		params.Log.Info("could not determine operator namespace")
+		return err
+	}
+	gateway := params.Instance
+	otelEnabled, err := util.GetOtelEnabled()
+	if err != nil {
+		params.Log.Info("could not determine if OTel is enabled")
+		return err
+	}
+
+	if !otelEnabled {
+		return nil
+	}
+
+	hostname, err := util.GetHostname()
+	if err != nil {
+		params.Log.Error(err, "failed to retrieve operator hostname")
+		return err
+	}
+
+	externalSecrets := 0
+	for _, secrets := range gateway.Status.LastAppliedExternalSecrets {
+		externalSecrets = externalSecrets + len(secrets)
+	}
+	externalCerts := 0
+	for _, certs := range gateway.Status.LastAppliedExternalCerts {
+		externalCerts = externalCerts + len(certs)
+	}
+
+	counts := map[string]int{
+		"secret": externalSecrets,
+		"key":    len(gateway.Status.LastAppliedExternalKeys),
+		"cert":   externalCerts,
+	}
+
+	for entityType, count := range counts {
+		err = externalEntities.Set(gateway.Namespace+"/"+gateway.Name+"/"+entityType, int64(count),
+			attribute.String("k8s.pod.name", hostname),
+			attribute.String("k8s.namespace.name", operatorNamespace),
+			attribute.String("gateway_namespace", gateway.Namespace),
+			attribute.String("gateway_name", gateway.Name),
+			attribute.String("entity_type", entityType))
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,109 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/caapim/layer7-operator/pkg/gateway"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Monitoring(ctx context.Context, params Params) error {
+	desiredMonitor := gateway.NewMonitor(params.Instance)
+
+	if err := reconcileMonitors(ctx, params, desiredMonitor); err != nil {
+		return fmt.Errorf("failed to reconcile monitoring: %w", err)
+	}
+	return nil
+}
+
+func reconcileMonitors(ctx context.Context, params Params, desiredMonitor *unstructured.Unstructured) error {
+	for _, gvk := range gateway.MonitorKinds {
+		currentMonitor := &unstructured.Unstructured{}
+		currentMonitor.SetGroupVersionKind(gvk)
+
+		err := params.Client.Get(ctx, types.NamespacedName{Name: desiredMonitor.GetName(), Namespace: params.Instance.Namespace}, currentMonitor)
+
+		// the Prometheus Operator CRDs for this monitor kind are not installed
+		if meta.IsNoMatchError(err) {
+			if params.Instance.Spec.App.Monitoring.Enabled && gvk.Kind == desiredMonitor.GetKind() {
+				params.Log.Info("monitor kind is not available", "kind", gvk.Kind, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+			}
+			continue
+		}
+
+		// remove the monitor if monitoring is disabled or the monitor type has changed
+		if !params.Instance.Spec.App.Monitoring.Enabled || gvk.Kind != desiredMonitor.GetKind() {
+			if err == nil && controllerutil.HasControllerReference(currentMonitor) {
+				if err := params.Client.Delete(ctx, currentMonitor); err != nil {
+					return err
+				}
+				params.Log.Info("removed monitor", "kind", currentMonitor.GetKind(), "name", currentMonitor.GetName(), "namespace", params.Instance.Namespace)
+			}
+			continue
+		}
+
+		if err := controllerutil.SetControllerReference(params.Instance, desiredMonitor, params.Scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+
+		if err != nil && k8serrors.IsNotFound(err) {
+			if err = params.Client.Create(ctx, desiredMonitor); err != nil {
+				return err
+			}
+			params.Log.Info("created monitor", "kind", desiredMonitor.GetKind(), "name", desiredMonitor.GetName(), "namespace", params.Instance.Namespace)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if equality.Semantic.DeepEqual(currentMonitor.Object["spec"], desiredMonitor.Object["spec"]) && equality.Semantic.DeepEqual(currentMonitor.GetLabels(), desiredMonitor.GetLabels()) {
+			params.Log.V(2).Info("no monitor updates needed", "kind", desiredMonitor.GetKind(), "name", desiredMonitor.GetName(), "namespace", desiredMonitor.GetNamespace())
+			continue
+		}
+
+		updatedMonitor := currentMonitor.DeepCopy()
+		updatedMonitor.Object["spec"] = desiredMonitor.Object["spec"]
+		updatedMonitor.SetOwnerReferences(desiredMonitor.GetOwnerReferences())
+		updatedMonitor.SetLabels(desiredMonitor.GetLabels())
+
+		patch := client.MergeFrom(currentMonitor)
+		if err := params.Client.Patch(ctx, updatedMonitor, patch); err != nil {
+			return err
+		}
+		params.Log.Info("monitor updated", "kind", desiredMonitor.GetKind(), "name", desiredMonitor.GetName(), "namespace", desiredMonitor.GetNamespace())
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/gateway"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// otkInitContainerName is the initContainer that installs OTK into the Gateway pods
+const otkInitContainerName = "otk-install-init"
+
+const (
+	OtkSchemaJobRunning   = "Running"
+	OtkSchemaJobSucceeded = "Succeeded"
+	OtkSchemaJobFailed    = "Failed"
+)
+
+// OtkSchema runs the OTK schema Job once for each OTK version and reports the result on the Gateway status
+func OtkSchema(ctx context.Context, params Params) error {
+	if !gateway.OtkSchemaJobEnabled(params.Instance) {
+		return nil
+	}
+
+	desiredJob := gateway.NewOtkSchemaJob(params.Instance)
+	if err := controllerutil.SetControllerReference(params.Instance, desiredJob, params.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	jobList := &batchv1.JobList{}
+	if err := params.Client.List(ctx, jobList, client.InNamespace(params.Instance.Namespace), client.MatchingLabels(gateway.OtkSchemaJobLabels(params.Instance))); err != nil {
+		return fmt.Errorf("failed to list otk schema jobs: %w", err)
+	}
+
+	// schema jobs for previous OTK versions are no longer needed once the image changes
+	for i := range jobList.Items {
+		job := jobList.Items[i]
+		if job.Name == desiredJob.Name || !controllerutil.HasControllerReference(&job) {
+			continue
+		}
+		if err := params.Client.Delete(ctx, &job, client.PropagationPolicy("Background")); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to remove otk schema job %s: %w", job.Name, err)
+		}
+		params.Log.Info("removed otk schema job", "job", job.Name, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+	}
+
+	currentJob := &batchv1.Job{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: desiredJob.Name, Namespace: params.Instance.Namespace}, currentJob)
+	if err != nil && k8serrors.IsNotFound(err) {
+		if err = params.Client.Create(ctx, desiredJob); err != nil {
+			return fmt.Errorf("failed creating otk schema job: %w", err)
+		}
+		params.Log.Info("created otk schema job", "job", desiredJob.Name, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+		currentJob = desiredJob
+	} else if err != nil {
+		return err
+	}
+
+	status := otkSchemaStatus(params.Instance.Status.OtkSchema, currentJob, gateway.OtkVersion(params.Instance))
+	if !reflect.DeepEqual(status, params.Instance.Status.OtkSchema) {
+		params.Instance.Status.OtkSchema = status
+		if err := params.Client.Status().Update(ctx, params.Instance); err != nil {
+			return fmt.Errorf("failed to update otk schema status: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// otkSchemaStatus derives the schema status from the Job for the current OTK version
+func otkSchemaStatus(status securityv1.OtkSchemaStatus, job *batchv1.Job, version string) securityv1.OtkSchemaStatus {
+	status.Job = job.Name
+	status.Result = OtkSchemaJobRunning
+	status.Reason = ""
+
+	if job.Status.Succeeded > 0 {
+		status.Result = OtkSchemaJobSucceeded
+		if status.Version != version {
+			status.Version = version
+			status.LastMigration = time.Now().Format(time.RFC3339)
+		}
+		return status
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			status.Result = OtkSchemaJobFailed
+			status.Reason = condition.Message
+		}
+	}
+	return status
+}
+
+// otkSchemaReady is true when the OTK schema Job for the current OTK version has succeeded
+func otkSchemaReady(ctx context.Context, params Params) (bool, error) {
+	if !gateway.OtkSchemaJobEnabled(params.Instance) {
+		return true, nil
+	}
+	job := &batchv1.Job{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: gateway.OtkSchemaJobName(params.Instance), Namespace: params.Instance.Namespace}, job)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return job.Status.Succeeded > 0, nil
+}
+
+// reportOtkSchemaHold reports that Gateway and OTK version changes are held until the OTK schema Job succeeds
+func reportOtkSchemaHold(params Params) {
+	gw := params.Instance
+	job := gateway.OtkSchemaJobName(gw)
+	if gw.Status.OtkSchema.Result == OtkSchemaJobFailed {
+		params.Log.Info("otk schema job failed, gateway and otk version changes are held", "job", job, "reason", gw.Status.OtkSchema.Reason, "name", gw.Name, "namespace", gw.Namespace)
+		params.Recorder.Eventf(gw, "Warning", "OtkSchemaJobFailed", "%s in namespace %s: otk schema job %s failed, gateway and otk version changes are held: %s", gw.Name, gw.Namespace, job, gw.Status.OtkSchema.Reason)
+		return
+	}
+	params.Log.V(2).Info("waiting for otk schema job", "job", job, "name", gw.Name, "namespace", gw.Namespace)
+}
+
+// holdVersionChanges keeps the Gateway and OTK images of the current Deployment in the desired Deployment
+// so that other changes are still applied. It returns false if OTK has just been enabled and the rollout must wait
+func holdVersionChanges(current *appsv1.Deployment, desired *appsv1.Deployment) bool {
+	images := map[string]string{}
+	for _, container := range current.Spec.Template.Spec.InitContainers {
+		images["init/"+container.Name] = container.Image
+	}
+	for _, container := range current.Spec.Template.Spec.Containers {
+		images[container.Name] = container.Image
+	}
+
+	for i, container := range desired.Spec.Template.Spec.InitContainers {
+		if container.Name != otkInitContainerName {
+			continue
+		}
+		image, ok := images["init/"+container.Name]
+		if !ok {
+			return false
+		}
+		desired.Spec.Template.Spec.InitContainers[i].Image = image
+	}
+	for i, container := range desired.Spec.Template.Spec.Containers {
+		if container.Name != "gateway" {
+			continue
+		}
+		if image, ok := images[container.Name]; ok {
+			desired.Spec.Template.Spec.Containers[i].Image = image
+		}
+	}
+	return true
+}
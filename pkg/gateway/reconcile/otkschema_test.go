@@ -0,0 +1,145 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/gateway"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestOtkSchema(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	gw := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "otk", Namespace: "default", UID: "otk-uid"}}
+	gw.Spec.App.Otk = securityv1.Otk{
+		Enabled:            true,
+		Type:               securityv1.OtkTypeSingle,
+		InitContainerImage: "docker.io/caapim/otk-install:4.6.3",
+		Database:           securityv1.OtkDatabase{Type: securityv1.OtkDatabaseTypeMySQL, Create: true},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw).WithStatusSubresource(&securityv1.Gateway{}, &batchv1.Job{}).Build()
+	params := Params{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: gw,
+	}
+	jobName := types.NamespacedName{Name: gateway.OtkSchemaJobName(gw), Namespace: "default"}
+
+	t.Run("should create the schema job and wait for it", func(t *testing.T) {
+		if err := OtkSchema(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if err := k8sClient.Get(context.Background(), jobName, &batchv1.Job{}); err != nil {
+			t.Fatal(err)
+		}
+		if gw.Status.OtkSchema.Result != OtkSchemaJobRunning || gw.Status.OtkSchema.Job != jobName.Name {
+			t.Errorf("unexpected status %+v", gw.Status.OtkSchema)
+		}
+		if ready, _ := otkSchemaReady(context.Background(), params); ready {
+			t.Errorf("expected the deployment to wait for the schema job")
+		}
+	})
+
+	t.Run("should report a failed schema job", func(t *testing.T) {
+		job := &batchv1.Job{}
+		_ = k8sClient.Get(context.Background(), jobName, job)
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+		if err := k8sClient.Status().Update(context.Background(), job); err != nil {
+			t.Fatal(err)
+		}
+		if err := OtkSchema(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if gw.Status.OtkSchema.Result != OtkSchemaJobFailed || gw.Status.OtkSchema.Reason != "BackoffLimitExceeded" {
+			t.Errorf("unexpected status %+v", gw.Status.OtkSchema)
+		}
+	})
+
+	t.Run("should report the migrated version", func(t *testing.T) {
+		job := &batchv1.Job{}
+		_ = k8sClient.Get(context.Background(), jobName, job)
+		job.Status.Conditions = nil
+		job.Status.Succeeded = 1
+		if err := k8sClient.Status().Update(context.Background(), job); err != nil {
+			t.Fatal(err)
+		}
+		if err := OtkSchema(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status := gw.Status.OtkSchema
+		if status.Result != OtkSchemaJobSucceeded || status.Version != "4.6.3" || status.LastMigration == "" {
+			t.Errorf("unexpected status %+v", status)
+		}
+		if ready, _ := otkSchemaReady(context.Background(), params); !ready {
+			t.Errorf("expected the deployment to be rolled out")
+		}
+	})
+
+	t.Run("should replace the job when the otk version changes", func(t *testing.T) {
+		gw.Spec.App.Otk.InitContainerImage = "docker.io/caapim/otk-install:4.6.4"
+		if err := k8sClient.Update(context.Background(), gw); err != nil {
+			t.Fatal(err)
+		}
+		if err := OtkSchema(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		jobs := &batchv1.JobList{}
+		if err := k8sClient.List(context.Background(), jobs, client.InNamespace("default")); err != nil {
+			t.Fatal(err)
+		}
+		if len(jobs.Items) != 1 || jobs.Items[0].Name != gateway.OtkSchemaJobName(gw) {
+			t.Errorf("expected only the job for the new version, actual %d jobs", len(jobs.Items))
+		}
+		if gw.Status.OtkSchema.Version != "4.6.3" || gw.Status.OtkSchema.Result != OtkSchemaJobRunning {
+			t.Errorf("expected the previous version to be kept until the upgrade succeeds, actual %+v", gw.Status.OtkSchema)
+		}
+	})
+}
+
+func TestHoldVersionChanges(t *testing.T) {
+	deployment := func(gatewayImage string, otkImage string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.Spec.Template.Spec.Containers = []corev1.Container{{Name: "gateway", Image: gatewayImage}}
+		if otkImage != "" {
+			dep.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: otkInitContainerName, Image: otkImage}}
+		}
+		return dep
+	}
+
+	tests := []struct {
+		name        string
+		current     *appsv1.Deployment
+		desired     *appsv1.Deployment
+		rollout     bool
+		wantGateway string
+		wantOtk     string
+	}{
+		{name: "holds gateway and otk upgrades", current: deployment("gateway:11.1.00", "otk-install:4.6.3"), desired: deployment("gateway:11.1.01", "otk-install:4.6.4"), rollout: true, wantGateway: "gateway:11.1.00", wantOtk: "otk-install:4.6.3"},
+		{name: "waits when otk is enabled", current: deployment("gateway:11.1.00", ""), desired: deployment("gateway:11.1.00", "otk-install:4.6.3"), rollout: false, wantGateway: "gateway:11.1.00", wantOtk: "otk-install:4.6.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := holdVersionChanges(tt.current, tt.desired); got != tt.rollout {
+				t.Fatalf("holdVersionChanges() = %v, want %v", got, tt.rollout)
+			}
+			if gatewayImage(tt.desired) != tt.wantGateway || tt.desired.Spec.Template.Spec.InitContainers[0].Image != tt.wantOtk {
+				t.Errorf("unexpected images %s %s", gatewayImage(tt.desired), tt.desired.Spec.Template.Spec.InitContainers[0].Image)
+			}
+		})
+	}
+}
@@ -43,7 +43,7 @@ import (
 
 func Routes(ctx context.Context, params Params) error {
 	desiredRoutes := []*routev1.Route{}
-	if strings.ToLower(params.Instance.Spec.App.Ingress.Type) == "ingress" {
+	if strings.ToLower(params.Instance.Spec.App.Ingress.Type) == "ingress" || strings.ToLower(params.Instance.Spec.App.Ingress.Type) == "gatewayapi" {
 		return nil
 	}
 	for index, route := range params.Instance.Spec.App.Ingress.Routes {
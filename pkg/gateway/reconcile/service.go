@@ -63,8 +63,13 @@ func reconcileServices(ctx context.Context, params Params, desiredServices []*co
 		}
 
 		if reflect.DeepEqual(currentService.Spec.Ports, desiredService.Spec.Ports) && reflect.DeepEqual(currentService.Spec.Type, desiredService.Spec.Type) {
-			params.Log.V(2).Info("no service updates needed", "name", desiredService.Name, "namespace", desiredService.Namespace)
-			return nil
+			// the selector moves between Deployments during a Gateway upgrade
+			if reflect.DeepEqual(currentService.Spec.Selector, desiredService.Spec.Selector) {
+				params.Log.V(2).Info("no service updates needed", "name", desiredService.Name, "namespace", desiredService.Namespace)
+				return nil
+			}
+			updated = currentService.DeepCopy()
+			updated.Spec.Selector = desiredService.Spec.Selector
 		}
 
 		patch := client.MergeFrom(&currentService)
@@ -0,0 +1,130 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// smokeTestClient is shared by all smoke tests, connections are not kept alive because pods are replaced between runs
+var smokeTestClient = &http.Client{Transport: &http.Transport{
+	TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+	DisableKeepAlives: true,
+}}
+
+// runSmokeTests sends each smoke test to each pod with at most limit requests in flight
+// results are returned in smoke test then pod order
+func runSmokeTests(ctx context.Context, smokeTests []securityv1.SmokeTest, pods []corev1.Pod, limit int) []securityv1.SmokeTestResult {
+	results := make([]securityv1.SmokeTestResult, len(smokeTests)*len(pods))
+	_ = util.RunConcurrently(limit, len(results), func(i int) error {
+		smokeTest := smokeTests[i/len(pods)]
+		pod := pods[i%len(pods)]
+		name := smokeTest.Name
+		if name == "" {
+			name = smokeTest.Path
+		}
+		results[i] = securityv1.SmokeTestResult{Name: name, Pod: pod.Name, Passed: true}
+		if err := runSmokeTest(ctx, smokeTest, pod.Status.PodIP); err != nil {
+			results[i].Passed = false
+			results[i].Message = err.Error()
+		}
+		return nil
+	})
+	return results
+}
+
+func runSmokeTest(ctx context.Context, smokeTest securityv1.SmokeTest, podIP string) error {
+	method := http.MethodGet
+	if smokeTest.Method != "" {
+		method = strings.ToUpper(smokeTest.Method)
+	}
+	scheme := "https"
+	if smokeTest.Scheme != "" {
+		scheme = smokeTest.Scheme
+	}
+	port := int32(8443)
+	if smokeTest.Port != 0 {
+		port = smokeTest.Port
+	}
+	expectedStatus := http.StatusOK
+	if smokeTest.ExpectedStatus != 0 {
+		expectedStatus = smokeTest.ExpectedStatus
+	}
+	timeout := 10 * time.Second
+	if smokeTest.TimeoutSeconds != 0 {
+		timeout = time.Duration(smokeTest.TimeoutSeconds) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url := scheme + "://" + net.JoinHostPort(podIP, strconv.Itoa(int(port))) + "/" + strings.TrimPrefix(smokeTest.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(smokeTest.Body))
+	if err != nil {
+		return err
+	}
+	for k, v := range smokeTest.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := smokeTestClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		return fmt.Errorf("expected status %d, got %d", expectedStatus, resp.StatusCode)
+	}
+
+	if smokeTest.ExpectedBody != "" {
+		expectedBody, err := regexp.Compile(smokeTest.ExpectedBody)
+		if err != nil {
+			return fmt.Errorf("invalid expectedBody: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if !expectedBody.Match(body) {
+			return fmt.Errorf("response body does not match %s", smokeTest.ExpectedBody)
+		}
+	}
+	return nil
+}
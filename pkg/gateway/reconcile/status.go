@@ -29,9 +29,17 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	securityv1 "github.com/caapim/layer7-operator/api/v1"
 	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	"github.com/caapim/layer7-operator/internal/graphman"
+	"github.com/caapim/layer7-operator/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -84,6 +92,14 @@ func GatewayStatus(ctx context.Context, params Params) error {
 			return fmt.Errorf("repository %s is not ready yet", repository.Name)
 		}
 
+		if repoRef.Enabled && repoRef.Type == securityv1.RepositoryReferenceTypeDynamic {
+			commitAge := time.Duration(0)
+			if commitTime, err := util.ParseStatusTime(repository.Status.CommitTime); err == nil {
+				commitAge = time.Since(commitTime)
+			}
+			_ = captureRepositoryReferenceMetrics(ctx, params, repoRef.Name, commitAge, podsBehind(params.Instance, podList, dep, repoRef, repository.Status.Commit))
+		}
+
 		found := false
 		for i, repoStatus := range gatewayStatus.RepositoryStatus {
 			if repoStatus.Name == repository.Name {
@@ -117,6 +133,7 @@ func GatewayStatus(ctx context.Context, params Params) error {
 				gatewayStatus.ManagementPod = p.Name
 			}
 		}
+		gatewayStatus.Gateway = gatewayStates(params.Instance, podList)
 	}
 
 	if !reflect.DeepEqual(gatewayStatus, params.Instance.Status) {
@@ -128,9 +145,69 @@ func GatewayStatus(ctx context.Context, params Params) error {
 		}
 		params.Log.V(2).Info("updated gateway status", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
 	}
+	_ = captureExternalEntityMetrics(ctx, params)
 	return nil
 }
 
+// gatewayStates reports the phase, readiness and Graphman health of each Gateway pod
+func gatewayStates(gateway *securityv1.Gateway, podList *corev1.PodList) []securityv1.GatewayState {
+	graphmanPort := 9443
+	if gateway.Spec.App.Management.Graphman.DynamicSyncPort != 0 {
+		graphmanPort = gateway.Spec.App.Management.Graphman.DynamicSyncPort
+	}
+
+	states := []securityv1.GatewayState{}
+	for _, pod := range podList.Items {
+		state := securityv1.GatewayState{
+			Name:  pod.Name,
+			Phase: pod.Status.Phase,
+		}
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name == "gateway" {
+				state.Ready = containerStatus.Ready
+			}
+		}
+		if pod.Status.StartTime != nil {
+			state.StartTime = pod.Status.StartTime.Format(time.RFC3339)
+		}
+		if pod.Status.PodIP != "" {
+			healthy, reason := graphman.TargetHealth(util.PodIP(pod.Status.PodIP) + ":" + strconv.Itoa(graphmanPort))
+			state.Unhealthy = !healthy
+			state.Reason = reason
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// podsBehind returns the number of Gateway pods that have not applied commit for a dynamic repository reference
+// database backed Gateways track the applied commit on the deployment so all pods are behind if it does not match
+func podsBehind(gateway *securityv1.Gateway, podList *corev1.PodList, dep *appsv1.Deployment, repoRef securityv1.RepositoryReference, commit string) int {
+	if podList == nil {
+		return 0
+	}
+	annotation := "security.brcmlabs.com/" + repoRef.Name + "-" + string(repoRef.Type)
+
+	behind := 0
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		applied := strings.TrimSuffix(pod.ObjectMeta.Annotations[annotation], "-leader")
+		if gateway.Spec.App.Management.Database.Enabled {
+			applied = ""
+			if dep != nil {
+				applied = dep.Annotations[annotation]
+			}
+		}
+		if applied != commit {
+			behind = behind + 1
+		}
+	}
+	return behind
+}
+
 func buildRepoStatus(ctx context.Context, params Params, repository securityv1.Repository, repoRef securityv1.RepositoryReference) (repoStatus securityv1.GatewayRepositoryStatus, err error) {
 	secretName := repository.Name
 	if repository.Spec.Auth.ExistingSecretName != "" {
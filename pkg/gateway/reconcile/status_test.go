@@ -0,0 +1,45 @@
+package reconcile
+
+import (
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodsBehind(t *testing.T) {
+	repoRef := securityv1.RepositoryReference{Name: "my-repo", Enabled: true, Type: securityv1.RepositoryReferenceTypeDynamic}
+	annotation := "security.brcmlabs.com/my-repo-dynamic"
+	now := metav1.Now()
+
+	podList := &corev1.PodList{Items: []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "current", Annotations: map[string]string{annotation: "commit2"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "leader", Annotations: map[string]string{annotation: "commit2-leader"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "behind", Annotations: map[string]string{annotation: "commit1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "new"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "terminating", DeletionTimestamp: &now, Annotations: map[string]string{annotation: "commit1"}}},
+	}}
+
+	t.Run("should compare pod annotations for ephemeral gateways", func(t *testing.T) {
+		gateway := &securityv1.Gateway{}
+		behind := podsBehind(gateway, podList, nil, repoRef, "commit2")
+		if behind != 2 {
+			t.Errorf("expected %d pods behind, actual %d", 2, behind)
+		}
+	})
+
+	t.Run("should compare the deployment annotation for database backed gateways", func(t *testing.T) {
+		gateway := &securityv1.Gateway{}
+		gateway.Spec.App.Management.Database.Enabled = true
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotation: "commit1"}}}
+		if behind := podsBehind(gateway, podList, dep, repoRef, "commit2"); behind != 4 {
+			t.Errorf("expected %d pods behind, actual %d", 4, behind)
+		}
+		dep.Annotations[annotation] = "commit2"
+		if behind := podsBehind(gateway, podList, dep, repoRef, "commit2"); behind != 0 {
+			t.Errorf("expected %d pods behind, actual %d", 0, behind)
+		}
+	})
+}
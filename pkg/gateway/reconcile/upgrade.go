@@ -0,0 +1,459 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/gateway"
+	"github.com/caapim/layer7-operator/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GatewayUpgrade orchestrates changes to the Gateway image between major/minor versions.
+// After the preflight checks pass the Service is pinned to the current Gateway pods and an upgrade Deployment with the new
+// image is created. Its pods are Gateway pods, so repository references and external entities are applied to them by the
+// other reconcile operations. Once they are ready and configured the Service is moved to them and the Gateway Deployment
+// is rolled out, then the Service is restored and the upgrade Deployment is removed.
+func GatewayUpgrade(ctx context.Context, params Params) error {
+	gw := params.Instance
+
+	// blueGreen Gateways roll out version changes to the idle colour
+	if gateway.BlueGreenEnabled(gw) {
+		return nil
+	}
+
+	currentDeployment, err := getGatewayDeployment(ctx, params)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	currentImage := gatewayImage(currentDeployment)
+
+	status := *gw.Status.GatewayUpgrade.DeepCopy()
+	now := time.Now().Format(time.RFC3339)
+
+	if !GatewayUpgradeInProgress(gw) {
+		if !gw.Spec.App.Upgrade.Enabled || gw.Spec.App.Upgrade.Abort || !gatewayVersionChanged(currentImage, gw.Spec.App.Image) {
+			return nil
+		}
+		status = securityv1.GatewayUpgradeStatus{
+			Phase:      securityv1.GatewayUpgradePhasePreflight,
+			FromImage:  currentImage,
+			ToImage:    gw.Spec.App.Image,
+			Deployment: gateway.UpgradeDeploymentName(gw),
+			StartedAt:  now,
+		}
+		params.Recorder.Eventf(gw, "Normal", "UpgradeStarted", "%s in namespace %s is upgrading from %s to %s", gw.Name, gw.Namespace, status.FromImage, status.ToImage)
+	}
+
+	if status.Phase == securityv1.GatewayUpgradePhasePreflight || status.Phase == securityv1.GatewayUpgradePhaseProvisioning {
+		reason := ""
+		switch {
+		case gw.Spec.App.Upgrade.Abort:
+			reason = "upgrade aborted"
+		case !gw.Spec.App.Upgrade.Enabled:
+			reason = "upgrade disabled"
+		case gw.Spec.App.Image != status.ToImage:
+			reason = "image changed to " + gw.Spec.App.Image
+		}
+		if reason != "" {
+			return abortGatewayUpgrade(ctx, params, status, reason)
+		}
+	}
+
+	primaryPods, upgradePods, err := getUpgradePods(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	switch status.Phase {
+	case securityv1.GatewayUpgradePhasePreflight:
+		revision := gatewayRevision(currentDeployment, primaryPods)
+		status.PreflightChecks = upgradePreflightChecks(ctx, params, status, revision)
+		status.Reason = ""
+		for _, check := range status.PreflightChecks {
+			if !check.Passed {
+				status.Reason = "preflight checks failed"
+			}
+		}
+		if status.Reason != "" {
+			return updateGatewayUpgradeStatus(ctx, params, status)
+		}
+
+		// traffic stays on the current Gateway pods while the upgrade Deployment starts
+		status.Phase = securityv1.GatewayUpgradePhaseProvisioning
+		status.Revision = revision
+		if err := updateGatewayUpgradeStatus(ctx, params, status); err != nil {
+			return err
+		}
+		if err := reconcileServices(ctx, params, []*corev1.Service{gateway.NewService(gw)}); err != nil {
+			return fmt.Errorf("failed to pin service: %w", err)
+		}
+		return createUpgradeDeployment(ctx, params, currentDeployment)
+	case securityv1.GatewayUpgradePhaseProvisioning:
+		upgradeDeployment := &appsv1.Deployment{}
+		err := params.Client.Get(ctx, types.NamespacedName{Name: status.Deployment, Namespace: gw.Namespace}, upgradeDeployment)
+		if k8serrors.IsNotFound(err) {
+			return createUpgradeDeployment(ctx, params, currentDeployment)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !deploymentRolledOut(upgradeDeployment):
+			status.Reason = "waiting for the upgrade deployment to be ready"
+		case !podsConfigured(primaryPods, upgradePods):
+			status.Reason = "waiting for repository references and external entities to be applied to the upgrade pods"
+		default:
+			status.Phase = securityv1.GatewayUpgradePhasePromoting
+			status.Revision = ""
+			status.Reason = ""
+			if err := updateGatewayUpgradeStatus(ctx, params, status); err != nil {
+				return err
+			}
+			params.Recorder.Eventf(gw, "Normal", "UpgradePromoting", "%s in namespace %s is served by %s while the gateway deployment is upgraded", gw.Name, gw.Namespace, status.Deployment)
+			if err := reconcileServices(ctx, params, []*corev1.Service{gateway.NewService(gw)}); err != nil {
+				return fmt.Errorf("failed to switch service: %w", err)
+			}
+			return nil
+		}
+	case securityv1.GatewayUpgradePhasePromoting:
+		// the Deployment operation rolls out the Gateway Deployment with the new image
+		status.ToImage = gw.Spec.App.Image
+		switch {
+		case gatewayImage(currentDeployment) != status.ToImage || !deploymentRolledOut(currentDeployment):
+			status.Reason = "waiting for the gateway deployment to be rolled out"
+		case !podsConfigured(upgradePods, primaryPods):
+			status.Reason = "waiting for repository references and external entities to be applied to the gateway pods"
+		default:
+			status.Phase = securityv1.GatewayUpgradePhaseCompleted
+			status.Reason = ""
+			status.CompletedAt = now
+			if err := updateGatewayUpgradeStatus(ctx, params, status); err != nil {
+				return err
+			}
+			if err := reconcileServices(ctx, params, []*corev1.Service{gateway.NewService(gw)}); err != nil {
+				return fmt.Errorf("failed to restore service: %w", err)
+			}
+			params.Recorder.Eventf(gw, "Normal", "UpgradeCompleted", "%s in namespace %s has been upgraded to %s", gw.Name, gw.Namespace, status.ToImage)
+			return removeUpgradeDeployment(ctx, params)
+		}
+		if gw.Spec.App.Upgrade.Abort {
+			status.Reason = "the upgrade is being promoted and can no longer be aborted, " + status.Reason
+		}
+	}
+
+	return updateGatewayUpgradeStatus(ctx, params, status)
+}
+
+// GatewayUpgradeInProgress is true until an upgrade has been completed or aborted
+func GatewayUpgradeInProgress(gw *securityv1.Gateway) bool {
+	status := gw.Status.GatewayUpgrade
+	return status.Phase == securityv1.GatewayUpgradePhasePreflight || status.Phase == securityv1.GatewayUpgradePhaseProvisioning || status.Phase == securityv1.GatewayUpgradePhasePromoting
+}
+
+// upgradeHoldsDeployment keeps the Gateway Deployment on its current image until the upgrade is promoted or while it is aborted
+func upgradeHoldsDeployment(gw *securityv1.Gateway) bool {
+	status := gw.Status.GatewayUpgrade
+	switch status.Phase {
+	case securityv1.GatewayUpgradePhasePreflight, securityv1.GatewayUpgradePhaseProvisioning:
+		return true
+	case securityv1.GatewayUpgradePhaseAborted:
+		return gw.Spec.App.Upgrade.Abort && gw.Spec.App.Image == status.ToImage
+	}
+	return false
+}
+
+func abortGatewayUpgrade(ctx context.Context, params Params, status securityv1.GatewayUpgradeStatus, reason string) error {
+	status.Phase = securityv1.GatewayUpgradePhaseAborted
+	status.Revision = ""
+	status.Reason = reason
+	status.CompletedAt = time.Now().Format(time.RFC3339)
+	if err := updateGatewayUpgradeStatus(ctx, params, status); err != nil {
+		return err
+	}
+	if err := reconcileServices(ctx, params, []*corev1.Service{gateway.NewService(params.Instance)}); err != nil {
+		return fmt.Errorf("failed to restore service: %w", err)
+	}
+	params.Recorder.Eventf(params.Instance, "Warning", "UpgradeAborted", "%s in namespace %s: %s", params.Instance.Name, params.Instance.Namespace, reason)
+	return removeUpgradeDeployment(ctx, params)
+}
+
+func updateGatewayUpgradeStatus(ctx context.Context, params Params, status securityv1.GatewayUpgradeStatus) error {
+	if reflect.DeepEqual(status, params.Instance.Status.GatewayUpgrade) {
+		return nil
+	}
+	params.Instance.Status.GatewayUpgrade = status
+	if err := params.Client.Status().Update(ctx, params.Instance); err != nil {
+		return fmt.Errorf("failed to update gateway upgrade status: %w", err)
+	}
+	params.Log.V(2).Info("updated gateway upgrade status", "phase", status.Phase, "reason", status.Reason, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+	return nil
+}
+
+// createUpgradeDeployment creates the upgrade Deployment from the current Gateway spec with the replicas of the Gateway Deployment
+func createUpgradeDeployment(ctx context.Context, params Params, currentDeployment *appsv1.Deployment) error {
+	desiredDeployment, err := desiredGatewayDeployment(ctx, params)
+	if err != nil {
+		return err
+	}
+	upgradeDeployment := gateway.NewUpgradeDeployment(params.Instance, desiredDeployment)
+	upgradeDeployment.Spec.Replicas = currentDeployment.Spec.Replicas
+
+	if err := params.Client.Create(ctx, upgradeDeployment); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed creating upgrade deployment: %w", err)
+	}
+	params.Log.Info("created upgrade deployment", "deployment", upgradeDeployment.Name, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+	return nil
+}
+
+func removeUpgradeDeployment(ctx context.Context, params Params) error {
+	upgradeDeployment := &appsv1.Deployment{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: gateway.UpgradeDeploymentName(params.Instance), Namespace: params.Instance.Namespace}, upgradeDeployment)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := params.Client.Delete(ctx, upgradeDeployment, client.PropagationPolicy("Background")); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to remove upgrade deployment: %w", err)
+	}
+	params.Log.Info("removed upgrade deployment", "deployment", upgradeDeployment.Name, "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+	return nil
+}
+
+// getUpgradePods splits the Gateway pods into the pods of the Gateway Deployment and the pods of the upgrade Deployment
+func getUpgradePods(ctx context.Context, params Params) ([]corev1.Pod, []corev1.Pod, error) {
+	podList, err := getConfiguredPods(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	primaryPods := []corev1.Pod{}
+	upgradePods := []corev1.Pod{}
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if pod.Labels[gateway.UpgradeLabel] == "true" {
+			upgradePods = append(upgradePods, pod)
+			continue
+		}
+		primaryPods = append(primaryPods, pod)
+	}
+	return primaryPods, upgradePods, nil
+}
+
+// upgradePreflightChecks verifies that the license, OTK and Graphman initContainers support the new Gateway version
+// and that the Gateway Deployment has a single revision that the Service can be pinned to
+func upgradePreflightChecks(ctx context.Context, params Params, status securityv1.GatewayUpgradeStatus, revision string) []securityv1.GatewayUpgradeCheck {
+	gw := params.Instance
+	toVersion, _ := gatewayVersion(status.ToImage)
+	checks := []securityv1.GatewayUpgradeCheck{}
+
+	database := securityv1.GatewayUpgradeCheck{Name: "database", Passed: true}
+	if gw.Spec.App.Management.Database.Enabled {
+		database.Passed = false
+		database.Message = "database backed gateways can not run two gateway versions against the same database"
+	}
+	checks = append(checks, database)
+
+	license := securityv1.GatewayUpgradeCheck{Name: "license", Passed: true}
+	licenseSecret, err := getGatewaySecret(ctx, params, gw.Spec.License.SecretName)
+	if err != nil {
+		license.Passed = false
+		license.Message = "failed to retrieve license: " + err.Error()
+	} else if gatewayLicense, err := util.ParseGatewayLicense(licenseSecret.Data["license.xml"]); err != nil {
+		license.Passed = false
+		license.Message = err.Error()
+	} else if gatewayLicense.MajorVersion != "" && gatewayLicense.MajorVersion != "*" && gatewayLicense.MajorVersion != strings.Split(toVersion, ".")[0] {
+		license.Passed = false
+		license.Message = fmt.Sprintf("license is valid for version %s, upgrade version is %s", gatewayLicense.MajorVersion, toVersion)
+	}
+	checks = append(checks, license)
+
+	otk := securityv1.GatewayUpgradeCheck{Name: "otk", Passed: true}
+	if gw.Spec.App.Otk.Enabled {
+		switch {
+		case !versionedImage(gw.Spec.App.Otk.InitContainerImage):
+			otk.Passed = false
+			otk.Message = "otk initContainerImage must be set to the OTK version for the upgrade version"
+		case gateway.OtkSchemaJobEnabled(gw) && (gw.Status.OtkSchema.Result != OtkSchemaJobSucceeded || gw.Status.OtkSchema.Version != gateway.OtkVersion(gw)):
+			otk.Passed = false
+			otk.Message = "waiting for the otk schema job for " + gateway.OtkVersion(gw)
+		}
+	}
+	checks = append(checks, otk)
+
+	graphman := securityv1.GatewayUpgradeCheck{Name: "graphman", Passed: true}
+	if gw.Spec.App.Management.Graphman.InitContainerImage != "" && !versionedImage(gw.Spec.App.Management.Graphman.InitContainerImage) {
+		graphman.Passed = false
+		graphman.Message = "graphman initContainerImage must be pinned to a version"
+	}
+	checks = append(checks, graphman)
+
+	deployment := securityv1.GatewayUpgradeCheck{Name: "deployment", Passed: true}
+	if revision == "" {
+		deployment.Passed = false
+		deployment.Message = "waiting for the gateway deployment to finish rolling out"
+	}
+	checks = append(checks, deployment)
+
+	return checks
+}
+
+// gatewayRevision returns the pod-template-hash of the Gateway pods if the Gateway Deployment has been rolled out
+func gatewayRevision(dep *appsv1.Deployment, pods []corev1.Pod) string {
+	if !deploymentRolledOut(dep) || len(pods) == 0 {
+		return ""
+	}
+	revision := pods[0].Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	for _, pod := range pods {
+		if pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey] != revision {
+			return ""
+		}
+	}
+	return revision
+}
+
+// deploymentRolledOut is true when all replicas of a Deployment are updated and ready
+func deploymentRolledOut(dep *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if dep.Spec.Replicas != nil {
+		replicas = *dep.Spec.Replicas
+	}
+	return dep.Status.ObservedGeneration >= dep.Generation &&
+		dep.Status.Replicas == replicas &&
+		dep.Status.UpdatedReplicas == replicas &&
+		dep.Status.ReadyReplicas == replicas
+}
+
+// podsConfigured is true when every pod is ready and has the repository references and external entities
+// that have been applied to all of the reference pods. Leader checksums are compared without their suffix
+func podsConfigured(reference []corev1.Pod, pods []corev1.Pod) bool {
+	if len(pods) == 0 {
+		return false
+	}
+	expected := map[string]string{}
+	for i, pod := range reference {
+		applied := map[string]string{}
+		for k, v := range pod.Annotations {
+			if strings.HasPrefix(k, "security.brcmlabs.com/") {
+				applied[k] = strings.TrimSuffix(v, "-leader")
+			}
+		}
+		if i == 0 {
+			expected = applied
+			continue
+		}
+		for k, v := range expected {
+			if applied[k] != v {
+				delete(expected, k)
+			}
+		}
+	}
+
+	for _, pod := range pods {
+		ready := false
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name == "gateway" {
+				ready = containerStatus.Ready
+			}
+		}
+		if !ready {
+			return false
+		}
+		for k, v := range expected {
+			if strings.TrimSuffix(pod.Annotations[k], "-leader") != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// gatewayImage returns the image of the gateway container
+func gatewayImage(dep *appsv1.Deployment) string {
+	for _, container := range dep.Spec.Template.Spec.Containers {
+		if container.Name == "gateway" {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+// gatewayVersionChanged is true if both images have a major.minor version tag and the versions are different
+func gatewayVersionChanged(fromImage string, toImage string) bool {
+	fromVersion, ok := gatewayVersion(fromImage)
+	if !ok {
+		return false
+	}
+	toVersion, ok := gatewayVersion(toImage)
+	if !ok {
+		return false
+	}
+	return fromVersion != toVersion
+}
+
+// gatewayVersion returns the major.minor version of a Gateway image tag i.e. 11.1 for 11.1.2 or 11.1.00_CR1
+func gatewayVersion(image string) (string, bool) {
+	parts := strings.Split(imageTag(image), ".")
+	if len(parts) < 2 {
+		return "", false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", false
+	}
+	minor := parts[1]
+	if i := strings.IndexFunc(minor, func(r rune) bool { return r < '0' || r > '9' }); i != -1 {
+		minor = minor[:i]
+	}
+	if minor == "" {
+		return "", false
+	}
+	return fmt.Sprintf("%d.%s", major, minor), true
+}
+
+// versionedImage is true if an image is pinned to a tag other than latest
+func versionedImage(image string) bool {
+	tag := imageTag(image)
+	return tag != "" && tag != "latest"
+}
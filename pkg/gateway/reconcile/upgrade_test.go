@@ -0,0 +1,234 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/gateway"
+	"github.com/caapim/layer7-operator/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const testUpgradeLicense = `<license Id="1" xmlns="http://l7tech.com/license">
+    <product name="Layer 7 SecureSpan Suite">
+        <version major="11" minor="*"/>
+    </product>
+</license>`
+
+func testGatewayPod(name string, hash string, upgrade bool, annotations map[string]string) *corev1.Pod {
+	labels := util.DefaultLabels("ssg", map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: hash})
+	if upgrade {
+		labels[gateway.UpgradeLabel] = "true"
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels, Annotations: annotations},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{Name: "gateway", Ready: true}}},
+	}
+}
+
+func rollOut(t *testing.T, k8sClient client.Client, name string, image string) {
+	dep := &appsv1.Deployment{}
+	if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, dep); err != nil {
+		t.Fatal(err)
+	}
+	if image != "" {
+		dep.Spec.Template.Spec.Containers[0].Image = image
+		if err := k8sClient.Update(context.Background(), dep); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dep.Status = appsv1.DeploymentStatus{ObservedGeneration: dep.Generation, Replicas: *dep.Spec.Replicas, UpdatedReplicas: *dep.Spec.Replicas, ReadyReplicas: *dep.Spec.Replicas}
+	if err := k8sClient.Status().Update(context.Background(), dep); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGatewayUpgrade(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	gw := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default", UID: "ssg-uid"}}
+	gw.Spec.License.SecretName = "ssg-license"
+	gw.Spec.App.Image = "caapim/gateway:11.0.00_CR2"
+	gw.Spec.App.Replicas = 1
+	gw.Spec.App.Upgrade.Enabled = true
+
+	replicas := int32(1)
+	primary := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "gateway", Image: "caapim/gateway:11.0.00_CR2"}}}},
+		},
+	}
+	applied := map[string]string{"security.brcmlabs.com/policies-dynamic": "c1-leader"}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		gw,
+		primary,
+		gateway.NewService(gw),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ssg-license", Namespace: "default"}, Data: map[string][]byte{"license.xml": []byte(testUpgradeLicense)}},
+		testGatewayPod("ssg-0", "rev1", false, applied),
+	).WithStatusSubresource(&securityv1.Gateway{}, &appsv1.Deployment{}).Build()
+	rollOut(t, k8sClient, "ssg", "")
+
+	params := Params{
+		Client:   k8sClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: gw,
+	}
+	updateSpec := func(t *testing.T, update func(gw *securityv1.Gateway)) {
+		update(gw)
+		if err := k8sClient.Update(context.Background(), gw); err != nil {
+			t.Fatal(err)
+		}
+	}
+	serviceSelector := func(t *testing.T) map[string]string {
+		svc := &corev1.Service{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg", Namespace: "default"}, svc); err != nil {
+			t.Fatal(err)
+		}
+		return svc.Spec.Selector
+	}
+
+	t.Run("should roll patch versions", func(t *testing.T) {
+		updateSpec(t, func(gw *securityv1.Gateway) { gw.Spec.App.Image = "caapim/gateway:11.0.00_CR3" })
+		if err := GatewayUpgrade(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if GatewayUpgradeInProgress(gw) || upgradeHoldsDeployment(gw) {
+			t.Errorf("expected a rolling update, actual %+v", gw.Status.GatewayUpgrade)
+		}
+	})
+
+	t.Run("should wait for preflight checks", func(t *testing.T) {
+		updateSpec(t, func(gw *securityv1.Gateway) { gw.Spec.App.Image = "caapim/gateway:12.0.00" })
+		if err := GatewayUpgrade(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status := gw.Status.GatewayUpgrade
+		if status.Phase != securityv1.GatewayUpgradePhasePreflight || status.Reason != "preflight checks failed" || !upgradeHoldsDeployment(gw) {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		for _, check := range status.PreflightChecks {
+			if check.Name == "license" && check.Passed {
+				t.Errorf("expected the license check to fail")
+			}
+		}
+	})
+
+	t.Run("should abort an upgrade", func(t *testing.T) {
+		updateSpec(t, func(gw *securityv1.Gateway) { gw.Spec.App.Upgrade.Abort = true })
+		if err := GatewayUpgrade(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if gw.Status.GatewayUpgrade.Phase != securityv1.GatewayUpgradePhaseAborted || !upgradeHoldsDeployment(gw) {
+			t.Fatalf("expected the deployment to keep the previous image, actual %+v", gw.Status.GatewayUpgrade)
+		}
+		updateSpec(t, func(gw *securityv1.Gateway) { gw.Spec.App.Image = "caapim/gateway:11.0.00_CR2" })
+		if upgradeHoldsDeployment(gw) {
+			t.Errorf("expected the deployment to be released when the image is reverted")
+		}
+		updateSpec(t, func(gw *securityv1.Gateway) { gw.Spec.App.Upgrade.Abort = false })
+	})
+
+	t.Run("should pin the service and provision the upgrade deployment", func(t *testing.T) {
+		updateSpec(t, func(gw *securityv1.Gateway) { gw.Spec.App.Image = "caapim/gateway:11.1.00" })
+		if err := GatewayUpgrade(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status := gw.Status.GatewayUpgrade
+		if status.Phase != securityv1.GatewayUpgradePhaseProvisioning || status.FromImage != "caapim/gateway:11.0.00_CR2" || status.Revision != "rev1" {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		if serviceSelector(t)[appsv1.DefaultDeploymentUniqueLabelKey] != "rev1" {
+			t.Errorf("expected the service to be pinned to the current pods, actual %v", serviceSelector(t))
+		}
+		upgrade := &appsv1.Deployment{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg-upgrade", Namespace: "default"}, upgrade); err != nil {
+			t.Fatal(err)
+		}
+		if gatewayImage(upgrade) != "caapim/gateway:11.1.00" || upgrade.Spec.Template.Labels[gateway.UpgradeLabel] != "true" || upgrade.Spec.Selector.MatchLabels[gateway.UpgradeLabel] != "true" {
+			t.Errorf("unexpected upgrade deployment %+v", upgrade.Spec)
+		}
+	})
+
+	t.Run("should switch the service once the upgrade pods are configured", func(t *testing.T) {
+		rollOut(t, k8sClient, "ssg-upgrade", "")
+		if err := k8sClient.Create(context.Background(), testGatewayPod("ssg-upgrade-0", "rev2", true, nil)); err != nil {
+			t.Fatal(err)
+		}
+		if err := GatewayUpgrade(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if gw.Status.GatewayUpgrade.Phase != securityv1.GatewayUpgradePhaseProvisioning {
+			t.Fatalf("expected to wait for the upgrade pods to be configured, actual %+v", gw.Status.GatewayUpgrade)
+		}
+
+		pod := &corev1.Pod{}
+		_ = k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg-upgrade-0", Namespace: "default"}, pod)
+		pod.Annotations = map[string]string{"security.brcmlabs.com/policies-dynamic": "c1"}
+		if err := k8sClient.Update(context.Background(), pod); err != nil {
+			t.Fatal(err)
+		}
+		if err := GatewayUpgrade(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if gw.Status.GatewayUpgrade.Phase != securityv1.GatewayUpgradePhasePromoting || upgradeHoldsDeployment(gw) {
+			t.Fatalf("unexpected status %+v", gw.Status.GatewayUpgrade)
+		}
+		if serviceSelector(t)[gateway.UpgradeLabel] != "true" {
+			t.Errorf("expected the service to select the upgrade pods, actual %v", serviceSelector(t))
+		}
+	})
+
+	t.Run("should complete once the gateway deployment is rolled out", func(t *testing.T) {
+		rollOut(t, k8sClient, "ssg", "caapim/gateway:11.1.00")
+		if err := GatewayUpgrade(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		status := gw.Status.GatewayUpgrade
+		if status.Phase != securityv1.GatewayUpgradePhaseCompleted || status.CompletedAt == "" {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		if len(serviceSelector(t)) != len(util.DefaultLabels("ssg", nil)) {
+			t.Errorf("expected the service selector to be restored, actual %v", serviceSelector(t))
+		}
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg-upgrade", Namespace: "default"}, &appsv1.Deployment{})
+		if !k8serrors.IsNotFound(err) {
+			t.Errorf("expected the upgrade deployment to be removed, actual %v", err)
+		}
+	})
+}
+
+func TestGatewayVersion(t *testing.T) {
+	versions := map[string]string{
+		"caapim/gateway:11.1.00":                   "11.1",
+		"caapim/gateway:11.0.00_CR2":               "11.0",
+		"registry.example.com:5000/gateway:10.1.2": "10.1",
+		"caapim/gateway:latest":                    "",
+		"caapim/gateway":                           "",
+	}
+	for image, expected := range versions {
+		if actual, _ := gatewayVersion(image); actual != expected {
+			t.Errorf("%s: expected %q, actual %q", image, expected, actual)
+		}
+	}
+	if gatewayVersionChanged("caapim/gateway:11.1.00", "caapim/gateway:11.1.01") || !gatewayVersionChanged("caapim/gateway:10.1.00", "caapim/gateway:11.1.00") {
+		t.Errorf("expected only major/minor changes to be upgrades")
+	}
+}
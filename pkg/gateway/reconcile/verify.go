@@ -0,0 +1,141 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	RepositoryVerified     = "VERIFIED"
+	RepositoryVerifyFailed = "VERIFY_FAILED"
+	RepositoryRolledBack   = "ROLLED_BACK"
+)
+
+// repositoryVerifyTimeout bounds the time that all verify checks of a commit can take
+const repositoryVerifyTimeout = 2 * time.Minute
+
+// repositoryVerification collects the verify results of a repository reference while its bundle is applied
+type repositoryVerification struct {
+	conditions []securityv1.RepositoryCondition
+}
+
+// verifyRepositoryReference runs the verify checks of a repository reference against the pods that it was applied to
+// in parallel within repositoryVerifyTimeout. Pods that fail are rolled back to the previous commit if rollbackOnVerifyFailure
+// is set, otherwise they keep the new commit and are not verified again until the commit changes
+func verifyRepositoryReference(ctx context.Context, params Params, gwUpdReq *GatewayUpdateRequest, podUpdates []podUpdate) {
+	repoRef := gwUpdReq.repositoryReference
+	pods := []corev1.Pod{}
+	for _, u := range podUpdates {
+		pods = append(pods, gwUpdReq.podList.Items[u.index])
+	}
+	verifyCtx, cancel := context.WithTimeout(ctx, repositoryVerifyTimeout)
+	results := runSmokeTests(verifyCtx, repoRef.Verify, pods, maxConcurrentPodUpdates(gwUpdReq.gateway))
+	cancel()
+
+	failures := map[string][]string{}
+	for _, result := range results {
+		if !result.Passed {
+			failures[result.Pod] = append(failures[result.Pod], result.Name+": "+result.Message)
+		}
+	}
+
+	previousCommit := ""
+	for _, repoStatus := range gwUpdReq.gateway.Status.RepositoryStatus {
+		if repoStatus.Name == repoRef.Name {
+			previousCommit = repoStatus.Commit
+		}
+	}
+
+	rolledBack := map[string]bool{}
+	rollbackError := ""
+	if repoRef.RollbackOnVerifyFailure && len(failures) > 0 {
+		failedUpdates := []podUpdate{}
+		for _, u := range podUpdates {
+			if _, failed := failures[gwUpdReq.podList.Items[u.index].Name]; failed {
+				failedUpdates = append(failedUpdates, u)
+			}
+		}
+		if err := rollbackRepositoryReference(ctx, params, gwUpdReq, previousCommit, failedUpdates); err != nil {
+			params.Log.Info("failed to roll back repository reference", "repository", repoRef.Name, "commit", previousCommit, "name", gwUpdReq.gateway.Name, "namespace", gwUpdReq.gateway.Namespace, "error", err.Error())
+			rollbackError = err.Error()
+		} else {
+			for pod := range failures {
+				rolledBack[pod] = true
+			}
+		}
+	}
+
+	_ = captureRepositoryVerifyMetrics(ctx, params, repoRef.Name, results, len(rolledBack))
+
+	if gwUpdReq.verification == nil {
+		return
+	}
+	now := time.Now().Format(time.RFC3339)
+	for _, pod := range pods {
+		condition := securityv1.RepositoryCondition{Time: now, Status: RepositoryVerified, Pod: pod.Name}
+		if podFailures, failed := failures[pod.Name]; failed {
+			condition.Status = RepositoryVerifyFailed
+			condition.Reason = strings.Join(podFailures, "; ")
+			switch {
+			case rolledBack[pod.Name]:
+				condition.Status = RepositoryRolledBack
+				condition.Reason = condition.Reason + ", rolled back to " + previousCommit
+			case rollbackError != "":
+				condition.Reason = condition.Reason + ", rollback: " + rollbackError
+			case !repoRef.RollbackOnVerifyFailure:
+				condition.Reason = condition.Reason + ", not verified again until the commit changes"
+			}
+		}
+		gwUpdReq.verification.conditions = append(gwUpdReq.verification.conditions, condition)
+	}
+}
+
+// rollbackRepositoryReference reapplies the cached bundle of the previous commit to pods, their annotations keep the
+// new commit so that it is not reapplied until the repository changes
+func rollbackRepositoryReference(ctx context.Context, params Params, gwUpdReq *GatewayUpdateRequest, previousCommit string, podUpdates []podUpdate) error {
+	if previousCommit == "" || previousCommit == gwUpdReq.checksum {
+		return fmt.Errorf("there is no previous commit to roll back to")
+	}
+	bundle, err := buildBundleFromCache(gwUpdReq.repository, gwUpdReq.repositoryReference, "/tmp/repo-cache/"+gwUpdReq.repository.Name, previousCommit+".json")
+	if err != nil {
+		return err
+	}
+
+	rollbackReq := *gwUpdReq
+	rollbackReq.bundle = bundle
+	rollbackReq.cacheEntry = gwUpdReq.repositoryReference.Name + "-" + previousCommit + "-rollback"
+	return util.RunConcurrently(maxConcurrentPodUpdates(rollbackReq.gateway), len(podUpdates), func(u int) error {
+		return applyToGatewayPod(ctx, params, &rollbackReq, podUpdates[u])
+	})
+}
@@ -0,0 +1,155 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestVerifyRepositoryReference(t *testing.T) {
+	// the gateway serves the value of the last cluster property that was applied
+	var mu sync.Mutex
+	applied := ""
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/graphman" {
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			if lookup, ok := graphmanLookupResponse(body); ok {
+				_, _ = w.Write(lookup)
+				return
+			}
+			applied = string(body)
+			_, _ = w.Write([]byte(`{"data":{}}`))
+			return
+		}
+		if !strings.Contains(applied, "healthy") {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_, _ = w.Write([]byte(applied))
+	}))
+	defer server.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "https://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	graphmanPort, _ := strconv.Atoi(port)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	gateway := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "verify", Namespace: "default"}}
+	gateway.Status.RepositoryStatus = []securityv1.GatewayRepositoryStatus{{Name: "verify-repo", Commit: "commit1"}}
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "verify-0", Namespace: "default"},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			PodIP:             host,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "gateway", Ready: true}},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod.DeepCopy(), gateway).Build()
+	params := Params{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: gateway,
+	}
+
+	// the bundle of the previous commit is cached by the repository controller
+	cachePath := "/tmp/repo-cache/verify-repo-test"
+	if err := os.MkdirAll(cachePath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(cachePath) })
+	bundleMap, _ := json.Marshal(map[string][]byte{"status.json": []byte(`{"clusterProperties":[{"name":"status","value":"healthy"}]}`)})
+	if err := os.WriteFile(cachePath+"/commit1.json", bundleMap, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repoRef := securityv1.RepositoryReference{
+		Name:        "verify-repo",
+		Enabled:     true,
+		Type:        securityv1.RepositoryReferenceTypeDynamic,
+		Directories: []string{"/"},
+		Verify:      []securityv1.SmokeTest{{Name: "status", Path: "/status", Port: int32(graphmanPort), ExpectedBody: "healthy"}},
+	}
+	newRequest := func(checksum string, value string) *GatewayUpdateRequest {
+		return &GatewayUpdateRequest{
+			checksum:            checksum,
+			patchAnnotation:     "security.brcmlabs.com/verify-repo-dynamic",
+			graphmanPort:        graphmanPort,
+			bundle:              []byte(`{"clusterProperties":[{"name":"status","value":"` + value + `"}]}`),
+			bundleName:          "verify-repo",
+			bundleType:          BundleTypeRepository,
+			username:            "admin",
+			password:            "password",
+			cacheEntry:          "verify-repo-" + checksum,
+			repositoryReference: &repoRef,
+			repository:          &securityv1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "verify-repo-test", Namespace: "default"}},
+			gateway:             gateway,
+			podList:             &corev1.PodList{Items: []corev1.Pod{*pod.DeepCopy()}},
+			verification:        &repositoryVerification{},
+		}
+	}
+
+	t.Run("should record the verify results of each pod", func(t *testing.T) {
+		gwUpdReq := newRequest("commit1", "healthy")
+		if err := updateGatewayPods(context.Background(), params, gwUpdReq); err != nil {
+			t.Fatal(err)
+		}
+		conditions := gwUpdReq.verification.conditions
+		if len(conditions) != 1 || conditions[0].Pod != "verify-0" || conditions[0].Status != RepositoryVerified {
+			t.Fatalf("unexpected conditions %+v", conditions)
+		}
+	})
+
+	t.Run("should report failed checks", func(t *testing.T) {
+		gwUpdReq := newRequest("commit2", "broken")
+		if err := updateGatewayPods(context.Background(), params, gwUpdReq); err != nil {
+			t.Fatal(err)
+		}
+		conditions := gwUpdReq.verification.conditions
+		if len(conditions) != 1 || conditions[0].Status != RepositoryVerifyFailed || !strings.Contains(conditions[0].Reason, "expected status 200, got 500") {
+			t.Fatalf("unexpected conditions %+v", conditions)
+		}
+		if !strings.Contains(conditions[0].Reason, "not verified again until the commit changes") {
+			t.Errorf("expected the reason to say that the commit is not verified again, actual %s", conditions[0].Reason)
+		}
+	})
+
+	t.Run("should roll back to the previous commit", func(t *testing.T) {
+		repoRef.RollbackOnVerifyFailure = true
+		gwUpdReq := newRequest("commit3", "broken")
+		if err := updateGatewayPods(context.Background(), params, gwUpdReq); err != nil {
+			t.Fatal(err)
+		}
+		conditions := gwUpdReq.verification.conditions
+		if len(conditions) != 1 || conditions[0].Status != RepositoryRolledBack || !strings.Contains(conditions[0].Reason, "rolled back to commit1") {
+			t.Fatalf("unexpected conditions %+v", conditions)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !strings.Contains(applied, "healthy") {
+			t.Errorf("expected the bundle of commit1 to be reapplied, actual %s", applied)
+		}
+	})
+}
@@ -88,6 +88,11 @@ func NewSecret(gw *securityv1.Gateway, name string) (*corev1.Secret, error) {
 			return nil, err
 		}
 
+		if gw.Spec.App.Redis.Default.Ssl.ClientCertSecretName != "" {
+			defaultRedisConfig.Ssl.ClientCert = "redis-client.crt"
+			defaultRedisConfig.Ssl.ClientKey = "redis-client.key"
+		}
+
 		defaultRedisConfig.KeyPrefixGroupName = gw.Spec.App.Redis.Default.GroupName
 
 		if defaultRedisConfig.KeyPrefixGroupName == "" {
@@ -153,6 +158,11 @@ func NewSecret(gw *securityv1.Gateway, name string) (*corev1.Secret, error) {
 						return nil, err
 					}
 
+					if rc.Ssl.ClientCertSecretName != "" {
+						redisConfig.Ssl.ClientCert = rc.Name + "-redis-client.crt"
+						redisConfig.Ssl.ClientKey = rc.Name + "-redis-client.key"
+					}
+
 					redisConfig.KeyPrefixGroupName = rc.GroupName
 					if redisConfig.KeyPrefixGroupName == "" {
 						redisConfig.KeyPrefixGroupName = redisGroupName
@@ -196,8 +206,17 @@ func NewSecret(gw *securityv1.Gateway, name string) (*corev1.Secret, error) {
 						if rc.Standalone.Host == "" || rc.Standalone.Port == 0 {
 							return nil, fmt.Errorf("redis %s standalone requires host and port to be set", rc.Name)
 						}
+					case securityv1.RedisTypeCluster:
+						if len(rc.Cluster.Nodes) == 0 {
+							return nil, fmt.Errorf("redis %s cluster requires an array of seed nodes that contain host and port", rc.Name)
+						}
+						for i, node := range rc.Cluster.Nodes {
+							if node.Host == "" || node.Port == 0 {
+								return nil, fmt.Errorf("redis %s cluster node %d requires host and port to be set", rc.Name, i)
+							}
+						}
 					default:
-						return nil, fmt.Errorf("redis %s requires a type, valid options are sentinel or standalone", rc.Name)
+						return nil, fmt.Errorf("redis %s requires a type, valid options are sentinel, standalone or cluster", rc.Name)
 					}
 
 					if len(rc.Sentinel.Nodes) > 0 {
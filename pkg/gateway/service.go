@@ -66,7 +66,7 @@ func NewService(gw *securityv1.Gateway) *corev1.Service {
 			Kind:       "Service",
 		},
 		Spec: corev1.ServiceSpec{
-			Selector: util.DefaultLabels(gw.Name, nil),
+			Selector: ServiceSelector(gw),
 			Ports:    ports,
 			Type:     gw.Spec.App.Service.Type,
 		},
@@ -155,6 +155,7 @@ func NewManagementService(gw *securityv1.Gateway) *corev1.Service {
 	}
 
 	ls := util.DefaultLabels(gw.Name, gw.Spec.App.Labels)
+	// upgrade pods are never elected so the management Service does not select them
 	mls := map[string]string{"management-access": "leader"}
 
 	service := &corev1.Service{
@@ -0,0 +1,86 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package gateway
+
+import (
+	"maps"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// UpgradeLabel identifies the pods of the upgrade Deployment, they are Gateway pods that are configured
+// with the rest of the Gateway but only receive traffic once the upgrade is promoted
+const UpgradeLabel = "security.brcmlabs.com/upgrade"
+
+// UpgradeDeploymentName is the temporary Deployment that runs the new Gateway image during an upgrade
+func UpgradeDeploymentName(gw *securityv1.Gateway) string {
+	return gw.Name + "-upgrade"
+}
+
+// UpgradePodLabels selects the pods of the upgrade Deployment
+func UpgradePodLabels(gw *securityv1.Gateway) map[string]string {
+	return util.DefaultLabels(gw.Name, map[string]string{UpgradeLabel: "true"})
+}
+
+// ServiceSelector selects the Gateway pods that receive traffic. While an upgrade is provisioned the Service is pinned
+// to the current revision of the Gateway pods and it selects the upgrade pods while the Gateway Deployment is promoted.
+// blueGreen Gateways select the active colour or the current revision of the Gateway pods until the first switch
+func ServiceSelector(gw *securityv1.Gateway) map[string]string {
+	selector := util.DefaultLabels(gw.Name, nil)
+	if BlueGreenEnabled(gw) {
+		switch {
+		case gw.Status.BlueGreen.Active != "":
+			selector[ColorLabel] = string(gw.Status.BlueGreen.Active)
+			return selector
+		case gw.Status.BlueGreen.Revision != "":
+			selector[appsv1.DefaultDeploymentUniqueLabelKey] = gw.Status.BlueGreen.Revision
+			return selector
+		}
+	}
+	switch gw.Status.GatewayUpgrade.Phase {
+	case securityv1.GatewayUpgradePhaseProvisioning:
+		if gw.Status.GatewayUpgrade.Revision != "" {
+			selector[appsv1.DefaultDeploymentUniqueLabelKey] = gw.Status.GatewayUpgrade.Revision
+		}
+	case securityv1.GatewayUpgradePhasePromoting:
+		selector[UpgradeLabel] = "true"
+	}
+	return selector
+}
+
+// NewUpgradeDeployment converts a Gateway Deployment into the upgrade Deployment
+func NewUpgradeDeployment(gw *securityv1.Gateway, dep *appsv1.Deployment) *appsv1.Deployment {
+	upgrade := dep.DeepCopy()
+	upgrade.Name = UpgradeDeploymentName(gw)
+	upgrade.ObjectMeta.Labels = maps.Clone(dep.ObjectMeta.Labels)
+	upgrade.ObjectMeta.Labels[UpgradeLabel] = "true"
+	upgrade.Spec.Selector.MatchLabels = UpgradePodLabels(gw)
+	upgrade.Spec.Template.ObjectMeta.Labels = maps.Clone(dep.Spec.Template.ObjectMeta.Labels)
+	upgrade.Spec.Template.ObjectMeta.Labels[UpgradeLabel] = "true"
+	return upgrade
+}
@@ -0,0 +1,110 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/caapim/layer7-operator/pkg/util"
+)
+
+// otkClientStorePath is the OTK client persistence service that the OAuth Manager uses to manage clients
+// it is published on the OTK port of the Gateway
+const otkClientStorePath = "/oauth/clientstore"
+
+// otkClientStore calls the OTK client persistence service of a single Gateway pod
+type otkClientStore struct {
+	baseUrl  string
+	username string
+	password string
+}
+
+// otkClient is an OTK client with a single client key
+type otkClient struct {
+	ClientIdent  string         `json:"client_ident"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description,omitempty"`
+	Type         string         `json:"type"`
+	RegisteredBy string         `json:"registered_by"`
+	ClientKeys   []otkClientKey `json:"client_keys"`
+}
+
+type otkClientKey struct {
+	ClientKey   string `json:"client_key"`
+	Secret      string `json:"secret,omitempty"`
+	Scope       string `json:"scope"`
+	Callback    string `json:"callback"`
+	GrantTypes  string `json:"grant_types"`
+	Environment string `json:"environment"`
+	Status      string `json:"status"`
+}
+
+// newOtkClientStore derives the client store url from the Graphman endpoint of a Gateway pod
+// Graphman is published on the management port, the client store is called on the OTK port of the same pod
+func newOtkClientStore(username string, password string, graphmanEndpoint string, otkPort int) (*otkClientStore, error) {
+	host, _, err := net.SplitHostPort(strings.TrimSuffix(graphmanEndpoint, "/graphman"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse graphman endpoint %s: %w", graphmanEndpoint, err)
+	}
+	return &otkClientStore{
+		baseUrl:  "https://" + net.JoinHostPort(host, strconv.Itoa(otkPort)) + otkClientStorePath,
+		username: username,
+		password: password,
+	}, nil
+}
+
+func (s *otkClientStore) call(operation string, data []byte) error {
+	_, err := util.RestCall("POST", s.baseUrl+"/"+operation, true, map[string]string{}, "application/json", data, s.username, s.password)
+	return err
+}
+
+// store creates the client or replaces the client and its client key if it exists
+func (s *otkClientStore) store(client otkClient) error {
+	data, err := json.Marshal(client)
+	if err != nil {
+		return err
+	}
+	if err := s.call("store", data); err != nil {
+		return fmt.Errorf("failed to store otk client %s: %w", client.ClientIdent, err)
+	}
+	return nil
+}
+
+// delete removes the client and each of its client keys
+func (s *otkClientStore) delete(clientIdent string) error {
+	data, err := json.Marshal(map[string]string{"client_ident": clientIdent})
+	if err != nil {
+		return err
+	}
+	if err := s.call("delete", data); err != nil {
+		return fmt.Errorf("failed to delete otk client %s: %w", clientIdent, err)
+	}
+	return nil
+}
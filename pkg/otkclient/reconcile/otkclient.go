@@ -0,0 +1,278 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package reconcile
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	gatewayreconcile "github.com/caapim/layer7-operator/pkg/gateway/reconcile"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OtkClientSecretLabel is added to client secrets so that rotated secrets are reregistered
+const OtkClientSecretLabel = "security.brcmlabs.com/otk-client-secret"
+
+// Register stores the client with the referenced Gateway when the client configuration or client secret changes
+func Register(ctx context.Context, params Params) error {
+	otkClient := params.Instance
+	status := otkClient.Status
+
+	desiredClient, secretVersion, err := newOtkClient(ctx, params)
+	if err != nil {
+		status.Registered = false
+		status.Reason = err.Error()
+		return updateStatus(ctx, params, status, err)
+	}
+
+	checksum, err := clientChecksum(desiredClient, secretVersion)
+	if err != nil {
+		return err
+	}
+	if status.Registered && status.Checksum == checksum && status.Gateway == otkClient.Spec.GatewayReference {
+		return nil
+	}
+
+	// clients that move to another Gateway are removed from the previous Gateway first
+	// so that Gateways which share an OTK database keep the new registration
+	if status.Gateway != "" && status.Gateway != otkClient.Spec.GatewayReference {
+		if err := Remove(ctx, params); err != nil {
+			params.Log.Info("failed to remove otk client from previous gateway", "name", otkClient.Name, "gateway", status.Gateway, "namespace", otkClient.Namespace, "message", err.Error())
+		}
+	}
+
+	store, pod, err := clientStore(ctx, params)
+	if err == nil {
+		err = store.store(desiredClient)
+	}
+	if err != nil {
+		params.Log.Info("failed to register otk client", "name", otkClient.Name, "gateway", otkClient.Spec.GatewayReference, "namespace", otkClient.Namespace, "message", err.Error())
+		status.Registered = false
+		status.Reason = err.Error()
+		return updateStatus(ctx, params, status, err)
+	}
+
+	params.Log.Info("registered otk client", "name", otkClient.Name, "gateway", otkClient.Spec.GatewayReference, "pod", pod, "namespace", otkClient.Namespace)
+
+	status.Registered = true
+	status.Gateway = otkClient.Spec.GatewayReference
+	status.ClientId = otkClient.Spec.ClientId
+	status.Reason = ""
+	status.Pod = pod
+	status.Checksum = checksum
+	status.LastUpdated = time.Now().Format(time.RFC3339)
+	return updateStatus(ctx, params, status, nil)
+}
+
+// Remove deletes the client from the Gateway that it was registered with
+// clients of Gateways that no longer exist do not need to be removed
+func Remove(ctx context.Context, params Params) error {
+	otkClient := params.Instance
+	if otkClient.Status.Gateway == "" {
+		return nil
+	}
+
+	gatewayParams := params
+	gatewayParams.Instance = otkClient.DeepCopy()
+	gatewayParams.Instance.Spec.GatewayReference = otkClient.Status.Gateway
+	store, _, err := clientStore(ctx, gatewayParams)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	err = store.delete(clientIdent(otkClient))
+	if err != nil {
+		return err
+	}
+	params.Log.Info("removed otk client", "name", otkClient.Name, "gateway", otkClient.Status.Gateway, "namespace", otkClient.Namespace)
+	return nil
+}
+
+// newOtkClient converts the OtkClient spec and client secret into the OTK client representation
+// the resourceVersion of the client secret is returned so that rotations can be detected without hashing the secret
+func newOtkClient(ctx context.Context, params Params) (otkClient, string, error) {
+	spec := params.Instance.Spec
+	if spec.ClientId == "" {
+		return otkClient{}, "", fmt.Errorf("a clientId is required")
+	}
+
+	clientType := spec.Type
+	if clientType == "" {
+		clientType = securityv1alpha1.OtkClientTypeConfidential
+	}
+
+	clientSecret := ""
+	secretVersion := ""
+	if clientType == securityv1alpha1.OtkClientTypeConfidential {
+		if spec.ClientSecret.SecretName == "" {
+			return otkClient{}, "", fmt.Errorf("a client secret is required for confidential clients")
+		}
+		key := spec.ClientSecret.Key
+		if key == "" {
+			key = "clientSecret"
+		}
+		secret := &corev1.Secret{}
+		err := params.Client.Get(ctx, types.NamespacedName{Name: spec.ClientSecret.SecretName, Namespace: params.Instance.Namespace}, secret)
+		if err != nil {
+			return otkClient{}, "", fmt.Errorf("failed to retrieve client secret %s: %w", spec.ClientSecret.SecretName, err)
+		}
+		clientSecret = string(secret.Data[key])
+		if clientSecret == "" {
+			return otkClient{}, "", fmt.Errorf("client secret %s does not contain key %s", spec.ClientSecret.SecretName, key)
+		}
+		if err := labelClientSecret(ctx, params, secret); err != nil {
+			return otkClient{}, "", err
+		}
+		secretVersion = secret.ResourceVersion
+	}
+
+	name := spec.ClientName
+	if name == "" {
+		name = params.Instance.Name
+	}
+
+	return otkClient{
+		ClientIdent:  clientIdent(params.Instance),
+		Name:         name,
+		Description:  spec.Description,
+		Type:         string(clientType),
+		RegisteredBy: "layer7-operator",
+		ClientKeys: []otkClientKey{{
+			ClientKey:   spec.ClientId,
+			Secret:      clientSecret,
+			Scope:       strings.Join(spec.Scopes, " "),
+			Callback:    strings.Join(spec.RedirectUris, ","),
+			GrantTypes:  strings.Join(spec.GrantTypes, " "),
+			Environment: "ALL",
+			Status:      "ENABLED",
+		}},
+	}, secretVersion, nil
+}
+
+// labelClientSecret labels the client secret so that the OtkClient controller only watches client secrets
+func labelClientSecret(ctx context.Context, params Params, secret *corev1.Secret) error {
+	if _, ok := secret.Labels[OtkClientSecretLabel]; ok {
+		return nil
+	}
+	patch := client.MergeFrom(secret.DeepCopy())
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[OtkClientSecretLabel] = "true"
+	if err := params.Client.Patch(ctx, secret, patch); err != nil {
+		return fmt.Errorf("failed to label client secret %s: %w", secret.Name, err)
+	}
+	return nil
+}
+
+// clientIdent identifies the client in OTK, it is stable for the lifetime of the OtkClient
+func clientIdent(otkClient *securityv1alpha1.OtkClient) string {
+	return otkClient.Namespace + "-" + otkClient.Name
+}
+
+// clientChecksum is stored in the OtkClient status, the client secret is replaced by the resourceVersion
+// of the secret that contains it so that the client is updated when the secret rotates without exposing a hash of the secret
+func clientChecksum(client otkClient, secretVersion string) (string, error) {
+	client.ClientKeys = slices.Clone(client.ClientKeys)
+	for i := range client.ClientKeys {
+		client.ClientKeys[i].Secret = ""
+	}
+	clientBytes, err := json.Marshal(client)
+	if err != nil {
+		return "", err
+	}
+	h := sha1.New()
+	h.Write(clientBytes)
+	h.Write([]byte(secretVersion))
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// clientStore returns the OTK client store of a running pod of the referenced Gateway
+func clientStore(ctx context.Context, params Params) (*otkClientStore, string, error) {
+	gateway := &securityv1.Gateway{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: params.Instance.Spec.GatewayReference, Namespace: params.Instance.Namespace}, gateway)
+	if err != nil {
+		return nil, "", err
+	}
+	if !gateway.Spec.App.Otk.Enabled {
+		return nil, "", fmt.Errorf("otk is not enabled on gateway %s", gateway.Name)
+	}
+	if gateway.Spec.App.Otk.Type == securityv1.OtkTypeDMZ {
+		return nil, "", fmt.Errorf("otk clients must be registered with a gateway of otk type single or internal, gateway %s is of type dmz", gateway.Name)
+	}
+
+	gatewayParams := gatewayreconcile.Params{
+		Client:   params.Client,
+		Scheme:   params.Scheme,
+		Log:      params.Log,
+		Instance: gateway,
+		Platform: params.Platform,
+	}
+	username, password, endpoint, pod, err := gatewayreconcile.ManagementTarget(ctx, gatewayParams)
+	if err != nil {
+		return nil, "", err
+	}
+
+	otkPort := 8443
+	if gateway.Spec.App.Otk.OTKPort != 0 {
+		otkPort = gateway.Spec.App.Otk.OTKPort
+	}
+	store, err := newOtkClientStore(username, password, endpoint, otkPort)
+	if err != nil {
+		return nil, "", err
+	}
+	return store, pod, nil
+}
+
+// updateStatus persists status changes and returns reconcileErr so that failed registrations are retried
+func updateStatus(ctx context.Context, params Params, status securityv1alpha1.OtkClientStatus, reconcileErr error) error {
+	if reflect.DeepEqual(params.Instance.Status, status) {
+		return reconcileErr
+	}
+
+	params.Instance.Status = status
+	err := params.Client.Status().Update(ctx, params.Instance)
+	if err != nil {
+		params.Log.V(2).Info("failed to update otk client status", "name", params.Instance.Name, "namespace", params.Instance.Namespace, "message", err.Error())
+		return err
+	}
+	params.Log.V(2).Info("updated otk client status", "name", params.Instance.Name, "namespace", params.Instance.Namespace)
+	return reconcileErr
+}
@@ -0,0 +1,207 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	securityv1 "github.com/caapim/layer7-operator/api/v1"
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// testClientStore is a stand-in for the OTK client persistence service of a Gateway pod
+type testClientStore struct {
+	*httptest.Server
+	mu      sync.Mutex
+	clients map[string]otkClient
+	stores  int
+}
+
+func newTestClientStore(t *testing.T) *testClientStore {
+	s := &testClientStore{clients: map[string]otkClient{}}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if username, password, ok := r.BasicAuth(); !ok || username != "admin" || password != "7layer" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case otkClientStorePath + "/store":
+			client := otkClient{}
+			_ = json.NewDecoder(r.Body).Decode(&client)
+			s.clients[client.ClientIdent] = client
+			s.stores++
+		case otkClientStorePath + "/delete":
+			req := map[string]string{}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			delete(s.clients, req["client_ident"])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestRegister(t *testing.T) {
+	clientStore := newTestClientStore(t)
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(clientStore.URL, "https://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otkPort, _ := strconv.Atoi(port)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+	_ = securityv1alpha1.AddToScheme(scheme)
+
+	gateway := &securityv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "otk-internal", Namespace: "default"}}
+	// the client store is called on the otk port, not the graphman port
+	gateway.Spec.App.Otk = securityv1.Otk{Enabled: true, Type: securityv1.OtkTypeInternal, OTKPort: otkPort}
+	gateway.Spec.App.Management.Graphman.DynamicSyncPort = 9443
+
+	otkClient := &securityv1alpha1.OtkClient{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+		Spec: securityv1alpha1.OtkClientSpec{
+			GatewayReference: "otk-internal",
+			ClientId:         "orders-key",
+			ClientSecret:     securityv1alpha1.OtkClientSecret{SecretName: "orders-secret"},
+			RedirectUris:     []string{"https://orders.example.com/callback"},
+			Scopes:           []string{"openid", "orders"},
+			GrantTypes:       []string{"authorization_code"},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		gateway,
+		otkClient,
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "otk-internal", Namespace: "default"}, Data: map[string][]byte{"SSG_ADMIN_USERNAME": []byte("admin"), "SSG_ADMIN_PASSWORD": []byte("7layer")}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "orders-secret", Namespace: "default"}, Data: map[string][]byte{"clientSecret": []byte("s1")}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "otk-internal-0", Namespace: "default", Labels: util.DefaultLabels("otk-internal", map[string]string{})},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: host},
+		},
+	).WithStatusSubresource(&securityv1alpha1.OtkClient{}).Build()
+
+	newParams := func(t *testing.T) Params {
+		current := &securityv1alpha1.OtkClient{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "orders", Namespace: "default"}, current); err != nil {
+			t.Fatal(err)
+		}
+		return Params{
+			Client:   k8sClient,
+			Scheme:   scheme,
+			Log:      zap.New(zap.UseDevMode(true)),
+			Instance: current,
+		}
+	}
+
+	t.Run("should register the client once", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if err := Register(context.Background(), newParams(t)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if clientStore.stores != 1 {
+			t.Errorf("expected 1 registration, actual %d", clientStore.stores)
+		}
+		registered := clientStore.clients["default-orders"]
+		if registered.Name != "orders" || registered.Type != "confidential" || len(registered.ClientKeys) != 1 {
+			t.Fatalf("unexpected client %+v", registered)
+		}
+		key := registered.ClientKeys[0]
+		if key.ClientKey != "orders-key" || key.Secret != "s1" || key.Scope != "openid orders" || key.Callback != "https://orders.example.com/callback" {
+			t.Errorf("unexpected client key %+v", key)
+		}
+		status := newParams(t).Instance.Status
+		if !status.Registered || status.Gateway != "otk-internal" || status.Pod != "otk-internal-0" || status.ClientId != "orders-key" {
+			t.Errorf("unexpected status %+v", status)
+		}
+		secret := &corev1.Secret{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "orders-secret", Namespace: "default"}, secret); err != nil {
+			t.Fatal(err)
+		}
+		if secret.Labels[OtkClientSecretLabel] != "true" {
+			t.Errorf("expected the client secret to be labelled, actual %v", secret.Labels)
+		}
+	})
+
+	t.Run("should update the client when the secret rotates", func(t *testing.T) {
+		secret := &corev1.Secret{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "orders-secret", Namespace: "default"}, secret); err != nil {
+			t.Fatal(err)
+		}
+		secret.Data["clientSecret"] = []byte("s2")
+		if err := k8sClient.Update(context.Background(), secret); err != nil {
+			t.Fatal(err)
+		}
+		if err := Register(context.Background(), newParams(t)); err != nil {
+			t.Fatal(err)
+		}
+		if clientStore.stores != 2 || clientStore.clients["default-orders"].ClientKeys[0].Secret != "s2" {
+			t.Errorf("expected the rotated secret to be registered")
+		}
+	})
+
+	t.Run("should report clients that cannot be registered", func(t *testing.T) {
+		params := newParams(t)
+		params.Instance.Spec.GatewayReference = "missing"
+		if err := Register(context.Background(), params); err == nil {
+			t.Fatal("expected an error")
+		}
+		status := newParams(t).Instance.Status
+		if status.Registered || status.Reason == "" {
+			t.Errorf("expected the failure to be reported, actual %+v", status)
+		}
+	})
+
+	t.Run("should remove the client from the gateway", func(t *testing.T) {
+		params := newParams(t)
+		params.Instance.Status.Gateway = "otk-internal"
+		if err := Remove(context.Background(), params); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := clientStore.clients["default-orders"]; ok {
+			t.Errorf("expected the client to be removed")
+		}
+	})
+}
+
+func TestClientChecksum(t *testing.T) {
+	client := func(secret string) otkClient {
+		return otkClient{ClientIdent: "default-orders", Name: "orders", ClientKeys: []otkClientKey{{ClientKey: "orders-key", Secret: secret}}}
+	}
+	first, err := clientChecksum(client("s1"), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := clientChecksum(client("s2"), "1")
+	if first != second {
+		t.Errorf("expected the checksum not to depend on the client secret")
+	}
+	rotated, _ := clientChecksum(client("s2"), "2")
+	if first == rotated {
+		t.Errorf("expected the checksum to change with the client secret resourceVersion")
+	}
+	c := client("s1")
+	_, _ = clientChecksum(c, "1")
+	if c.ClientKeys[0].Secret != "s1" {
+		t.Errorf("expected the client secret to be kept on the client")
+	}
+}
@@ -0,0 +1,228 @@
+package reconcile
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/caapim/layer7-operator/api/v1alpha1"
+	"github.com/caapim/layer7-operator/internal/templategen"
+	apireconcile "github.com/caapim/layer7-operator/pkg/api/reconcile"
+	"github.com/caapim/layer7-operator/pkg/util"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	apiFinalizer = "security.brcmlabs.com/finalizer"
+	// traceIdAnnotation is the checksum of the Portal API that L7Api deployment conditions refer to
+	traceIdAnnotation = "app.l7.traceId"
+	// portalReportedAnnotation records the last deployment status that was reported to the Portal
+	portalReportedAnnotation = "security.brcmlabs.com/portal-reported-status"
+)
+
+// syncPortalApis creates, updates or deletes the L7Apis owned by an L7Portal so that they match the APIs deployed to the Portal tenant
+// the deployment status of each L7Api is reported back to the Portal
+func syncPortalApis(ctx context.Context, params Params, papi *papiClient, l7Portal *v1alpha1.L7Portal) error {
+	portalAPIs, err := papi.deployedApis()
+	if err != nil {
+		return err
+	}
+
+	l7ApiList := &v1alpha1.L7ApiList{}
+	err = params.Client.List(ctx, l7ApiList, client.InNamespace(l7Portal.Namespace))
+	if err != nil {
+		return err
+	}
+
+	currentApis := map[string]*v1alpha1.L7Api{}
+	for i, l7Api := range l7ApiList.Items {
+		if l7Api.Spec.PortalPublished && l7Api.Spec.L7Portal == l7Portal.Name && metav1.IsControlledBy(&l7Api, l7Portal) {
+			currentApis[l7Api.Name] = &l7ApiList.Items[i]
+		}
+	}
+
+	errs := []error{}
+	desiredApis := map[string]bool{}
+	for _, portalAPI := range portalAPIs {
+		desiredApi, err := newL7Api(params, l7Portal, portalAPI)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		desiredApis[desiredApi.Name] = true
+
+		currentApi, ok := currentApis[desiredApi.Name]
+		if !ok {
+			if err := params.Client.Create(ctx, desiredApi); err != nil {
+				errs = append(errs, fmt.Errorf("failed to create l7api %s: %w", desiredApi.Name, err))
+				continue
+			}
+			params.Log.Info("created l7api", "name", desiredApi.Name, "api", portalAPI.Name, "namespace", l7Portal.Namespace)
+			continue
+		}
+
+		// an L7Api that is being removed is recreated once its finalizer has been removed
+		if currentApi.DeletionTimestamp != nil {
+			continue
+		}
+
+		if currentApi.Annotations[traceIdAnnotation] == desiredApi.Annotations[traceIdAnnotation] && reflect.DeepEqual(currentApi.Spec, desiredApi.Spec) {
+			continue
+		}
+
+		patch := client.MergeFrom(currentApi.DeepCopy())
+		currentApi.Spec = desiredApi.Spec
+		if currentApi.Annotations == nil {
+			currentApi.Annotations = map[string]string{}
+		}
+		currentApi.Annotations[traceIdAnnotation] = desiredApi.Annotations[traceIdAnnotation]
+		if err := params.Client.Patch(ctx, currentApi, patch); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update l7api %s: %w", currentApi.Name, err))
+			continue
+		}
+		params.Log.Info("updated l7api", "name", currentApi.Name, "api", portalAPI.Name, "namespace", l7Portal.Namespace)
+	}
+
+	for name, currentApi := range currentApis {
+		if !desiredApis[name] && currentApi.DeletionTimestamp == nil {
+			if err := params.Client.Delete(ctx, currentApi); err != nil && !k8serrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to delete l7api %s: %w", name, err))
+				continue
+			}
+			params.Log.Info("removed l7api", "name", name, "namespace", l7Portal.Namespace)
+			// the deletion status is reported once the api has been removed from each gateway
+			continue
+		}
+		if err := reportDeploymentStatus(ctx, params, papi, l7Portal, currentApi); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// newL7Api converts a Portal API into an L7Api that is owned by the L7Portal
+func newL7Api(params Params, l7Portal *v1alpha1.L7Portal, portalAPI templategen.PortalAPI) (*v1alpha1.L7Api, error) {
+	if portalAPI.TenantId == "" {
+		portalAPI.TenantId = l7Portal.Spec.PortalTenant
+	}
+	if portalAPI.UuidStripped == "" {
+		portalAPI.UuidStripped = strings.ReplaceAll(portalAPI.Uuid, "-", "")
+	}
+
+	portalMeta := v1alpha1.PortalMeta{}
+	portalMetaBytes, err := json.Marshal(portalAPI)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(portalMetaBytes, &portalMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := portalAPI.Checksum
+	if checksum == "" {
+		h := sha1.New()
+		h.Write(portalMetaBytes)
+		checksum = fmt.Sprintf("%x", h.Sum(nil))
+	}
+
+	l7Api := &v1alpha1.L7Api{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        l7Portal.Name + "-" + strings.ToLower(portalAPI.UuidStripped),
+			Namespace:   l7Portal.Namespace,
+			Labels:      util.DefaultLabels(l7Portal.Name, map[string]string{}),
+			Annotations: map[string]string{traceIdAnnotation: checksum},
+			// removal is reported to the Portal before the finalizer is removed
+			Finalizers: []string{apiFinalizer},
+		},
+		Spec: v1alpha1.L7ApiSpec{
+			ServiceUrl:      portalAPI.SsgUrl,
+			PortalPublished: true,
+			L7Portal:        l7Portal.Name,
+			PortalMeta:      portalMeta,
+			DeploymentTags:  l7Portal.Spec.DeploymentTags,
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(l7Portal, l7Api, params.Scheme); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+	return l7Api, nil
+}
+
+// reportDeploymentStatus reports the deployment status of the current L7Api checksum to the Portal
+// L7Apis that have been removed from every gateway are reported as undeployed and their finalizer is removed
+func reportDeploymentStatus(ctx context.Context, params Params, papi *papiClient, l7Portal *v1alpha1.L7Portal, l7Api *v1alpha1.L7Api) error {
+	checksum := l7Api.Annotations[traceIdAnnotation]
+
+	if l7Api.DeletionTimestamp != nil {
+		if l7Api.Annotations[apireconcile.L7API_REMOVED_ANNOTATION] != "true" {
+			return nil
+		}
+		status, message, ok := deploymentStatus(l7Api.Status, checksum, apireconcile.UNDEPLOY)
+		if !ok {
+			status = PortalDeploymentUndeployed
+		}
+		err := papi.reportDeployment(l7Api.Spec.PortalMeta.Uuid, PortalDeploymentStatus{Proxy: l7Portal.Name, Status: status, Message: message, Checksum: checksum})
+		if err != nil {
+			return err
+		}
+		controllerutil.RemoveFinalizer(l7Api, apiFinalizer)
+		if err := params.Client.Update(ctx, l7Api); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		params.Log.Info("reported api removal to portal", "name", l7Api.Name, "status", status, "namespace", l7Api.Namespace)
+		return nil
+	}
+
+	status, message, ok := deploymentStatus(l7Api.Status, checksum, apireconcile.DEPLOY)
+	if !ok || l7Api.Annotations[portalReportedAnnotation] == checksum+"/"+status {
+		return nil
+	}
+
+	err := papi.reportDeployment(l7Api.Spec.PortalMeta.Uuid, PortalDeploymentStatus{Proxy: l7Portal.Name, Status: status, Message: message, Checksum: checksum})
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(l7Api.DeepCopy())
+	l7Api.Annotations[portalReportedAnnotation] = checksum + "/" + status
+	if err := params.Client.Patch(ctx, l7Api, patch); err != nil {
+		return err
+	}
+	params.Log.Info("reported api deployment to portal", "name", l7Api.Name, "status", status, "namespace", l7Api.Namespace)
+	return nil
+}
+
+// deploymentStatus summarises the gateway pod conditions of an L7Api for checksum and action
+// ok is false if the L7Api has not been applied to any gateway pod yet
+func deploymentStatus(l7ApiStatus v1alpha1.L7ApiStatus, checksum string, action string) (status string, message string, ok bool) {
+	failures := []string{}
+	for _, gateway := range l7ApiStatus.Gateways {
+		for _, condition := range gateway.Conditions {
+			if condition.Checksum != checksum || condition.Action != action {
+				continue
+			}
+			ok = true
+			if condition.Status == apireconcile.FAILURE {
+				failures = append(failures, gateway.Name+": "+condition.Reason)
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return PortalDeploymentError, strings.Join(failures, "; "), ok
+	}
+	if action == apireconcile.UNDEPLOY {
+		return PortalDeploymentUndeployed, "", ok
+	}
+	return PortalDeploymentDeployed, "", ok
+}
@@ -0,0 +1,349 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/caapim/layer7-operator/api/v1alpha1"
+	"github.com/caapim/layer7-operator/internal/templategen"
+	apireconcile "github.com/caapim/layer7-operator/pkg/api/reconcile"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// testPortal is a stand-in for the Portal API (PAPI) of a single tenant
+type testPortal struct {
+	*httptest.Server
+	mu       sync.Mutex
+	apis     []templategen.PortalAPI
+	statuses map[string][]PortalDeploymentStatus
+	// enrollmentBundle is returned by the Portal, graphmanRequests counts bundles applied to Gateway pods
+	enrollmentBundle []byte
+	proxies          []PortalProxyStatus
+	graphmanRequests int
+}
+
+func newTestPortal(t *testing.T) *testPortal {
+	p := &testPortal{statuses: map[string][]PortalDeploymentStatus{}}
+	p.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/auth/oauth/v2/token":
+			_, _ = w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		case "/graphman":
+			p.graphmanRequests++
+			_, _ = w.Write([]byte(`{"data":{}}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path == "/tenant/deployments/1.0/enrollment-bundle" {
+			_, _ = w.Write(p.enrollmentBundle)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/tenant/deployments/1.0/proxies/") {
+			proxy := PortalProxyStatus{}
+			_ = json.NewDecoder(r.Body).Decode(&proxy)
+			p.proxies = append(p.proxies, proxy)
+			return
+		}
+
+		path := strings.TrimPrefix(r.URL.Path, "/tenant/api-management/1.0/apis")
+		switch {
+		case path == "":
+			_ = json.NewEncoder(w).Encode(RawPortalAPISummary{APIs: p.apis})
+			return
+		case strings.HasSuffix(path, "/deployment-status"):
+			status := PortalDeploymentStatus{}
+			_ = json.NewDecoder(r.Body).Decode(&status)
+			uuid := strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/deployment-status")
+			p.statuses[uuid] = append(p.statuses[uuid], status)
+			return
+		case strings.HasSuffix(path, "/secure-passwords"):
+			_, _ = w.Write([]byte(`[{"id":"pw1","name":"backend","value":"secret"}]`))
+			return
+		}
+		for _, api := range p.apis {
+			if "/"+api.Uuid == path {
+				_ = json.NewEncoder(w).Encode(api)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *testPortal) reported(uuid string) []PortalDeploymentStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.statuses[uuid]
+}
+
+func TestSyncPortalApis(t *testing.T) {
+	portalServer := newTestPortal(t)
+	portalServer.apis = []templategen.PortalAPI{
+		{Uuid: "1d6a6b4a-0b0e-4c49-9b5e-0d1b6b3b0a01", Name: "orders", SsgUrl: "orders", Checksum: "c1",
+			PolicyTemplates: []templategen.PolicyTemplate{{Uuid: "template", ApiPolicyTemplateArguments: []templategen.PolicyTemplateArg{{Name: "limit", Value: "10"}}}},
+			CustomFields:    []templategen.CustomField{{Name: "owner", Value: "team"}}},
+		{Uuid: "2d6a6b4a-0b0e-4c49-9b5e-0d1b6b3b0a02", Name: "payments", SsgUrl: "payments", Checksum: "c2"},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	l7Portal := &v1alpha1.L7Portal{
+		ObjectMeta: metav1.ObjectMeta{Name: "portal", Namespace: "default", UID: "portal-uid"},
+		Spec: v1alpha1.L7PortalSpec{
+			Enabled:        true,
+			Endpoint:       strings.TrimPrefix(portalServer.URL, "https://"),
+			PortalTenant:   "tenant",
+			DeploymentTags: []string{"ssg"},
+			Auth:           v1alpha1.PortalAuth{PapiClientId: "client", PapiClientSecret: "secret"},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(l7Portal).WithStatusSubresource(&v1alpha1.L7Api{}).Build()
+	params := Params{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: l7Portal,
+	}
+	papi, err := newPapiClient(context.Background(), params, l7Portal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ordersName := types.NamespacedName{Name: "portal-1d6a6b4a0b0e4c499b5e0d1b6b3b0a01", Namespace: "default"}
+	paymentsName := types.NamespacedName{Name: "portal-2d6a6b4a0b0e4c499b5e0d1b6b3b0a02", Namespace: "default"}
+
+	t.Run("should create an L7Api for each portal api", func(t *testing.T) {
+		if err := syncPortalApis(context.Background(), params, papi, l7Portal); err != nil {
+			t.Fatal(err)
+		}
+		l7Api := &v1alpha1.L7Api{}
+		if err := k8sClient.Get(context.Background(), ordersName, l7Api); err != nil {
+			t.Fatal(err)
+		}
+		if !l7Api.Spec.PortalPublished || l7Api.Spec.L7Portal != "portal" || !metav1.IsControlledBy(l7Api, l7Portal) {
+			t.Errorf("expected the l7api to be published by and owned by the portal")
+		}
+		meta := l7Api.Spec.PortalMeta
+		if meta.Name != "orders" || meta.TenantId != "tenant" || len(meta.PolicyTemplates) != 1 || len(meta.CustomFields) != 1 {
+			t.Errorf("unexpected portal meta %+v", meta)
+		}
+		if len(meta.SecurePasswords) != 1 || meta.SecurePasswords[0].Value != "secret" {
+			t.Errorf("expected secure passwords to be set, actual %+v", meta.SecurePasswords)
+		}
+		if l7Api.Annotations[traceIdAnnotation] != "c1" || l7Api.Spec.DeploymentTags[0] != "ssg" {
+			t.Errorf("expected the checksum and deployment tags to be set")
+		}
+		if err := k8sClient.Get(context.Background(), paymentsName, &v1alpha1.L7Api{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("should update an L7Api when the portal api changes", func(t *testing.T) {
+		portalServer.mu.Lock()
+		portalServer.apis[0].Checksum = "c1-updated"
+		portalServer.apis[0].SsgUrl = "orders/v2"
+		portalServer.mu.Unlock()
+
+		if err := syncPortalApis(context.Background(), params, papi, l7Portal); err != nil {
+			t.Fatal(err)
+		}
+		l7Api := &v1alpha1.L7Api{}
+		if err := k8sClient.Get(context.Background(), ordersName, l7Api); err != nil {
+			t.Fatal(err)
+		}
+		if l7Api.Annotations[traceIdAnnotation] != "c1-updated" || l7Api.Spec.PortalMeta.SsgUrl != "orders/v2" {
+			t.Errorf("expected the l7api to be updated, actual %s %s", l7Api.Annotations[traceIdAnnotation], l7Api.Spec.PortalMeta.SsgUrl)
+		}
+	})
+
+	t.Run("should report deployment status once", func(t *testing.T) {
+		l7Api := &v1alpha1.L7Api{}
+		if err := k8sClient.Get(context.Background(), ordersName, l7Api); err != nil {
+			t.Fatal(err)
+		}
+		l7Api.Status.Gateways = []v1alpha1.LinkedGatewayStatus{
+			{Name: "ssg-0", Deployment: "ssg", Conditions: []v1alpha1.GatewayPodDeploymentCondition{{Action: apireconcile.DEPLOY, Checksum: "c1-updated", Status: apireconcile.SUCCESS}}},
+			{Name: "ssg-1", Deployment: "ssg", Conditions: []v1alpha1.GatewayPodDeploymentCondition{{Action: apireconcile.DEPLOY, Checksum: "c1-updated", Status: apireconcile.FAILURE, Reason: "timeout"}}},
+		}
+		if err := k8sClient.Status().Update(context.Background(), l7Api); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if err := syncPortalApis(context.Background(), params, papi, l7Portal); err != nil {
+				t.Fatal(err)
+			}
+		}
+		reported := portalServer.reported(portalServer.apis[0].Uuid)
+		if len(reported) != 1 {
+			t.Fatalf("expected 1 status report, actual %d", len(reported))
+		}
+		if reported[0].Status != PortalDeploymentError || reported[0].Message != "ssg-1: timeout" || reported[0].Proxy != "portal" {
+			t.Errorf("unexpected status report %+v", reported[0])
+		}
+		if len(portalServer.reported(portalServer.apis[1].Uuid)) != 0 {
+			t.Errorf("expected apis that have not been deployed to be skipped")
+		}
+	})
+
+	t.Run("should remove L7Apis that are no longer in the portal", func(t *testing.T) {
+		paymentsUuid := portalServer.apis[1].Uuid
+		portalServer.mu.Lock()
+		portalServer.apis = portalServer.apis[:1]
+		portalServer.mu.Unlock()
+
+		if err := syncPortalApis(context.Background(), params, papi, l7Portal); err != nil {
+			t.Fatal(err)
+		}
+		l7Api := &v1alpha1.L7Api{}
+		if err := k8sClient.Get(context.Background(), paymentsName, l7Api); err != nil {
+			t.Fatal(err)
+		}
+		if l7Api.DeletionTimestamp == nil {
+			t.Fatal("expected the l7api to be deleted")
+		}
+
+		// the l7api controller removes the api from each gateway
+		l7Api.Annotations[apireconcile.L7API_REMOVED_ANNOTATION] = "true"
+		if err := k8sClient.Update(context.Background(), l7Api); err != nil {
+			t.Fatal(err)
+		}
+		if err := syncPortalApis(context.Background(), params, papi, l7Portal); err != nil {
+			t.Fatal(err)
+		}
+
+		err := k8sClient.Get(context.Background(), paymentsName, &v1alpha1.L7Api{})
+		if !k8serrors.IsNotFound(err) {
+			t.Errorf("expected the l7api finalizer to be removed, actual %v", err)
+		}
+		reported := portalServer.reported(paymentsUuid)
+		if len(reported) != 1 || reported[0].Status != PortalDeploymentUndeployed {
+			t.Errorf("expected the removal to be reported, actual %+v", reported)
+		}
+		if err := k8sClient.Get(context.Background(), ordersName, &v1alpha1.L7Api{}); err != nil {
+			t.Errorf("expected the remaining l7api to be kept, actual %v", err)
+		}
+	})
+}
+
+func TestFinalize(t *testing.T) {
+	portalServer := newTestPortal(t)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	l7Portal := &v1alpha1.L7Portal{
+		ObjectMeta: metav1.ObjectMeta{Name: "portal", Namespace: "default", UID: "portal-uid", Finalizers: []string{portalFinalizer}},
+		Spec: v1alpha1.L7PortalSpec{
+			Enabled:      true,
+			Endpoint:     strings.TrimPrefix(portalServer.URL, "https://"),
+			PortalTenant: "tenant",
+			Auth:         v1alpha1.PortalAuth{PapiClientId: "client", PapiClientSecret: "secret"},
+		},
+	}
+	params := Params{
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: l7Portal,
+	}
+	deployed, err := newL7Api(params, l7Portal, templategen.PortalAPI{Uuid: "1d6a6b4a-0b0e-4c49-9b5e-0d1b6b3b0a01", Name: "orders", Checksum: "c1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployed.Status.Ready = true
+	notDeployed, err := newL7Api(params, l7Portal, templategen.PortalAPI{Uuid: "2d6a6b4a-0b0e-4c49-9b5e-0d1b6b3b0a02", Name: "payments", Checksum: "c2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(l7Portal, deployed, notDeployed).WithStatusSubresource(&v1alpha1.L7Api{}).Build()
+	params.Client = k8sClient
+	if err := k8sClient.Delete(context.Background(), l7Portal); err != nil {
+		t.Fatal(err)
+	}
+	if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "portal", Namespace: "default"}, l7Portal); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should delete owned L7Apis", func(t *testing.T) {
+		done, err := Finalize(context.Background(), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done {
+			t.Fatal("expected finalize to wait for the l7apis to be removed")
+		}
+		l7Api := &v1alpha1.L7Api{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: deployed.Name, Namespace: "default"}, l7Api); err != nil {
+			t.Fatal(err)
+		}
+		if l7Api.DeletionTimestamp == nil {
+			t.Errorf("expected the l7api to be deleted")
+		}
+	})
+
+	t.Run("should wait for deployed L7Apis to be removed from the gateways", func(t *testing.T) {
+		done, err := Finalize(context.Background(), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done {
+			t.Fatal("expected finalize to wait for the deployed l7api")
+		}
+		err = k8sClient.Get(context.Background(), types.NamespacedName{Name: notDeployed.Name, Namespace: "default"}, &v1alpha1.L7Api{})
+		if !k8serrors.IsNotFound(err) {
+			t.Errorf("expected the l7api that was never deployed to be removed, actual %v", err)
+		}
+	})
+
+	t.Run("should report the removal and remove the portal finalizer", func(t *testing.T) {
+		l7Api := &v1alpha1.L7Api{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: deployed.Name, Namespace: "default"}, l7Api); err != nil {
+			t.Fatal(err)
+		}
+		l7Api.Annotations[apireconcile.L7API_REMOVED_ANNOTATION] = "true"
+		if err := k8sClient.Update(context.Background(), l7Api); err != nil {
+			t.Fatal(err)
+		}
+
+		done, err := Finalize(context.Background(), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !done {
+			t.Fatal("expected finalize to complete")
+		}
+		reported := portalServer.reported("1d6a6b4a-0b0e-4c49-9b5e-0d1b6b3b0a01")
+		if len(reported) != 1 || reported[0].Status != PortalDeploymentUndeployed {
+			t.Errorf("expected the removal to be reported, actual %+v", reported)
+		}
+		err = k8sClient.Get(context.Background(), types.NamespacedName{Name: "portal", Namespace: "default"}, &v1alpha1.L7Portal{})
+		if !k8serrors.IsNotFound(err) {
+			t.Errorf("expected the l7portal to be removed, actual %v", err)
+		}
+	})
+}
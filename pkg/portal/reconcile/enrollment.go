@@ -0,0 +1,333 @@
+package reconcile
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	v1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/api/v1alpha1"
+	apireconcile "github.com/caapim/layer7-operator/pkg/api/reconcile"
+	"github.com/caapim/layer7-operator/pkg/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Proxy types that are reported in L7Portal status
+const (
+	ProxyTypeEphemeral = "Ephemeral"
+	ProxyTypeDbBacked  = "DbBacked"
+)
+
+// syncProxies applies the enrollment bundle to each Gateway that matches the L7Portal deployment tags
+// each Gateway is registered as a proxy and its sync state is reported in L7Portal and Gateway status
+// papi is nil if the L7Portal does not have an endpoint, an enrollment bundle must then be set in the L7Portal spec
+func syncProxies(ctx context.Context, params Params, papi *papiClient, l7Portal *v1alpha1.L7Portal) error {
+	enrollmentBundle, err := getEnrollmentBundle(l7Portal, papi)
+	if err != nil {
+		return err
+	}
+
+	portalStatus := l7Portal.Status.DeepCopy()
+	checksum := ""
+	if len(enrollmentBundle) > 0 {
+		updated, err := EnrollmentSecret(ctx, params, enrollmentBundle)
+		if err != nil {
+			return err
+		}
+		if updated || portalStatus.EnrollmentBundle.SecretName == "" {
+			portalStatus.EnrollmentBundle = v1alpha1.EnrollmentBundle{
+				SecretName:  l7Portal.Name + "-enrollment-bundle",
+				LastUpdated: time.Now().UTC().Format(time.RFC3339),
+			}
+		}
+		h := sha1.New()
+		h.Write(enrollmentBundle)
+		checksum = fmt.Sprintf("%x", h.Sum(nil))
+	}
+
+	l7ApiList := &v1alpha1.L7ApiList{}
+	err = params.Client.List(ctx, l7ApiList, client.InNamespace(l7Portal.Namespace))
+	if err != nil {
+		return err
+	}
+	l7Apis := []v1alpha1.L7Api{}
+	for _, l7Api := range l7ApiList.Items {
+		if l7Api.Spec.PortalPublished && l7Api.Spec.L7Portal == l7Portal.Name && l7Api.DeletionTimestamp == nil {
+			l7Apis = append(l7Apis, l7Api)
+		}
+	}
+
+	errs := []error{}
+	proxies := []v1alpha1.GatewayProxy{}
+	for _, tag := range l7Portal.Spec.DeploymentTags {
+		gateway := &v1.Gateway{}
+		err := params.Client.Get(ctx, types.NamespacedName{Name: tag, Namespace: l7Portal.Namespace}, gateway)
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		apiCount := 0
+		for _, l7Api := range l7Apis {
+			if slices.Contains(l7Api.Spec.DeploymentTags, gateway.Name) {
+				apiCount++
+			}
+		}
+
+		proxy, err := syncProxy(ctx, params, l7Portal, gateway, enrollmentBundle, checksum, l7Apis)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		previousProxy := v1alpha1.GatewayProxy{}
+		for _, p := range l7Portal.Status.GatewayProxies {
+			if p.Name == proxy.Name {
+				previousProxy = p
+			}
+		}
+		// LastUpdated only changes when the sync state of a Gateway pod changes
+		for i, proxyGateway := range proxy.Gateways {
+			proxy.Gateways[i].LastUpdated = time.Now().UTC().Format(time.RFC3339)
+			for _, previousGateway := range previousProxy.Gateways {
+				if previousGateway.Name == proxyGateway.Name && previousGateway.Synchronised == proxyGateway.Synchronised {
+					proxy.Gateways[i].LastUpdated = previousGateway.LastUpdated
+				}
+			}
+		}
+		if papi != nil && !reflect.DeepEqual(previousProxy, proxy) {
+			err = papi.registerProxy(PortalProxyStatus{Name: proxy.Name, Type: proxy.Type, ApiCount: apiCount, Gateways: proxy.Gateways})
+			if err != nil {
+				errs = append(errs, err)
+				// the previous state is kept so that registration is retried on the next sync
+				proxy = previousProxy
+			}
+		}
+		if proxy.Name != "" {
+			proxies = append(proxies, proxy)
+		}
+
+		err = updatePortalSyncStatus(ctx, params, gateway, l7Portal.Name, apiCount)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	portalStatus.GatewayProxies = proxies
+	portalStatus.Ready = len(proxies) > 0
+	for _, proxy := range proxies {
+		for _, proxyGateway := range proxy.Gateways {
+			if !proxyGateway.Synchronised {
+				portalStatus.Ready = false
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(*portalStatus, l7Portal.Status) {
+		l7Portal.Status = *portalStatus
+		err := params.Client.Status().Update(ctx, l7Portal)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to update portal status: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// getEnrollmentBundle returns the enrollment bundle that is set in the L7Portal spec or retrieves it from the Portal
+// the L7Portal enrollment bundle may be a Graphman bundle or a base64 encoded Graphman bundle
+func getEnrollmentBundle(l7Portal *v1alpha1.L7Portal, papi *papiClient) ([]byte, error) {
+	if l7Portal.Spec.EnrollmentBundle != "" {
+		enrollmentBundle, err := base64.StdEncoding.DecodeString(l7Portal.Spec.EnrollmentBundle)
+		if err != nil {
+			return []byte(l7Portal.Spec.EnrollmentBundle), nil
+		}
+		return enrollmentBundle, nil
+	}
+	if papi == nil {
+		return nil, nil
+	}
+	return papi.enrollmentBundle()
+}
+
+// syncProxy applies the enrollment bundle to a Gateway and reports whether each Gateway pod is synchronised
+// a pod is synchronised when it is ready, has the latest enrollment bundle and every L7Api has been deployed to it
+func syncProxy(ctx context.Context, params Params, l7Portal *v1alpha1.L7Portal, gateway *v1.Gateway, enrollmentBundle []byte, checksum string, l7Apis []v1alpha1.L7Api) (v1alpha1.GatewayProxy, error) {
+	proxy := v1alpha1.GatewayProxy{Name: gateway.Name, Type: ProxyTypeEphemeral}
+	if gateway.Spec.App.Management.Database.Enabled {
+		proxy.Type = ProxyTypeDbBacked
+	}
+
+	podList := &corev1.PodList{}
+	err := params.Client.List(ctx, podList, client.InNamespace(gateway.Namespace), client.MatchingLabels(util.DefaultLabels(gateway.Name, map[string]string{})))
+	if err != nil {
+		return proxy, err
+	}
+
+	graphmanPort := 9443
+	if gateway.Spec.App.Management.Graphman.DynamicSyncPort != 0 {
+		graphmanPort = gateway.Spec.App.Management.Graphman.DynamicSyncPort
+	}
+	annotation := "security.brcmlabs.com/" + l7Portal.Name + "-enrollment"
+
+	username, password := "", ""
+	if checksum != "" {
+		name := gateway.Name
+		if gateway.Spec.App.Management.SecretName != "" {
+			name = gateway.Spec.App.Management.SecretName
+		}
+		gwSecret := &corev1.Secret{}
+		err := params.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: gateway.Namespace}, gwSecret)
+		if err != nil {
+			return proxy, err
+		}
+		username = string(gwSecret.Data["SSG_ADMIN_USERNAME"])
+		password = string(gwSecret.Data["SSG_ADMIN_PASSWORD"])
+	}
+
+	errs := []error{}
+	deploymentEnrolled := true
+	if proxy.Type == ProxyTypeDbBacked && checksum != "" {
+		deploymentEnrolled, err = enrollDeployment(ctx, params, gateway, annotation, username, password, graphmanPort, enrollmentBundle, checksum)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		ready := pod.Status.Phase == corev1.PodRunning
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name == "gateway" && !containerStatus.Ready {
+				ready = false
+			}
+		}
+
+		enrolled := deploymentEnrolled
+		if proxy.Type == ProxyTypeEphemeral && checksum != "" {
+			enrolled = pod.Annotations[annotation] == checksum
+			if !enrolled && ready {
+				endpoint := util.PodIP(pod.Status.PodIP) + ":" + strconv.Itoa(graphmanPort) + "/graphman"
+				err := util.ApplyGraphmanBundle(ctx, username, password, endpoint, "", enrollmentBundle)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("failed to apply enrollment bundle to %s: %w", pod.Name, err))
+				} else {
+					patch := fmt.Sprintf("{\"metadata\": {\"annotations\": {\"%s\": \"%s\"}}}", annotation, checksum)
+					if err := params.Client.Patch(ctx, &pod, client.RawPatch(types.StrategicMergePatchType, []byte(patch))); err != nil {
+						errs = append(errs, err)
+					} else {
+						params.Log.Info("applied enrollment bundle", "pod", pod.Name, "gateway", gateway.Name, "namespace", gateway.Namespace)
+						enrolled = true
+					}
+				}
+			}
+		}
+
+		// L7Apis are only deployed to ephemeral Gateways
+		apisSynchronised := true
+		if proxy.Type == ProxyTypeEphemeral {
+			for _, l7Api := range l7Apis {
+				if slices.Contains(l7Api.Spec.DeploymentTags, gateway.Name) && !deployedToPod(l7Api, gateway.Name, pod.Name) {
+					apisSynchronised = false
+				}
+			}
+		}
+
+		proxy.Gateways = append(proxy.Gateways, v1alpha1.ProxyGateway{
+			Name:         pod.Name,
+			Synchronised: ready && enrolled && apisSynchronised,
+		})
+	}
+
+	slices.SortFunc(proxy.Gateways, func(a, b v1alpha1.ProxyGateway) int {
+		if a.Name < b.Name {
+			return -1
+		}
+		if a.Name > b.Name {
+			return 1
+		}
+		return 0
+	})
+	return proxy, errors.Join(errs...)
+}
+
+// enrollDeployment applies the enrollment bundle once to a database backed Gateway
+func enrollDeployment(ctx context.Context, params Params, gateway *v1.Gateway, annotation string, username string, password string, graphmanPort int, enrollmentBundle []byte, checksum string) (bool, error) {
+	gatewayDeployment := &appsv1.Deployment{}
+	err := params.Client.Get(ctx, types.NamespacedName{Name: gateway.Name, Namespace: gateway.Namespace}, gatewayDeployment)
+	if err != nil {
+		return false, err
+	}
+
+	if gatewayDeployment.Annotations[annotation] == checksum {
+		return true, nil
+	}
+
+	if gatewayDeployment.Status.ReadyReplicas != gatewayDeployment.Status.Replicas {
+		return false, nil
+	}
+
+	endpoint := gateway.Name + "." + gateway.Namespace + ".svc.cluster.local:" + strconv.Itoa(graphmanPort) + "/graphman"
+	if gateway.Spec.App.Management.Service.Enabled {
+		endpoint = gateway.Name + "-management-service." + gateway.Namespace + ".svc.cluster.local:" + strconv.Itoa(graphmanPort) + "/graphman"
+	}
+
+	err = util.ApplyGraphmanBundle(ctx, username, password, endpoint, "", enrollmentBundle)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply enrollment bundle to %s: %w", gateway.Name, err)
+	}
+
+	patch := fmt.Sprintf("{\"metadata\": {\"annotations\": {\"%s\": \"%s\"}}}", annotation, checksum)
+	if err := params.Client.Patch(ctx, gatewayDeployment, client.RawPatch(types.StrategicMergePatchType, []byte(patch))); err != nil {
+		return false, err
+	}
+	params.Log.Info("applied enrollment bundle", "gateway", gateway.Name, "namespace", gateway.Namespace)
+	return true, nil
+}
+
+// deployedToPod returns true if the current checksum of an L7Api has been deployed to a Gateway pod
+func deployedToPod(l7Api v1alpha1.L7Api, tag string, podName string) bool {
+	for _, gateway := range l7Api.Status.Gateways {
+		if gateway.Name != podName || gateway.Deployment != tag {
+			continue
+		}
+		for _, condition := range gateway.Conditions {
+			if condition.Checksum == l7Api.Annotations[traceIdAnnotation] && condition.Action == apireconcile.DEPLOY && condition.Status == apireconcile.SUCCESS {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// updatePortalSyncStatus reports the number of L7Apis that target a Gateway in the Gateway status
+func updatePortalSyncStatus(ctx context.Context, params Params, gateway *v1.Gateway, portalName string, apiCount int) error {
+	if gateway.Status.PortalSyncStatus.Name == portalName && gateway.Status.PortalSyncStatus.ApiCount == apiCount {
+		return nil
+	}
+
+	patch := client.MergeFrom(gateway.DeepCopy())
+	gateway.Status.PortalSyncStatus = v1.PortalSyncStatus{
+		Name:        portalName,
+		ApiCount:    apiCount,
+		LastUpdated: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := params.Client.Status().Patch(ctx, gateway, patch); err != nil {
+		return fmt.Errorf("failed to update portal sync status of gateway %s: %w", gateway.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	v1 "github.com/caapim/layer7-operator/api/v1"
+	"github.com/caapim/layer7-operator/api/v1alpha1"
+	apireconcile "github.com/caapim/layer7-operator/pkg/api/reconcile"
+	"github.com/caapim/layer7-operator/pkg/portal"
+	"github.com/caapim/layer7-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestSyncProxies(t *testing.T) {
+	portalServer := newTestPortal(t)
+	portalServer.enrollmentBundle = []byte(`{"clusterProperties":[{"name":"portal.enrolled","value":"true"}]}`)
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(portalServer.URL, "https://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	graphmanPort, _ := strconv.Atoi(port)
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	l7Portal := &v1alpha1.L7Portal{
+		ObjectMeta: metav1.ObjectMeta{Name: "enroll", Namespace: "default", UID: "enroll-uid"},
+		Spec: v1alpha1.L7PortalSpec{
+			Enabled:        true,
+			Endpoint:       strings.TrimPrefix(portalServer.URL, "https://"),
+			PortalTenant:   "tenant",
+			DeploymentTags: []string{"ssg", "missing"},
+			Auth:           v1alpha1.PortalAuth{PapiClientId: "client", PapiClientSecret: "secret"},
+		},
+	}
+	gateway := &v1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default"}}
+	gateway.Spec.App.Management.Graphman.DynamicSyncPort = graphmanPort
+	gwSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssg", Namespace: "default"},
+		Data:       map[string][]byte{"SSG_ADMIN_USERNAME": []byte("admin"), "SSG_ADMIN_PASSWORD": []byte("password")},
+	}
+	l7Api := &v1alpha1.L7Api{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default", Annotations: map[string]string{traceIdAnnotation: "c1"}},
+		Spec:       v1alpha1.L7ApiSpec{PortalPublished: true, L7Portal: "enroll", DeploymentTags: []string{"ssg"}},
+		Status: v1alpha1.L7ApiStatus{Gateways: []v1alpha1.LinkedGatewayStatus{
+			{Name: "ssg-0", Deployment: "ssg", Conditions: []v1alpha1.GatewayPodDeploymentCondition{{Action: apireconcile.DEPLOY, Checksum: "c1", Status: apireconcile.SUCCESS}}},
+		}},
+	}
+	objects := []client.Object{l7Portal, gateway, gwSecret, l7Api}
+	for _, name := range []string{"ssg-0", "ssg-1"} {
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: util.DefaultLabels("ssg", map[string]string{})},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				PodIP:             host,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "gateway", Ready: true}},
+			},
+		})
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithStatusSubresource(&v1alpha1.L7Portal{}, &v1.Gateway{}, &v1alpha1.L7Api{}).Build()
+	params := Params{
+		Client:   k8sClient,
+		Scheme:   scheme,
+		Log:      zap.New(zap.UseDevMode(true)),
+		Instance: l7Portal,
+	}
+	papi, err := newPapiClient(context.Background(), params, l7Portal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should enroll each gateway pod and report proxy status", func(t *testing.T) {
+		if err := syncProxies(context.Background(), params, papi, l7Portal); err != nil {
+			t.Fatal(err)
+		}
+		if portalServer.graphmanRequests != 2 {
+			t.Errorf("expected the enrollment bundle to be applied to 2 pods, actual %d", portalServer.graphmanRequests)
+		}
+
+		secret := &corev1.Secret{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "enroll-enrollment-bundle", Namespace: "default"}, secret); err != nil {
+			t.Fatal(err)
+		}
+		if string(secret.Data[portal.EnrollmentBundleKey]) != string(portalServer.enrollmentBundle) {
+			t.Errorf("expected the enrollment bundle to be stored in a secret")
+		}
+
+		current := &v1alpha1.L7Portal{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "enroll", Namespace: "default"}, current); err != nil {
+			t.Fatal(err)
+		}
+		if current.Status.EnrollmentBundle.SecretName != "enroll-enrollment-bundle" {
+			t.Errorf("expected enrollment bundle status to be set, actual %+v", current.Status.EnrollmentBundle)
+		}
+		if len(current.Status.GatewayProxies) != 1 || current.Status.GatewayProxies[0].Type != ProxyTypeEphemeral {
+			t.Fatalf("expected 1 ephemeral proxy, actual %+v", current.Status.GatewayProxies)
+		}
+		proxyGateways := current.Status.GatewayProxies[0].Gateways
+		if len(proxyGateways) != 2 || !proxyGateways[0].Synchronised || proxyGateways[1].Synchronised {
+			t.Errorf("expected only ssg-0 to be synchronised, actual %+v", proxyGateways)
+		}
+		if current.Status.Ready {
+			t.Errorf("expected the portal not to be ready until every gateway is synchronised")
+		}
+
+		if len(portalServer.proxies) != 1 || portalServer.proxies[0].Name != "ssg" || portalServer.proxies[0].ApiCount != 1 {
+			t.Errorf("expected the gateway to be registered as a proxy, actual %+v", portalServer.proxies)
+		}
+
+		gw := &v1.Gateway{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "ssg", Namespace: "default"}, gw); err != nil {
+			t.Fatal(err)
+		}
+		if gw.Status.PortalSyncStatus.Name != "enroll" || gw.Status.PortalSyncStatus.ApiCount != 1 {
+			t.Errorf("unexpected portal sync status %+v", gw.Status.PortalSyncStatus)
+		}
+	})
+
+	t.Run("should not reapply or reregister unchanged gateways", func(t *testing.T) {
+		current := &v1alpha1.L7Portal{}
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: "enroll", Namespace: "default"}, current); err != nil {
+			t.Fatal(err)
+		}
+		if err := syncProxies(context.Background(), params, papi, current); err != nil {
+			t.Fatal(err)
+		}
+		if portalServer.graphmanRequests != 2 {
+			t.Errorf("expected enrolled pods to be skipped, actual %d requests", portalServer.graphmanRequests)
+		}
+		if len(portalServer.proxies) != 1 {
+			t.Errorf("expected unchanged proxies not to be registered again, actual %d", len(portalServer.proxies))
+		}
+	})
+}
+
+func TestGetEnrollmentBundle(t *testing.T) {
+	bundle := `{"clusterProperties":[]}`
+	for _, enrollmentBundle := range []string{bundle, base64.StdEncoding.EncodeToString([]byte(bundle))} {
+		l7Portal := &v1alpha1.L7Portal{Spec: v1alpha1.L7PortalSpec{EnrollmentBundle: enrollmentBundle}}
+		actual, err := getEnrollmentBundle(l7Portal, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != bundle {
+			t.Errorf("expected %s, actual %s", bundle, string(actual))
+		}
+	}
+
+	actual, err := getEnrollmentBundle(&v1alpha1.L7Portal{}, nil)
+	if err != nil || actual != nil {
+		t.Errorf("expected no enrollment bundle without a portal endpoint")
+	}
+}
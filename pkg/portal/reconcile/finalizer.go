@@ -0,0 +1,87 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/caapim/layer7-operator/api/v1alpha1"
+	apireconcile "github.com/caapim/layer7-operator/pkg/api/reconcile"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const portalFinalizer = "security.brcmlabs.com/finalizer"
+
+// Finalize removes the L7Apis owned by an L7Portal that is being deleted. Each L7Api is removed from its gateways by the
+// L7Api controller, the removal is reported to the Portal and the L7Api finalizer is removed because the portal sync no longer runs.
+// done is false while L7Apis are still being removed
+func Finalize(ctx context.Context, params Params) (done bool, err error) {
+	l7Portal := params.Instance
+	if !controllerutil.ContainsFinalizer(l7Portal, portalFinalizer) {
+		return true, nil
+	}
+	_ = removeJob(l7Portal.Name + "-sync-portal")
+
+	l7ApiList := &v1alpha1.L7ApiList{}
+	err = params.Client.List(ctx, l7ApiList, client.InNamespace(l7Portal.Namespace))
+	if err != nil {
+		return false, err
+	}
+
+	var papi *papiClient
+	if l7Portal.Spec.Endpoint != "" {
+		papi, err = newPapiClient(ctx, params, l7Portal)
+		if err != nil {
+			params.Log.Info("failed to connect to portal, api removal will not be reported", "name", l7Portal.Name, "namespace", l7Portal.Namespace, "message", err.Error())
+		}
+	}
+
+	errs := []error{}
+	remaining := 0
+	for i, l7Api := range l7ApiList.Items {
+		if !metav1.IsControlledBy(&l7Api, l7Portal) {
+			continue
+		}
+		if l7Api.DeletionTimestamp == nil {
+			if err := params.Client.Delete(ctx, &l7ApiList.Items[i]); err != nil && !k8serrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to delete l7api %s: %w", l7Api.Name, err))
+			}
+			remaining++
+			continue
+		}
+		if !controllerutil.ContainsFinalizer(&l7Api, apiFinalizer) {
+			remaining++
+			continue
+		}
+		removed := l7Api.Annotations[apireconcile.L7API_REMOVED_ANNOTATION] == "true"
+		// l7apis that were never ready have not been deployed to a gateway
+		if !removed && l7Api.Status.Ready {
+			remaining++
+			continue
+		}
+		if removed && papi != nil {
+			err := reportDeploymentStatus(ctx, params, papi, l7Portal, &l7ApiList.Items[i])
+			if err == nil {
+				continue
+			}
+			params.Log.Info("failed to report api removal to portal", "name", l7Api.Name, "namespace", l7Api.Namespace, "message", err.Error())
+		}
+		controllerutil.RemoveFinalizer(&l7ApiList.Items[i], apiFinalizer)
+		if err := params.Client.Update(ctx, &l7ApiList.Items[i]); err != nil && !k8serrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to remove l7api %s finalizer: %w", l7Api.Name, err))
+		}
+	}
+	if len(errs) > 0 || remaining > 0 {
+		return false, errors.Join(errs...)
+	}
+
+	controllerutil.RemoveFinalizer(l7Portal, portalFinalizer)
+	if err := params.Client.Update(ctx, l7Portal); err != nil && !k8serrors.IsNotFound(err) {
+		return false, err
+	}
+	params.Log.Info("removed portal finalizer", "name", l7Portal.Name, "namespace", l7Portal.Namespace)
+	return true, nil
+}
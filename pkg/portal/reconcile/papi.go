@@ -0,0 +1,174 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/caapim/layer7-operator/api/v1alpha1"
+	"github.com/caapim/layer7-operator/internal/templategen"
+	"github.com/caapim/layer7-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// papiPageSize is the number of APIs that are requested from the Portal API (PAPI) at a time
+const papiPageSize = 100
+
+// Portal API (PAPI) paths relative to the tenant
+const (
+	papiApiManagementPath = "/api-management/1.0"
+	papiDeploymentsPath   = "/deployments/1.0"
+)
+
+// Deployment statuses that are reported to the Portal
+const (
+	PortalDeploymentDeployed   = "DEPLOYED"
+	PortalDeploymentUndeployed = "UNDEPLOYED"
+	PortalDeploymentError      = "ERROR"
+)
+
+// PortalProxyStatus registers a Gateway as a proxy of the Portal tenant
+type PortalProxyStatus struct {
+	Name     string                  `json:"name"`
+	Type     string                  `json:"type"`
+	ApiCount int                     `json:"apiCount"`
+	Gateways []v1alpha1.ProxyGateway `json:"gateways"`
+}
+
+// papiClient calls the Portal API (PAPI) of a single Portal tenant
+type papiClient struct {
+	baseUrl string
+	token   string
+}
+
+// PortalDeploymentStatus is reported to the Portal after an API has been deployed to or removed from the Gateways of an L7Portal
+type PortalDeploymentStatus struct {
+	Proxy    string `json:"proxy"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+func newPapiClient(ctx context.Context, params Params, l7Portal *v1alpha1.L7Portal) (*papiClient, error) {
+	clientId := l7Portal.Spec.Auth.PapiClientId
+	clientSecret := l7Portal.Spec.Auth.PapiClientSecret
+
+	if l7Portal.Spec.Auth.ExistingSecretName != "" {
+		secret := &corev1.Secret{}
+		err := params.Client.Get(ctx, types.NamespacedName{Name: l7Portal.Spec.Auth.ExistingSecretName, Namespace: l7Portal.Namespace}, secret)
+		if err != nil {
+			return nil, err
+		}
+		clientId = string(secret.Data["clientId"])
+		clientSecret = string(secret.Data["clientSecret"])
+	}
+
+	if clientId == "" || clientSecret == "" {
+		return nil, errors.New("portal clientId and clientSecret are required")
+	}
+
+	authEndpoint := l7Portal.Spec.Auth.Endpoint
+	if authEndpoint == "" {
+		authEndpoint = l7Portal.Spec.Endpoint
+	}
+
+	token, err := util.GetPortalAccessToken(l7Portal.Name, authEndpoint, clientId, clientSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve portal access token: %w", err)
+	}
+
+	return &papiClient{
+		baseUrl: "https://" + l7Portal.Spec.Endpoint + "/" + l7Portal.Spec.PortalTenant,
+		token:   token,
+	}, nil
+}
+
+func (c *papiClient) call(method string, path string, data []byte) ([]byte, error) {
+	return util.RestCall(method, c.baseUrl+path, true, map[string]string{"Authorization": "Bearer " + c.token}, "application/json", data, "", "")
+}
+
+// deployedApis returns each API that is deployed to the tenant with its policy templates, custom fields and secure passwords
+func (c *papiClient) deployedApis() ([]templategen.PortalAPI, error) {
+	portalAPIs := []templategen.PortalAPI{}
+	for page := 0; ; page++ {
+		resp, err := c.call("GET", fmt.Sprintf(papiApiManagementPath+"/apis?page=%d&size=%d", page, papiPageSize), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list portal apis: %w", err)
+		}
+		summary := RawPortalAPISummary{}
+		err = json.Unmarshal(resp, &summary)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, apiSummary := range summary.APIs {
+			portalAPI, err := c.api(apiSummary.Uuid)
+			if err != nil {
+				return nil, err
+			}
+			portalAPIs = append(portalAPIs, portalAPI)
+		}
+
+		if len(summary.APIs) < papiPageSize {
+			return portalAPIs, nil
+		}
+	}
+}
+
+func (c *papiClient) api(uuid string) (templategen.PortalAPI, error) {
+	portalAPI := templategen.PortalAPI{}
+	resp, err := c.call("GET", papiApiManagementPath+"/apis/"+url.PathEscape(uuid), nil)
+	if err != nil {
+		return portalAPI, fmt.Errorf("failed to retrieve portal api %s: %w", uuid, err)
+	}
+	err = json.Unmarshal(resp, &portalAPI)
+	if err != nil {
+		return portalAPI, err
+	}
+
+	resp, err = c.call("GET", papiApiManagementPath+"/apis/"+url.PathEscape(uuid)+"/secure-passwords", nil)
+	if err != nil {
+		return portalAPI, fmt.Errorf("failed to retrieve secure passwords for portal api %s: %w", uuid, err)
+	}
+	err = json.Unmarshal(resp, &portalAPI.SecurePasswords)
+	if err != nil {
+		return portalAPI, err
+	}
+	return portalAPI, nil
+}
+
+func (c *papiClient) reportDeployment(uuid string, status PortalDeploymentStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	_, err = c.call("PUT", papiApiManagementPath+"/apis/"+url.PathEscape(uuid)+"/deployment-status", data)
+	if err != nil {
+		return fmt.Errorf("failed to report deployment status of portal api %s: %w", uuid, err)
+	}
+	return nil
+}
+
+// enrollmentBundle returns the Graphman bundle that enrolls Gateways as proxies of the tenant
+func (c *papiClient) enrollmentBundle() ([]byte, error) {
+	resp, err := c.call("GET", papiDeploymentsPath+"/enrollment-bundle", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve portal enrollment bundle: %w", err)
+	}
+	return resp, nil
+}
+
+func (c *papiClient) registerProxy(proxy PortalProxyStatus) error {
+	data, err := json.Marshal(proxy)
+	if err != nil {
+		return err
+	}
+	_, err = c.call("PUT", papiDeploymentsPath+"/proxies/"+url.PathEscape(proxy.Name), data)
+	if err != nil {
+		return fmt.Errorf("failed to register proxy %s: %w", proxy.Name, err)
+	}
+	return nil
+}
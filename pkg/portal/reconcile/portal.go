@@ -37,6 +37,25 @@ func syncPortal(ctx context.Context, params Params) {
 		return
 	}
 
+	// portals that are bootstrapped by an L7Api do not have an endpoint, their APIs are created externally
+	var papi *papiClient
+	if l7Portal.Spec.Endpoint != "" {
+		papi, err = newPapiClient(ctx, params, l7Portal)
+		if err != nil {
+			params.Log.Info("failed to connect to portal", "name", l7Portal.Name, "namespace", l7Portal.Namespace, "message", err.Error())
+		} else {
+			err = syncPortalApis(ctx, params, papi, l7Portal)
+			if err != nil {
+				params.Log.Info("failed to sync portal apis", "name", l7Portal.Name, "namespace", l7Portal.Namespace, "message", err.Error())
+			}
+		}
+	}
+
+	err = syncProxies(ctx, params, papi, l7Portal)
+	if err != nil {
+		params.Log.Info("failed to sync portal proxies", "name", l7Portal.Name, "namespace", l7Portal.Namespace, "message", err.Error())
+	}
+
 	portalAPIs := []templategen.PortalAPI{}
 	portalTempDirectory := tempDirectoryBase + l7Portal.Name
 
@@ -0,0 +1,56 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/caapim/layer7-operator/pkg/portal"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// EnrollmentSecret stores the enrollment bundle in a Secret, updated is true if the Secret was created or changed
+func EnrollmentSecret(ctx context.Context, params Params, enrollmentBundle []byte) (updated bool, err error) {
+	desiredSecret := portal.NewEnrollmentSecret(params.Instance, enrollmentBundle)
+
+	updated, err = reconcileSecret(ctx, params, desiredSecret)
+	if err != nil {
+		return false, fmt.Errorf("failed to reconcile secrets: %w", err)
+	}
+
+	return updated, nil
+}
+
+func reconcileSecret(ctx context.Context, params Params, desiredSecret *corev1.Secret) (bool, error) {
+	if err := controllerutil.SetControllerReference(params.Instance, desiredSecret, params.Scheme); err != nil {
+		return false, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	currentSecret := corev1.Secret{}
+
+	err := params.Client.Get(ctx, types.NamespacedName{Name: desiredSecret.Name, Namespace: params.Instance.Namespace}, &currentSecret)
+	if err != nil && k8serrors.IsNotFound(err) {
+		if err = params.Client.Create(ctx, desiredSecret); err != nil {
+			return false, err
+		}
+		params.Log.Info("created secret", "name", desiredSecret.Name, "namespace", params.Instance.Namespace)
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if desiredSecret.ObjectMeta.Annotations["checksum/data"] != currentSecret.ObjectMeta.Annotations["checksum/data"] {
+		patch := client.MergeFrom(&currentSecret)
+		if err := params.Client.Patch(ctx, desiredSecret, patch); err != nil {
+			return false, err
+		}
+		params.Log.Info("secret updated", "name", desiredSecret.Name, "namespace", desiredSecret.Namespace)
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,37 @@
+package portal
+
+import (
+	"crypto/sha1"
+	"fmt"
+
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
+	"github.com/caapim/layer7-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnrollmentBundleKey is the Secret key that contains the Portal enrollment bundle
+const EnrollmentBundleKey = "enrollment.json"
+
+// NewEnrollmentSecret stores the Graphman bundle that enrolls Gateways with the Portal
+func NewEnrollmentSecret(portal *securityv1alpha1.L7Portal, enrollmentBundle []byte) *corev1.Secret {
+	h := sha1.New()
+	h.Write(enrollmentBundle)
+	dataCheckSum := fmt.Sprintf("%x", h.Sum(nil))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        portal.Name + "-enrollment-bundle",
+			Namespace:   portal.Namespace,
+			Labels:      util.DefaultLabels(portal.Name, map[string]string{}),
+			Annotations: map[string]string{"checksum/data": dataCheckSum},
+		},
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{EnrollmentBundleKey: enrollmentBundle},
+	}
+	return secret
+}
@@ -40,7 +40,8 @@ var syncCache = util.NewSyncCache(3 * time.Second)
 
 func ScheduledJobs(ctx context.Context, params Params) error {
 
-	registerJobs(ctx, params)
+	// jobs outlive this reconcile so they are not part of its trace
+	registerJobs(util.WithoutSpan(ctx), params)
 
 	for _, j := range s.Jobs() {
 		for _, t := range j.Tags() {
@@ -160,6 +160,7 @@ func removeFinalizer(ctx context.Context, params Params) error {
 		params.Log.V(2).Info("fail to remove finalizer", "name", params.Instance.Name, "namespace", params.Instance.Namespace, "message", err.Error())
 		return err
 	}
+	repositoryCommitInfo.Delete(params.Instance.Namespace + "/" + params.Instance.Name)
 
 	return nil
 }
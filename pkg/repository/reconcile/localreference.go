@@ -70,6 +70,9 @@ func LocalReference(ctx context.Context, params Params) error {
 		return nil
 	}
 
+	if repoStatus.Commit != commit || repoStatus.CommitTime == "" {
+		repoStatus.CommitTime = time.Now().Format(time.RFC3339)
+	}
 	repoStatus.Commit = commit
 	repoStatus.Name = repository.Name
 	repoStatus.Vendor = "kubernetes"
@@ -48,7 +48,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func syncRepository(ctx context.Context, params Params) error {
+func syncRepository(ctx context.Context, params Params) (err error) {
+	ctx, span := util.StartSpan(ctx, "syncRepository", attribute.String("namespace", params.Instance.Namespace), attribute.String("name", params.Instance.Name))
+	defer func() { util.EndSpan(span, err) }()
+
 	repository, err := getRepository(ctx, params)
 	var commit string
 	var username string
@@ -174,7 +177,13 @@ func syncRepository(ctx context.Context, params Params) error {
 			return nil
 		}
 	case "git":
+		_, cloneSpan := util.StartSpan(ctx, "CloneRepository", attribute.String("endpoint", repository.Spec.Endpoint))
 		commit, err = util.CloneRepository(repository.Spec.Endpoint, username, token, sshKey, sshKeyPass, repository.Spec.Branch, repository.Spec.Tag, repository.Spec.RemoteName, repository.Name, repository.Spec.Auth.Vendor, string(authType), knownHosts, repository.Namespace)
+		cloneSpan.SetAttributes(attribute.String("commit", commit))
+		if err != git.NoErrAlreadyUpToDate && err != git.ErrRemoteExists {
+			util.RecordSpanError(cloneSpan, err)
+		}
+		cloneSpan.End()
 		if err == git.NoErrAlreadyUpToDate || err == git.ErrRemoteExists {
 			params.Log.V(5).Info(err.Error(), "name", repository.Name, "namespace", repository.Namespace)
 
@@ -271,7 +280,14 @@ func syncRepository(ctx context.Context, params Params) error {
 		}
 	}
 
-	err = updateStatus(ctx, params, commit, storageSecretName, stateStoreSynced)
+	commitTime := time.Now()
+	if strings.ToLower(string(repository.Spec.Type)) == "git" {
+		if t, err := util.CommitTime(repository.Name, repository.Namespace, repository.Spec.Branch, repository.Spec.Tag, commit); err == nil {
+			commitTime = t
+		}
+	}
+
+	err = updateStatus(ctx, params, commit, commitTime, storageSecretName, stateStoreSynced)
 	if err != nil {
 		_ = captureRepositorySyncMetrics(ctx, params, start, commit, true)
 		params.Log.Info("failed to update repository status", "namespace", repository.Namespace, "name", repository.Name, "error", err.Error())
@@ -308,7 +324,7 @@ func getRepository(ctx context.Context, params Params) (securityv1.Repository, e
 	return repository, nil
 }
 
-func updateStatus(ctx context.Context, params Params, commit string, storageSecretName string, stateStoreSynced bool) (err error) {
+func updateStatus(ctx context.Context, params Params, commit string, commitTime time.Time, storageSecretName string, stateStoreSynced bool) (err error) {
 
 	if params.Instance.Status.StorageSecretName == "_" {
 		storageSecretName = "_"
@@ -317,6 +333,9 @@ func updateStatus(ctx context.Context, params Params, commit string, storageSecr
 	rs := params.Instance.Status
 	r := params.Instance
 
+	if rs.Commit != commit || rs.CommitTime == "" {
+		rs.CommitTime = commitTime.Format(time.RFC3339)
+	}
 	rs.Commit = commit
 	rs.Name = r.Name
 	rs.Vendor = r.Spec.Auth.Vendor
@@ -357,7 +376,10 @@ func setRepoReady(ctx context.Context, params Params, patch []byte) error {
 
 // BuildRepositoryCache scans a repository, builds bundles per directory, and caches them
 // Returns the bundleMap for reuse (e.g., in StorageSecret)
-func BuildRepositoryCache(ctx context.Context, params Params, commit string, storageSecretName string) (map[string][]byte, error) {
+func BuildRepositoryCache(ctx context.Context, params Params, commit string, storageSecretName string) (_ map[string][]byte, err error) {
+	_, span := util.StartSpan(ctx, "BuildRepositoryCache", attribute.String("commit", commit))
+	defer func() { util.EndSpan(span, err) }()
+
 	repository := params.Instance
 	cachePath := "/tmp/repo-cache/" + repository.Name
 	// fileName := commit + ".json"
@@ -595,6 +617,9 @@ func BuildRepositoryCache(ctx context.Context, params Params, commit string, sto
 	return bundleMap, nil
 }
 
+// repositoryCommitInfo reports the commit of the last successful sync of each Repository
+var repositoryCommitInfo = util.NewInfoGauge("layer7-operator-repository-sync-metrics", "operator_repository_commit_info", "commit of the last successful repository sync")
+
 func captureRepositorySyncMetrics(ctx context.Context, params Params, start time.Time, commitId string, hasError bool) error {
 	operatorNamespace, err := util.GetOperatorNamespace()
 	if err != nil {
@@ -673,8 +698,7 @@ func captureRepositorySyncMetrics(ctx context.Context, params Params, start time
 				attribute.String("k8s.namespace.name", operatorNamespace),
 				attribute.String("repository_namespace", gateway.Namespace),
 				attribute.String("repository_type", string(params.Instance.Spec.Type)),
-				attribute.String("repository_name", params.Instance.Name),
-				attribute.String("commit_id", commitId)))
+				attribute.String("repository_name", params.Instance.Name)))
 	} else {
 		repoSyncSuccess.Add(ctx, 1,
 			metric.WithAttributes(
@@ -682,9 +706,79 @@ func captureRepositorySyncMetrics(ctx context.Context, params Params, start time
 				attribute.String("k8s.namespace.name", operatorNamespace),
 				attribute.String("repository_namespace", gateway.Namespace),
 				attribute.String("repository_type", string(params.Instance.Spec.Type)),
-				attribute.String("repository_name", params.Instance.Name),
-				attribute.String("commit_id", commitId)))
+				attribute.String("repository_name", params.Instance.Name)))
+
+		lastSyncGauge, err := meter.Int64Gauge(otelMetricPrefix+"operator_repository_last_sync_timestamp",
+			metric.WithDescription("time of the last successful repository sync"), metric.WithUnit("s"))
+		if err != nil {
+			return err
+		}
+		lastSyncGauge.Record(ctx, time.Now().Unix(),
+			metric.WithAttributes(
+				attribute.String("k8s.pod.name", hostname),
+				attribute.String("k8s.namespace.name", operatorNamespace),
+				attribute.String("repository_namespace", gateway.Namespace),
+				attribute.String("repository_type", string(params.Instance.Spec.Type)),
+				attribute.String("repository_name", params.Instance.Name)))
+
+		// commits are reported as an info metric rather than a label on the sync counters
+		if err := repositoryCommitInfo.Set(gateway.Namespace+"/"+gateway.Name,
+			attribute.String("repository_namespace", gateway.Namespace),
+			attribute.String("repository_name", params.Instance.Name),
+			attribute.String("commit", commitId)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func captureRepositoryBundleSizeMetrics(ctx context.Context, params Params, storage string, size int) error {
+	operatorNamespace, err := util.GetOperatorNamespace()
+	if err != nil {
+		params.Log.Info("could not determine operator namespace")
+		return err
 	}
+	otelEnabled, err := util.GetOtelEnabled()
+	if err != nil {
+		params.Log.Info("could not determine if OTel is enabled")
+		return err
+	}
+
+	if !otelEnabled {
+		return nil
+	}
+
+	otelMetricPrefix, err := util.GetOtelMetricPrefix()
+	if err != nil {
+		params.Log.Info("could not determine otel metric prefix")
+		return err
+	}
+
+	if otelMetricPrefix == "" {
+		otelMetricPrefix = "layer7_"
+	}
+
+	hostname, err := util.GetHostname()
+	if err != nil {
+		params.Log.Error(err, "failed to retrieve operator hostname")
+		return err
+	}
+
+	meter := otel.Meter("layer7-operator-repository-sync-metrics")
+	bundleSizeGauge, err := meter.Int64Gauge(otelMetricPrefix+"operator_repository_bundle_size",
+		metric.WithDescription("size of the repository bundles in the storage secret or state store"), metric.WithUnit("By"))
+	if err != nil {
+		return err
+	}
+
+	bundleSizeGauge.Record(ctx, int64(size),
+		metric.WithAttributes(
+			attribute.String("k8s.pod.name", hostname),
+			attribute.String("k8s.namespace.name", operatorNamespace),
+			attribute.String("repository_namespace", params.Instance.Namespace),
+			attribute.String("repository_name", params.Instance.Name),
+			attribute.String("storage", storage)))
 
 	return nil
 }
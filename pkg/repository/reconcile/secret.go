@@ -226,6 +226,7 @@ func StorageSecretFromBundleMap(ctx context.Context, params Params, bundleMap ma
 	if err := reconcileSecret(ctx, params, desiredSecret); err != nil {
 		return fmt.Errorf("failed to reconcile secrets: %w", err)
 	}
+	_ = captureRepositoryBundleSizeMetrics(ctx, params, "secret", secretSize)
 
 	return nil
 }
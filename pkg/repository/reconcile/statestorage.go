@@ -42,11 +42,15 @@ import (
 	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
 	"github.com/caapim/layer7-operator/internal/graphman"
 	"github.com/caapim/layer7-operator/pkg/util"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
-func StateStorage(ctx context.Context, params Params, statestore securityv1alpha1.L7StateStore, commit string) error {
+func StateStorage(ctx context.Context, params Params, statestore securityv1alpha1.L7StateStore, commit string) (err error) {
+	ctx, span := util.StartSpan(ctx, "StateStorage", attribute.String("statestore", statestore.Name), attribute.String("commit", commit))
+	defer func() { util.EndSpan(span, err) }()
+
 	storageSecretName, repositoryPath, _, err := localRepoStorageInfo(params)
 	if err != nil {
 		return err
@@ -76,6 +80,15 @@ func StateStorage(ctx context.Context, params Params, statestore securityv1alpha
 		statestore.Spec.Redis.MasterPassword = string(stateStoreSecret.Data["masterPassword"])
 	}
 
+	if statestore.Spec.Redis.Tls.Enabled && statestore.Spec.Redis.Tls.ExistingSecret != "" {
+		tlsSecret, err := getStateStoreSecret(ctx, statestore.Spec.Redis.Tls.ExistingSecret, statestore, params)
+		if err != nil {
+			return err
+		}
+		statestore.Spec.Redis.Tls.ClientCrt = string(tlsSecret.Data[corev1.TLSCertKey])
+		statestore.Spec.Redis.Tls.ClientKey = string(tlsSecret.Data[corev1.TLSPrivateKeyKey])
+	}
+
 	rc, err := util.RedisClient(&statestore.Spec.Redis)
 	if err != nil {
 		return fmt.Errorf("failed to connect to state store: %w", err)
@@ -129,6 +142,7 @@ func StateStorage(ctx context.Context, params Params, statestore securityv1alpha
 		if rs.Err() != nil {
 			return fmt.Errorf("failed to reconcile state storage: %w", rs.Err())
 		}
+		_ = captureRepositoryBundleSizeMetrics(ctx, params, "statestore", len(compressedBundleBytes))
 
 		// then write that to file...
 		err = os.WriteFile(tmpPath+"/"+fileName, compressedBundleBytes, 0755)
@@ -277,6 +291,7 @@ func StateStorage(ctx context.Context, params Params, statestore securityv1alpha
 	if rs.Err() != nil {
 		return fmt.Errorf("failed to reconcile state storage: %w", rs.Err())
 	}
+	_ = captureRepositoryBundleSizeMetrics(ctx, params, "statestore", len(compressedBundleBytes))
 
 	err = os.WriteFile(tmpPath+"/"+fileName, compressedBundleBytes, 0755)
 	if err != nil {
@@ -305,6 +320,15 @@ func GetStateStoreChecksum(ctx context.Context, params Params, statestore securi
 		statestore.Spec.Redis.MasterPassword = string(stateStoreSecret.Data["masterPassword"])
 	}
 
+	if statestore.Spec.Redis.Tls.Enabled && statestore.Spec.Redis.Tls.ExistingSecret != "" {
+		tlsSecret, err := getStateStoreSecret(ctx, statestore.Spec.Redis.Tls.ExistingSecret, statestore, params)
+		if err != nil {
+			return "", err
+		}
+		statestore.Spec.Redis.Tls.ClientCrt = string(tlsSecret.Data[corev1.TLSCertKey])
+		statestore.Spec.Redis.Tls.ClientKey = string(tlsSecret.Data[corev1.TLSPrivateKeyKey])
+	}
+
 	rc, err := util.RedisClient(&statestore.Spec.Redis)
 	if err != nil {
 		return "", fmt.Errorf("failed to connect to state store: %w", err)
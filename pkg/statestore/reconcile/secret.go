@@ -31,6 +31,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	securityv1alpha1 "github.com/caapim/layer7-operator/api/v1alpha1"
 	"github.com/caapim/layer7-operator/pkg/statestore"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -51,12 +52,33 @@ func Secret(ctx context.Context, params Params) error {
 		desiredSecrets = append(desiredSecrets, statestore.NewSecret(params.Instance, data, params.Instance.Name+"-secret"))
 	}
 
+	// read-only credentials are mounted into Gateway initContainers
+	if params.Instance.Spec.Redis.ReadOnly.ExistingSecret == "" && params.Instance.Spec.Redis.ReadOnly.Username != "" {
+		data := map[string][]byte{}
+		data["username"] = []byte(params.Instance.Spec.Redis.ReadOnly.Username)
+		data["masterPassword"] = []byte(params.Instance.Spec.Redis.ReadOnly.MasterPassword)
+		desiredSecrets = append(desiredSecrets, statestore.NewSecret(params.Instance, data, params.Instance.Name+"-readonly-secret"))
+	}
+
+	// plaintext client certificates are stored in a kubernetes.io/tls secret for Gateway initContainers
+	if params.Instance.Spec.Redis.Tls.Enabled && params.Instance.Spec.Redis.Tls.ExistingSecret == "" && params.Instance.Spec.Redis.Tls.ClientCrt != "" {
+		data := map[string][]byte{}
+		data[corev1.TLSCertKey] = []byte(params.Instance.Spec.Redis.Tls.ClientCrt)
+		data[corev1.TLSPrivateKeyKey] = []byte(params.Instance.Spec.Redis.Tls.ClientKey)
+		tlsSecret := statestore.NewSecret(params.Instance, data, params.Instance.Name+"-tls-secret")
+		tlsSecret.Type = corev1.SecretTypeTLS
+		desiredSecrets = append(desiredSecrets, tlsSecret)
+	}
+
 	data := map[string][]byte{}
 
 	redisConf := params.Instance.Spec.Redis
 
 	redisConf.Username = ""
 	redisConf.MasterPassword = ""
+	redisConf.ReadOnly = securityv1alpha1.RedisReadOnlyCredentials{}
+	redisConf.Tls.ClientCrt = ""
+	redisConf.Tls.ClientKey = ""
 
 	confBytes, err := json.Marshal(redisConf)
 	if err != nil {
@@ -57,6 +57,22 @@ func RedisStateStore(ctx context.Context, params Params) error {
 		statestore.Spec.Redis.MasterPassword = string(stateStoreSecret.Data["masterPassword"])
 	}
 
+	// the client certificate secret is read on every reconcile so that rotated certificates are picked up
+	if statestore.Spec.Redis.Tls.Enabled && statestore.Spec.Redis.Tls.ExistingSecret != "" {
+		tlsSecret, err := getStateStoreSecret(ctx, statestore.Spec.Redis.Tls.ExistingSecret, *statestore, params)
+		if err != nil {
+			params.Log.V(2).Info("failed to retrieve client certificate secret", "name", statestore.Name, "namespace", statestore.Namespace, "message", err.Error())
+			status.Ready = false
+			statusErr := updateStatus(ctx, params, status)
+			if statusErr != nil {
+				return statusErr
+			}
+			return err
+		}
+		statestore.Spec.Redis.Tls.ClientCrt = string(tlsSecret.Data[corev1.TLSCertKey])
+		statestore.Spec.Redis.Tls.ClientKey = string(tlsSecret.Data[corev1.TLSPrivateKeyKey])
+	}
+
 	c, err := util.RedisClient(&statestore.Spec.Redis)
 	if err != nil {
 		params.Recorder.Eventf(statestore, "Warning", "ConnectionFailed", "%s in namespace %s", statestore.Name, statestore.Namespace)
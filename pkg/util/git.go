@@ -26,117 +26,114 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 )
 
-func CloneRepository(url string, username string, token string, privateKey []byte, privateKeyPass string, branch string, tag string, remoteName string, name string, vendor string, authType string, knownHosts []byte, namespace string) (string, error) {
-	if remoteName == "" {
-		remoteName = "origin"
-	}
-
-	cloneOpts := git.CloneOptions{
-		URL:        url,
-		RemoteName: remoteName,
-	}
-
-	pullOpts := git.PullOptions{
-		RemoteName: remoteName,
-	}
+// gitRemote is the url, authentication and TLS configuration used to reach a git remote
+type gitRemote struct {
+	url             string
+	auth            transport.AuthMethod
+	insecureSkipTLS bool
+}
 
+// newGitRemote resolves the vendor and auth type of a repository, ssh known hosts are added to /tmp/known_hosts
+func newGitRemote(url string, username string, token string, privateKey []byte, privateKeyPass string, vendor string, authType string, knownHosts []byte) (gitRemote, error) {
+	remote := gitRemote{url: url}
 	if !strings.HasSuffix(url, ".git") {
-		cloneOpts.URL = url + ".git"
+		remote.url = url + ".git"
 	}
 
 	if strings.ToLower(vendor) == "azure" {
 		transport.UnsupportedCapabilities = []capability.Capability{
 			capability.ThinPack,
 		}
-		cloneOpts.URL = url
+		remote.url = url
 	}
 
 	if strings.Contains(strings.ToLower(vendor), "insecure") {
-		cloneOpts.InsecureSkipTLS = true
-		pullOpts.InsecureSkipTLS = true
-	}
-
-	if tag != "" {
-		cloneOpts.ReferenceName = plumbing.ReferenceName(tag)
-		pullOpts.ReferenceName = plumbing.ReferenceName("refs/heads/" + tag)
-	}
-
-	// this supercedes tag if set.
-	if branch != "" {
-		cloneOpts.ReferenceName = plumbing.ReferenceName(branch)
-		pullOpts.ReferenceName = plumbing.ReferenceName("refs/heads/" + branch)
+		remote.insecureSkipTLS = true
 	}
 
 	switch strings.ToLower(authType) {
 	case "ssh":
 		if strings.Contains(url, "https") {
-			return "", fmt.Errorf("auth type %s is not valid for %s please use username,token instead", authType, url)
+			return remote, fmt.Errorf("auth type %s is not valid for %s please use username,token instead", authType, url)
 		}
 		publicKeys, err := ssh.NewPublicKeys("git", privateKey, privateKeyPass)
 		if err != nil {
-			return "", err
+			return remote, err
 		}
-		cloneOpts.Auth = publicKeys
-		pullOpts.Auth = publicKeys
+		remote.auth = publicKeys
 
 		if os.Getenv("SSH_KNOWN_HOSTS") != "/tmp/known_hosts" {
 			os.Setenv("SSH_KNOWN_HOSTS", "/tmp/known_hosts")
 		}
-		var newKnownHosts string
-		currentKnownHosts, err := os.ReadFile("/tmp/known_hosts")
-		if err != nil {
-			err = os.WriteFile("/tmp/known_hosts", knownHosts, 0644)
-			if err != nil {
-				return "", err
-			}
-		} else {
-			if len(currentKnownHosts) == 0 {
-				newKnownHosts = string(knownHosts)
-			}
-			for _, c := range strings.Split(string(currentKnownHosts), "\n") {
-				if !strings.Contains(newKnownHosts, c) {
-					if newKnownHosts == "" {
-						newKnownHosts = c
-					} else {
-						newKnownHosts = newKnownHosts + "\n" + c
-					}
-
-					for _, n := range strings.Split(string(knownHosts), "\n") {
-						if !strings.Contains(newKnownHosts, n) {
-							if newKnownHosts == "" {
-								newKnownHosts = n
-							} else {
-								newKnownHosts = newKnownHosts + "\n" + n
-							}
-						}
-					}
-				}
-			}
-
-			err = os.WriteFile("/tmp/known_hosts", []byte(newKnownHosts), 0644)
-			if err != nil {
-				return "", err
+		currentKnownHosts, _ := os.ReadFile("/tmp/known_hosts")
+		newKnownHosts := string(currentKnownHosts)
+		for _, n := range strings.Split(string(knownHosts), "\n") {
+			if n != "" && !strings.Contains(newKnownHosts, n) {
+				newKnownHosts = strings.TrimSuffix(newKnownHosts, "\n") + "\n" + n
 			}
 		}
-
+		err = os.WriteFile("/tmp/known_hosts", []byte(strings.TrimPrefix(newKnownHosts, "\n")), 0644)
+		if err != nil {
+			return remote, err
+		}
 	case "basic":
 		if username != "" && token != "" {
-			cloneOpts.Auth = &http.BasicAuth{Username: username, Password: token}
-			pullOpts.Auth = &http.BasicAuth{Username: username, Password: token}
+			remote.auth = &http.BasicAuth{Username: username, Password: token}
 		}
 	}
+	return remote, nil
+}
+
+func CloneRepository(url string, username string, token string, privateKey []byte, privateKeyPass string, branch string, tag string, remoteName string, name string, vendor string, authType string, knownHosts []byte, namespace string) (string, error) {
+	if remoteName == "" {
+		remoteName = "origin"
+	}
+
+	remote, err := newGitRemote(url, username, token, privateKey, privateKeyPass, vendor, authType, knownHosts)
+	if err != nil {
+		return "", err
+	}
+
+	cloneOpts := git.CloneOptions{
+		URL:             remote.url,
+		RemoteName:      remoteName,
+		Auth:            remote.auth,
+		InsecureSkipTLS: remote.insecureSkipTLS,
+	}
+
+	pullOpts := git.PullOptions{
+		RemoteName:      remoteName,
+		Auth:            remote.auth,
+		InsecureSkipTLS: remote.insecureSkipTLS,
+	}
+
+	if tag != "" {
+		cloneOpts.ReferenceName = plumbing.ReferenceName(tag)
+		pullOpts.ReferenceName = plumbing.ReferenceName("refs/heads/" + tag)
+	}
+
+	// this supercedes tag if set.
+	if branch != "" {
+		cloneOpts.ReferenceName = plumbing.ReferenceName(branch)
+		pullOpts.ReferenceName = plumbing.ReferenceName("refs/heads/" + branch)
+	}
 
 	ext := cloneOpts.ReferenceName.String()
 
@@ -194,3 +191,114 @@ func CloneRepository(url string, username string, token string, privateKey []byt
 
 	return commit.Hash.String(), nil
 }
+
+// CommitTime returns the committer time of commit in a repository cloned by CloneRepository
+func CommitTime(name string, namespace string, branch string, tag string, commit string) (time.Time, error) {
+	ext := tag
+	if branch != "" {
+		ext = branch
+	}
+	r, err := git.PlainOpen("/tmp/" + name + "-" + namespace + "-" + ext)
+	if err != nil {
+		return time.Time{}, err
+	}
+	c, err := r.CommitObject(plumbing.NewHash(commit))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.Committer.When, nil
+}
+
+// PushRepository clones branch into a working directory, calls write with the directory that should be updated
+// and pushes a commit if anything changed. The branch is created from the default branch if it does not exist
+func PushRepository(url string, username string, token string, privateKey []byte, privateKeyPass string, branch string, remoteName string, name string, vendor string, authType string, knownHosts []byte, namespace string, path string, message string, author object.Signature, write func(dir string) error) (string, error) {
+	if remoteName == "" {
+		remoteName = "origin"
+	}
+
+	remote, err := newGitRemote(url, username, token, privateKey, privateKeyPass, vendor, authType, knownHosts)
+	if err != nil {
+		return "", err
+	}
+
+	cloneOpts := git.CloneOptions{
+		URL:             remote.url,
+		RemoteName:      remoteName,
+		Auth:            remote.auth,
+		InsecureSkipTLS: remote.insecureSkipTLS,
+	}
+	pushOpts := git.PushOptions{
+		RemoteName:      remoteName,
+		RefSpecs:        []config.RefSpec{config.RefSpec("refs/heads/" + branch + ":refs/heads/" + branch)},
+		Auth:            remote.auth,
+		InsecureSkipTLS: remote.insecureSkipTLS,
+	}
+
+	// exports always start from the latest state of the remote branch
+	dir := "/tmp/" + name + "-" + namespace + "-export-" + branch
+	err = os.RemoveAll(dir)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(branch)
+	cloneOpts.SingleBranch = true
+	r, err := git.PlainClone(dir, false, &cloneOpts)
+	if errors.Is(err, git.NoMatchingRefSpecError{}) {
+		_ = os.RemoveAll(dir)
+		cloneOpts.ReferenceName = ""
+		r, err = git.PlainClone(dir, false, &cloneOpts)
+		if err == nil {
+			w, wErr := r.Worktree()
+			if wErr != nil {
+				return "", wErr
+			}
+			err = w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branch), Create: true})
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	err = write(filepath.Join(dir, path))
+	if err != nil {
+		return "", err
+	}
+
+	err = w.AddWithOptions(&git.AddOptions{All: true})
+	if err != nil {
+		return "", err
+	}
+
+	status, err := w.Status()
+	if err != nil {
+		return "", err
+	}
+
+	if status.IsClean() {
+		ref, err := r.Head()
+		if err != nil {
+			return "", err
+		}
+		return ref.Hash().String(), nil
+	}
+
+	author.When = time.Now()
+	hash, err := w.Commit(message, &git.CommitOptions{Author: &author})
+	if err != nil {
+		return "", err
+	}
+
+	err = r.Push(&pushOpts)
+	if err != nil {
+		return "", err
+	}
+
+	return hash.String(), nil
+}
@@ -0,0 +1,88 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func remoteHead(t *testing.T, remote string, branch string) string {
+	r, err := git.PlainOpen(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := r.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref.Hash().String()
+}
+
+func TestPushRepository(t *testing.T) {
+	remote := NewTestGitRemote(t)
+	author := object.Signature{Name: "layer7-operator", Email: "layer7-operator@localhost"}
+	write := func(content string) func(dir string) error {
+		return func(dir string) error {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(dir, "clusterProperties.json"), []byte(content), 0644)
+		}
+	}
+
+	t.Run("should commit and push changes", func(t *testing.T) {
+		commit, err := PushRepository(remote, "", "", nil, "", "main", "", "push-test", "", "none", nil, "default", "exports/ssg", "export ssg", author, write("one"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if commit == "" || remoteHead(t, remote, "main") != commit {
+			t.Errorf("expected %s to be pushed to main", commit)
+		}
+	})
+
+	t.Run("should not commit when nothing changed", func(t *testing.T) {
+		head := remoteHead(t, remote, "main")
+		commit, err := PushRepository(remote, "", "", nil, "", "main", "", "push-test", "", "none", nil, "default", "exports/ssg", "export ssg", author, write("one"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if commit != head || remoteHead(t, remote, "main") != head {
+			t.Errorf("expected the head of main %s to be returned, actual %s", head, commit)
+		}
+	})
+
+	t.Run("should create a branch that does not exist", func(t *testing.T) {
+		commit, err := PushRepository(remote, "", "", nil, "", "exports", "", "push-test", "", "none", nil, "default", "", "export ssg", author, write("two"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if remoteHead(t, remote, "exports") != commit {
+			t.Errorf("expected %s to be pushed to a new branch", commit)
+		}
+	})
+}
+
+func TestCommitTime(t *testing.T) {
+	remote := NewTestGitRemote(t)
+	t.Cleanup(func() { os.RemoveAll("/tmp/commit-time-test-default-main") })
+
+	commit, err := CloneRepository(remote, "", "", nil, "", "main", "", "", "commit-time-test", "", "none", nil, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitTime, err := CommitTime("commit-time-test", "default", "main", "", commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(commitTime) > time.Minute {
+		t.Errorf("expected the time of the initial commit, actual %s", commitTime)
+	}
+	if _, err := CommitTime("commit-time-test", "default", "main", "", "0000000000000000000000000000000000000000"); err == nil {
+		t.Errorf("expected an error for a commit that does not exist")
+	}
+}
@@ -29,6 +29,7 @@ package util
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha1"
@@ -88,59 +89,97 @@ type GraphmanOtkConfig struct {
 	InternalGatewayReference string `json:"internalGatewayReference,omitempty"`
 }
 
-func ApplyToGraphmanTarget(bundleBytes []byte, singleton bool, username string, password string, target string, encpass string, delete bool) error {
-	bundle := graphman.Bundle{}
+func ApplyToGraphmanTarget(ctx context.Context, bundleBytes []byte, singleton bool, username string, password string, target string, encpass string, delete bool) error {
 	var err error
 
 	if !singleton {
-		scheduledTasks := []*graphman.ScheduledTaskInput{}
-		jmsListeners := []*graphman.JmsDestinationInput{}
-
-		err := json.Unmarshal(bundleBytes, &bundle)
+		bundleBytes, err = removeSingletonEntities(bundleBytes)
 		if err != nil {
 			return err
 		}
+	}
 
-		for _, st := range bundle.ScheduledTasks {
-			if !st.ExecuteOnSingleNode {
-				scheduledTasks = append(scheduledTasks, st)
-			}
-		}
-
-		bundle.ScheduledTasks = scheduledTasks
-
-		for _, jmsl := range bundle.JmsDestinations {
-			if jmsl.Direction != "OUTBOUND" {
-				jmsListeners = append(jmsListeners, jmsl)
-			}
-		}
-		bundle.JmsDestinations = jmsListeners
-
-		bundleBytes, err = json.Marshal(bundle)
+	if !delete {
+		_, err = graphman.ApplyDynamicBundle(ctx, username, password, "https://"+target, encpass, bundleBytes)
 		if err != nil {
 			return err
 		}
-	}
-
-	if !delete {
-		_, err = graphman.ApplyDynamicBundle(username, password, "https://"+target, encpass, bundleBytes)
+		// Graphman can report success for entities that were not persisted
+		missing, err := graphman.MissingEntities(ctx, username, password, "https://"+target, bundleBytes)
 		if err != nil {
 			return err
 		}
+		if len(missing) > 0 {
+			return fmt.Errorf("applied entities do not exist on the gateway: %s", strings.Join(missing, ", "))
+		}
 	} else {
-		_, err = graphman.DeleteDynamicBundle(username, password, "https://"+target, encpass, bundleBytes)
+		_, err = graphman.DeleteDynamicBundle(ctx, username, password, "https://"+target, encpass, bundleBytes)
 		if err != nil {
 			return err
 		}
 	}
 
-	// _, err = graphman.ApplyDynamicBundle(username, password, "https://"+target, encpass, bundleBytes)
+	// _, err = graphman.ApplyDynamicBundle(ctx, username, password, "https://"+target, encpass, bundleBytes)
 	// if err != nil {
 	// 	return err
 	// }
 	return nil
 }
 
+// DetectGraphmanDrift reports entities in bundleBytes that are missing or modified on the target
+// singleton entities are only expected on the target if singleton is true
+func DetectGraphmanDrift(ctx context.Context, bundleBytes []byte, singleton bool, username string, password string, target string) ([]graphman.DriftedEntity, error) {
+	if !singleton {
+		var err error
+		bundleBytes, err = removeSingletonEntities(bundleBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return graphman.DetectDrift(ctx, username, password, "https://"+target, bundleBytes)
+}
+
+// removeSingletonEntities drops scheduled tasks that run on a single node and outbound jms destinations
+// these are only applied to the leader when singleton extraction is enabled
+func removeSingletonEntities(bundleBytes []byte) ([]byte, error) {
+	bundle := graphman.Bundle{}
+	scheduledTasks := []*graphman.ScheduledTaskInput{}
+	jmsListeners := []*graphman.JmsDestinationInput{}
+
+	err := json.Unmarshal(bundleBytes, &bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, st := range bundle.ScheduledTasks {
+		if !st.ExecuteOnSingleNode {
+			scheduledTasks = append(scheduledTasks, st)
+		}
+	}
+	bundle.ScheduledTasks = scheduledTasks
+
+	for _, jmsl := range bundle.JmsDestinations {
+		if jmsl.Direction != "OUTBOUND" {
+			jmsListeners = append(jmsListeners, jmsl)
+		}
+	}
+	bundle.JmsDestinations = jmsListeners
+
+	return json.Marshal(bundle)
+}
+
+// ExportGraphmanTarget explodes the configuration of the target into path
+// secrets are only exported if encpass is set
+func ExportGraphmanTarget(ctx context.Context, username string, password string, target string, encpass string, path string) (int, error) {
+	return graphman.ExportToPath(ctx, username, password, "https://"+target, encpass, path)
+}
+
+// GraphmanServerInfo retrieves the cluster name and nodes of the target
+func GraphmanServerInfo(ctx context.Context, username string, password string, target string) (*graphman.ClusterInfo, error) {
+	return graphman.ServerInfo(ctx, username, password, "https://"+target)
+}
+
 func ConvertX509ToGraphmanBundle(keys []GraphmanKey, notFound []string) ([]byte, error) {
 	bundle := graphman.Bundle{}
 
@@ -335,8 +374,8 @@ func ConvertOpaqueMapToGraphmanBundle(secrets []GraphmanSecret, notFound []strin
 	return bundleBytes, nil
 }
 
-func ApplyGraphmanBundle(username string, password string, target string, encpass string, bundle []byte) error {
-	_, err := graphman.ApplyDynamicBundle(username, password, "https://"+target, encpass, bundle)
+func ApplyGraphmanBundle(ctx context.Context, username string, password string, target string, encpass string, bundle []byte) error {
+	_, err := graphman.ApplyDynamicBundle(ctx, username, password, "https://"+target, encpass, bundle)
 
 	if err != nil {
 		return err
@@ -1,6 +1,10 @@
 package util
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
@@ -38,3 +42,29 @@ func TestConvertOpaqueMapToGraphmanBundle(t *testing.T) {
 		t.Errorf("bundle %s should contain key %s", bundle, "test")
 	}
 }*/
+
+func TestApplyToGraphmanTarget(t *testing.T) {
+	// the gateway accepts the bundle but only knows the cluster property named found
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(string(body), "clusterPropertyByName") {
+			_, _ = w.Write([]byte(`{"data":{"e0":{"goid":"1","name":"found"},"e1":null}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+	target := strings.TrimPrefix(server.URL, "https://") + "/graphman"
+
+	bundle := []byte(`{"clusterProperties":[{"name":"found","value":"a"},{"name":"lost","value":"b"}]}`)
+	err := ApplyToGraphmanTarget(context.Background(), bundle, true, "admin", "password", target, "", false)
+	if err == nil || !strings.Contains(err.Error(), "applied entities do not exist on the gateway: clusterProperties/lost") {
+		t.Fatalf("expected the missing cluster property to be reported, actual %v", err)
+	}
+
+	bundle = []byte(`{"clusterProperties":[{"name":"found","value":"a"}]}`)
+	if err := ApplyToGraphmanTarget(context.Background(), bundle, true, "admin", "password", target, "", false); err != nil {
+		t.Fatal(err)
+	}
+}
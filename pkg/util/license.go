@@ -0,0 +1,94 @@
+/*
+* Copyright (c) 2025 Broadcom. All rights reserved.
+* The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+* All trademarks, trade names, service marks, and logos referenced
+* herein belong to their respective companies.
+*
+* This software and all information contained therein is confidential
+* and proprietary and shall not be duplicated, used, disclosed or
+* disseminated in any way except as authorized by the applicable
+* license agreement, without the express written permission of Broadcom.
+* All authorized reproductions must be marked with this language.
+*
+* EXCEPT AS SET FORTH IN THE APPLICABLE LICENSE AGREEMENT, TO THE
+* EXTENT PERMITTED BY APPLICABLE LAW OR AS AGREED BY BROADCOM IN ITS
+* APPLICABLE LICENSE AGREEMENT, BROADCOM PROVIDES THIS DOCUMENTATION
+* "AS IS" WITHOUT WARRANTY OF ANY KIND, INCLUDING WITHOUT LIMITATION,
+* ANY IMPLIED WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+* PURPOSE, OR. NONINFRINGEMENT. IN NO EVENT WILL BROADCOM BE LIABLE TO
+* THE END USER OR ANY THIRD PARTY FOR ANY LOSS OR DAMAGE, DIRECT OR
+* INDIRECT, FROM THE USE OF THIS DOCUMENTATION, INCLUDING WITHOUT LIMITATION,
+* LOST PROFITS, LOST INVESTMENT, BUSINESS INTERRUPTION, GOODWILL, OR
+* LOST DATA, EVEN IF BROADCOM IS EXPRESSLY ADVISED IN ADVANCE OF THE
+* POSSIBILITY OF SUCH LOSS OR DAMAGE.
+*
+ */
+package util
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GatewayLicense contains the details of a Gateway license.xml
+type GatewayLicense struct {
+	Id           string
+	Product      string
+	MajorVersion string
+	Licensee     string
+	FeatureSets  []string
+	// Expires is zero if the license does not expire
+	Expires time.Time
+}
+
+type licenseXml struct {
+	XMLName xml.Name `xml:"license"`
+	Id      string   `xml:"Id,attr"`
+	Expires string   `xml:"expires"`
+	Product struct {
+		Name    string `xml:"name,attr"`
+		Version struct {
+			Major string `xml:"major,attr"`
+		} `xml:"version"`
+		FeatureSets []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"featureset"`
+	} `xml:"product"`
+	Licensee struct {
+		Name string `xml:"name,attr"`
+	} `xml:"licensee"`
+}
+
+// ParseGatewayLicense reads the product, version, licensee, feature sets and expiry date from a Gateway license.xml
+func ParseGatewayLicense(licenseBytes []byte) (GatewayLicense, error) {
+	l := licenseXml{}
+	err := xml.Unmarshal(licenseBytes, &l)
+	if err != nil {
+		return GatewayLicense{}, fmt.Errorf("failed to parse license: %w", err)
+	}
+
+	license := GatewayLicense{
+		Id:           l.Id,
+		Product:      l.Product.Name,
+		MajorVersion: l.Product.Version.Major,
+		Licensee:     l.Licensee.Name,
+	}
+	for _, fs := range l.Product.FeatureSets {
+		license.FeatureSets = append(license.FeatureSets, fs.Name)
+	}
+
+	expires := strings.TrimSpace(l.Expires)
+	if expires != "" {
+		license.Expires, err = time.Parse(time.RFC3339, expires)
+		if err != nil {
+			license.Expires, err = time.Parse("2006-01-02", expires)
+			if err != nil {
+				return GatewayLicense{}, fmt.Errorf("invalid license expiry date %s", expires)
+			}
+		}
+	}
+
+	return license, nil
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+const testLicense = `<?xml version="1.0" encoding="UTF-8"?>
+<license Id="5774270471352355000" xmlns="http://l7tech.com/license">
+    <description>Layer7 API Gateway</description>
+    <licenseAttributes/>
+    <valid>2024-01-01T00:00:00.000Z</valid>
+    <expires>2026-12-31T23:59:59.000Z</expires>
+    <host name="*"/>
+    <ip address="*"/>
+    <product name="Layer 7 SecureSpan Suite">
+        <version major="11" minor="*"/>
+        <featureset name="set:Profile:EnterpriseGateway"/>
+        <featureset name="set:Profile:Gateway"/>
+    </product>
+    <licensee contactEmail="admin@example.com" name="Example Corp"/>
+</license>`
+
+func TestParseGatewayLicense(t *testing.T) {
+	license, err := ParseGatewayLicense([]byte(testLicense))
+	if err != nil {
+		t.Fatalf("failed to parse license: %v", err)
+	}
+
+	if license.Id != "5774270471352355000" || license.Product != "Layer 7 SecureSpan Suite" || license.Licensee != "Example Corp" {
+		t.Errorf("unexpected license details %+v", license)
+	}
+	if license.MajorVersion != "11" {
+		t.Errorf("expected major version 11, actual %s", license.MajorVersion)
+	}
+	if len(license.FeatureSets) != 2 || license.FeatureSets[0] != "set:Profile:EnterpriseGateway" {
+		t.Errorf("unexpected feature sets %v", license.FeatureSets)
+	}
+	if !license.Expires.Equal(time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)) {
+		t.Errorf("unexpected expiry date %s", license.Expires)
+	}
+
+	_, err = ParseGatewayLicense([]byte("not a license"))
+	if err == nil {
+		t.Errorf("invalid license should be rejected")
+	}
+}
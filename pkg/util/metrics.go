@@ -0,0 +1,124 @@
+package util
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Gauge is an observable gauge that reports the latest value for each key
+// unlike synchronous gauges, keys can be deleted so that removed resources stop being reported
+type Gauge struct {
+	meterName   string
+	name        string
+	description string
+	unit        string
+	mu          sync.Mutex
+	values      map[string]gaugeValue
+	registered  bool
+}
+
+type gaugeValue struct {
+	value      int64
+	attributes attribute.Set
+}
+
+// NewGauge returns a Gauge, the OTel metric prefix is added when the gauge is first set
+func NewGauge(meterName string, name string, description string, unit string) *Gauge {
+	return &Gauge{
+		meterName:   meterName,
+		name:        name,
+		description: description,
+		unit:        unit,
+		values:      map[string]gaugeValue{},
+	}
+}
+
+// Set replaces the value and attributes that are reported for key
+func (g *Gauge) Set(key string, value int64, attributes ...attribute.KeyValue) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[key] = gaugeValue{value: value, attributes: attribute.NewSet(attributes...)}
+	if g.registered {
+		return nil
+	}
+
+	otelMetricPrefix, err := GetOtelMetricPrefix()
+	if err != nil {
+		return err
+	}
+	if otelMetricPrefix == "" {
+		otelMetricPrefix = "layer7_"
+	}
+
+	options := []metric.Int64ObservableGaugeOption{metric.WithDescription(g.description), metric.WithInt64Callback(g.observe)}
+	if g.unit != "" {
+		options = append(options, metric.WithUnit(g.unit))
+	}
+	_, err = otel.Meter(g.meterName).Int64ObservableGauge(otelMetricPrefix+g.name, options...)
+	if err != nil {
+		return err
+	}
+	g.registered = true
+	return nil
+}
+
+// Delete stops reporting key
+func (g *Gauge) Delete(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.values, key)
+}
+
+// DeletePrefix stops reporting every key that starts with prefix
+func (g *Gauge) DeletePrefix(prefix string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for key := range g.values {
+		if strings.HasPrefix(key, prefix) {
+			delete(g.values, key)
+		}
+	}
+}
+
+func (g *Gauge) observe(_ context.Context, o metric.Int64Observer) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, v := range g.values {
+		o.Observe(v.value, metric.WithAttributeSet(v.attributes))
+	}
+	return nil
+}
+
+// InfoGauge is a Gauge with a value of 1 that only reports the latest attributes for each key
+// high cardinality values like commit hashes are exported this way so that previous values are not retained
+type InfoGauge struct {
+	*Gauge
+}
+
+// NewInfoGauge returns an InfoGauge, the OTel metric prefix is added when the gauge is first set
+func NewInfoGauge(meterName string, name string, description string) *InfoGauge {
+	return &InfoGauge{Gauge: NewGauge(meterName, name, description, "")}
+}
+
+// Set replaces the attributes that are reported for key
+func (g *InfoGauge) Set(key string, attributes ...attribute.KeyValue) error {
+	return g.Gauge.Set(key, 1, attributes...)
+}
+
+// ParseStatusTime parses timestamps written to custom resource status
+// older status fields were written with time.Time.String() rather than RFC3339
+func ParseStatusTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+	// strip the monotonic clock reading
+	value, _, _ = strings.Cut(value, " m=")
+	return time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", value)
+}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestInfoGauge(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Setenv(OtelMetricPrefixEnvVar, "test_")
+
+	commitInfo := NewInfoGauge("test", "commit_info", "test commit info")
+	if err := commitInfo.Set("default/repo", attribute.String("repository_name", "repo"), attribute.String("commit", "commit1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitInfo.Set("default/repo", attribute.String("repository_name", "repo"), attribute.String("commit", "commit2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitInfo.Set("default/other", attribute.String("repository_name", "other"), attribute.String("commit", "commit1")); err != nil {
+		t.Fatal(err)
+	}
+
+	collect := func() []metricdata.DataPoint[int64] {
+		rm := metricdata.ResourceMetrics{}
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatal(err)
+		}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name == "test_commit_info" {
+					return m.Data.(metricdata.Gauge[int64]).DataPoints
+				}
+			}
+		}
+		return nil
+	}
+
+	dataPoints := collect()
+	if len(dataPoints) != 2 {
+		t.Fatalf("expected %d data points, actual %d", 2, len(dataPoints))
+	}
+	for _, dp := range dataPoints {
+		name, _ := dp.Attributes.Value("repository_name")
+		commit, _ := dp.Attributes.Value("commit")
+		if name.AsString() == "repo" && commit.AsString() != "commit2" {
+			t.Errorf("expected only the latest commit to be reported, actual %s", commit.AsString())
+		}
+	}
+
+	commitInfo.Delete("default/other")
+	if len(collect()) != 1 {
+		t.Errorf("expected deleted keys to no longer be reported")
+	}
+}
+
+func TestGauge(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Setenv(OtelMetricPrefixEnvVar, "test_")
+
+	podsBehind := NewGauge("test", "pods_behind", "test pods behind", "")
+	for key, value := range map[string]int64{"default/ssg/repo": 2, "default/ssg/other": 1, "default/ssg-two/repo": 3} {
+		if err := podsBehind.Set(key, value, attribute.String("key", key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := podsBehind.Set("default/ssg/repo", 0, attribute.String("key", "default/ssg/repo")); err != nil {
+		t.Fatal(err)
+	}
+
+	collect := func() map[string]int64 {
+		rm := metricdata.ResourceMetrics{}
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatal(err)
+		}
+		values := map[string]int64{}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name == "test_pods_behind" {
+					for _, dp := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+						key, _ := dp.Attributes.Value("key")
+						values[key.AsString()] = dp.Value
+					}
+				}
+			}
+		}
+		return values
+	}
+
+	values := collect()
+	if len(values) != 3 || values["default/ssg/repo"] != 0 {
+		t.Fatalf("expected the latest value of each key, actual %v", values)
+	}
+
+	podsBehind.DeletePrefix("default/ssg/")
+	values = collect()
+	if len(values) != 1 || values["default/ssg-two/repo"] != 3 {
+		t.Errorf("expected only keys with the prefix to be deleted, actual %v", values)
+	}
+}
+
+func TestParseStatusTime(t *testing.T) {
+	now := time.Now()
+	for _, value := range []string{now.Format(time.RFC3339), now.String(), now.UTC().String()} {
+		parsed, err := ParseStatusTime(value)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", value, err)
+		}
+		if parsed.Unix() != now.Unix() {
+			t.Errorf("expected %d, actual %d", now.Unix(), parsed.Unix())
+		}
+	}
+	if _, err := ParseStatusTime("invalid"); err == nil {
+		t.Errorf("invalid timestamps should be rejected")
+	}
+}
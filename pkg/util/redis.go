@@ -38,7 +38,9 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func RedisClient(c *v1alpha1.Redis) (rdb *redis.Client, err error) {
+// RedisClient returns a client for the configured Redis type. A UniversalClient is
+// returned so that standalone, sentinel and cluster deployments can be used interchangeably.
+func RedisClient(c *v1alpha1.Redis) (rdb redis.UniversalClient, err error) {
 	username := ""
 	if c.Username != "" {
 		username = c.Username
@@ -59,7 +61,23 @@ func RedisClient(c *v1alpha1.Redis) (rdb *redis.Client, err error) {
 	tlsConfig := tls.Config{}
 
 	if c.Tls.Enabled {
-		tlsConfig.MinVersion = tls.VersionTLS12
+		tlsConfig.MinVersion, err = redisTlsVersion(c.Tls.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.Tls.ServerName != "" {
+			tlsConfig.ServerName = c.Tls.ServerName
+		}
+
+		if c.Tls.ClientCrt != "" || c.Tls.ClientKey != "" {
+			clientCrt, err := tls.X509KeyPair([]byte(c.Tls.ClientCrt), []byte(c.Tls.ClientKey))
+			if err != nil {
+				return nil, fmt.Errorf("invalid redis client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{clientCrt}
+		}
+
 		localCrts := []x509.Certificate{}
 
 		tlsConfig.InsecureSkipVerify = false
@@ -69,7 +87,6 @@ func RedisClient(c *v1alpha1.Redis) (rdb *redis.Client, err error) {
 		}
 
 		if c.Tls.VerifyPeer && c.Tls.RedisCrt != "" {
-			certValidations := 0
 			tlsConfig.InsecureSkipVerify = true
 			if strings.Contains(string(c.Tls.RedisCrt), "-----BEGIN CERTIFICATE-----") {
 				crtStrings := strings.SplitAfter(string(c.Tls.RedisCrt), "-----END CERTIFICATE-----")
@@ -85,6 +102,7 @@ func RedisClient(c *v1alpha1.Redis) (rdb *redis.Client, err error) {
 
 				tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
 					// validate local certs against server certs
+					certValidations := 0
 					for _, localCert := range localCrts {
 						for _, peerCert := range cs.PeerCertificates {
 							if bytes.Equal(localCert.Raw, peerCert.Raw) {
@@ -135,6 +153,38 @@ func RedisClient(c *v1alpha1.Redis) (rdb *redis.Client, err error) {
 		}
 
 		return rdb, nil
+
+	case string(v1alpha1.RedisTypeCluster):
+		if len(c.Cluster.Nodes) == 0 {
+			return nil, fmt.Errorf("redis cluster requires at least one seed node")
+		}
+		clusterAddrs := []string{}
+		for _, clusterAddr := range c.Cluster.Nodes {
+			clusterAddrs = append(clusterAddrs, clusterAddr.Host+":"+strconv.Itoa(clusterAddr.Port))
+		}
+
+		// Redis Cluster only supports database 0
+		rdb := redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    clusterAddrs,
+			Username: username,
+			Password: password,
+		})
+
+		if c.Tls.Enabled {
+			rdb.Options().TLSConfig = &tlsConfig
+		}
+
+		return rdb, nil
+	}
+	return nil, fmt.Errorf("%s is not a supported redis type", c.Type)
+}
+
+func redisTlsVersion(v string) (uint16, error) {
+	switch v {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
 	}
-	return nil, err
+	return 0, fmt.Errorf("%s is not a supported minimum tls version, valid options are 1.2 and 1.3", v)
 }
@@ -0,0 +1,121 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/caapim/layer7-operator/api/v1alpha1"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisClusterClient(t *testing.T) {
+	c := v1alpha1.Redis{
+		Type: v1alpha1.RedisTypeCluster,
+		Cluster: v1alpha1.RedisCluster{
+			Nodes: []v1alpha1.RedisClusterNode{
+				{Host: "redis-cluster-0", Port: 6379},
+				{Host: "redis-cluster-1", Port: 6379},
+			},
+		},
+	}
+
+	rc, err := RedisClient(&c)
+	if err != nil {
+		t.Fatalf("failed to create redis cluster client: %v", err)
+	}
+	defer rc.Close()
+
+	cc, ok := rc.(*redis.ClusterClient)
+	if !ok {
+		t.Fatalf("expected *redis.ClusterClient, actual %T", rc)
+	}
+
+	if len(cc.Options().Addrs) != 2 || cc.Options().Addrs[0] != "redis-cluster-0:6379" {
+		t.Errorf("unexpected cluster seed nodes %v", cc.Options().Addrs)
+	}
+
+	c.Cluster.Nodes = nil
+	_, err = RedisClient(&c)
+	if err == nil {
+		t.Errorf("redis cluster without seed nodes should be rejected")
+	}
+}
+
+func TestRedisClientMutualTls(t *testing.T) {
+	crt, key := testClientCertificate(t)
+	c := v1alpha1.Redis{
+		Type:       v1alpha1.RedisTypeStandalone,
+		Standalone: v1alpha1.RedisStandalone{Host: "localhost", Port: 6379},
+		Tls: v1alpha1.RedisTls{
+			Enabled:    true,
+			VerifyPeer: true,
+			ClientCrt:  crt,
+			ClientKey:  key,
+			ServerName: "redis.example.com",
+			MinVersion: "1.3",
+		},
+	}
+
+	rc, err := RedisClient(&c)
+	if err != nil {
+		t.Fatalf("failed to create redis client: %v", err)
+	}
+	defer rc.Close()
+
+	tlsConfig := rc.(*redis.Client).Options().TLSConfig
+	if len(tlsConfig.Certificates) != 1 {
+		t.Errorf("expected client certificate to be set")
+	}
+	if tlsConfig.ServerName != "redis.example.com" {
+		t.Errorf("expected serverName redis.example.com, actual %s", tlsConfig.ServerName)
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("expected minimum tls version 1.3, actual %x", tlsConfig.MinVersion)
+	}
+
+	c.Tls.MinVersion = "1.1"
+	_, err = RedisClient(&c)
+	if err == nil {
+		t.Errorf("tls version 1.1 should be rejected")
+	}
+
+	c.Tls.MinVersion = ""
+	c.Tls.ClientKey = "invalid"
+	_, err = RedisClient(&c)
+	if err == nil {
+		t.Errorf("invalid client key should be rejected")
+	}
+}
+
+func testClientCertificate(t *testing.T) (string, string) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "layer7-operator"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crt := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	key := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return string(crt), string(key)
+}
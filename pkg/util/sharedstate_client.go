@@ -23,6 +23,10 @@ type RedisSsl struct {
 	Enabled    bool   `yaml:"enabled"`
 	Crt        string `yaml:"cert,omitempty"`
 	VerifyPeer *bool  `yaml:"verifyPeer,omitempty"`
+	ClientCert string `yaml:"clientCert,omitempty"`
+	ClientKey  string `yaml:"clientKey,omitempty"`
+	ServerName string `yaml:"serverName,omitempty"`
+	MinVersion string `yaml:"minVersion,omitempty"`
 }
 
 type RedisSentinel struct {
@@ -30,6 +34,10 @@ type RedisSentinel struct {
 	Nodes  []RedisNode `yaml:"nodes,omitempty"`
 }
 
+type RedisCluster struct {
+	Nodes []RedisNode `yaml:"nodes,omitempty"`
+}
+
 type RedisClientConfig struct {
 	Name               string        `yaml:"name,omitempty"`
 	Type               string        `yaml:"type,omitempty"`
@@ -42,6 +50,7 @@ type RedisClientConfig struct {
 	Password           string        `yaml:"password,omitempty"`
 	Standalone         RedisNode     `yaml:"standalone,omitempty"`
 	Sentinel           RedisSentinel `yaml:"sentinel,omitempty"`
+	Cluster            RedisCluster  `yaml:"cluster,omitempty"`
 	Ssl                RedisSsl      `yaml:"ssl"`
 }
 
@@ -139,6 +139,57 @@ func TestSingleSentinelSharedStateClientConfiguration(t *testing.T) {
 	}
 }
 
+func TestSingleClusterSharedStateClientConfiguration(t *testing.T) {
+	rcc := []RedisClientConfig{
+		{
+			Name:               defaultName,
+			Type:               "cluster",
+			KeyPrefixGroupName: redisGroupName,
+			CommandTimeout:     commandTimeout,
+			ConnectTimeout:     connectTimeout,
+			Password:           "7layer",
+			Cluster: RedisCluster{
+				Nodes: []RedisNode{
+					{
+						Host: "redis-cluster-0.redis-cluster-headless.develop.svc.cluster.local",
+						Port: 6379,
+					},
+					{
+						Host: "redis-cluster-1.redis-cluster-headless.develop.svc.cluster.local",
+						Port: 6379,
+					},
+				},
+			},
+		},
+	}
+
+	configBytes, err := GenerateSharedStateClientConfig("redis", rcc, nil)
+	if err != nil {
+		t.Errorf("failed to generate sharedstate_client config for single cluster configuration test err: %v", err)
+	}
+
+	expected := `redis:
+  default:
+    type: cluster
+    commandTimeout: 5000
+    connectTimeout: 10000
+    keyPrefixGroupName: l7GW
+    testOnStart: false
+    password: 7layer
+    cluster:
+      nodes:
+        - host: redis-cluster-0.redis-cluster-headless.develop.svc.cluster.local
+          port: 6379
+        - host: redis-cluster-1.redis-cluster-headless.develop.svc.cluster.local
+          port: 6379
+    ssl:
+      enabled: false`
+
+	if !reflect.DeepEqual(strings.TrimSpace(string(configBytes)), strings.TrimSpace(expected)) {
+		t.Errorf("actual \n%v, expected \n%v", string(configBytes), expected)
+	}
+}
+
 func TestAdditionalProviderSharedStateClientConfiguration(t *testing.T) {
 	rcc := []RedisClientConfig{
 		{
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// NewTestGitRemote creates a bare repository with a single commit on branch main for tests that clone or push
+func NewTestGitRemote(t testing.TB) string {
+	t.Helper()
+	main := git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")}
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	if _, err := git.PlainInitWithOptions(remote, &git.PlainInitOptions{Bare: true, InitOptions: main}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	r, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{InitOptions: main})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("gateway configuration"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, _ := r.Worktree()
+	if _, err := w.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Commit("initial commit", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@localhost", When: time.Now()}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remote}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Push(&git.PushOptions{RemoteName: "origin"}); err != nil {
+		t.Fatal(err)
+	}
+	return remote
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "layer7-operator"
+
+// StartSpan starts a span that is a child of any span in ctx
+// spans are no-ops unless OTel is enabled
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// RecordSpanError marks the span as failed if err is set
+func RecordSpanError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// EndSpan records err on the span if it is set and ends the span
+func EndSpan(span trace.Span, err error) {
+	RecordSpanError(span, err)
+	span.End()
+}
+
+// WithoutSpan returns ctx without its span so that scheduled jobs which outlive a reconcile start new traces
+func WithoutSpan(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(ctx, trace.SpanContext{})
+}
@@ -2,17 +2,23 @@ package util
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -34,6 +40,28 @@ func DefaultLabels(name string, additionalLabels map[string]string) map[string]s
 	return labels
 }
 
+// DefaultLabelsName returns the name that a set of labels was created for by DefaultLabels
+func DefaultLabelsName(labels map[string]string) (string, bool) {
+	name := labels["app.kubernetes.io/name"]
+	if name == "" {
+		return "", false
+	}
+	for k, v := range DefaultLabels(name, map[string]string{}) {
+		if labels[k] != v {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+// PodIP returns a pod IP that can be used as the host of an address, IPv6 addresses are enclosed in brackets
+func PodIP(podIp string) string {
+	if ip := net.ParseIP(podIp); ip != nil && strings.Contains(podIp, ":") {
+		return "[" + podIp + "]"
+	}
+	return podIp
+}
+
 // Contains returns true if string array contains string
 func Contains(arr []string, str string) bool {
 	for _, a := range arr {
@@ -52,6 +80,8 @@ var (
 	OtelCollectorUrlEnvVar  = "OTEL_EXPORTER_OTLP_ENDPOINT"
 	OtelMetricPrefixEnvVar  = "OTEL_METRIC_PREFIX"
 	HostNameEnvVar          = "HOSTNAME"
+	// MaxConcurrentPodUpdatesEnvVar is the operator wide default for Gateway.Spec.App.MaxConcurrentPodUpdates
+	MaxConcurrentPodUpdatesEnvVar = "MAX_CONCURRENT_POD_UPDATES"
 )
 
 // GetWatchNamespace returns the namespace the operator should be watching for changes
@@ -134,6 +164,47 @@ func GetOtelMetricPrefix() (string, error) {
 	return otelMetricPrefix, nil
 }
 
+// GetMaxConcurrentPodUpdates returns the number of Gateway pods that bundles are applied to in parallel
+// 0 is returned if it has not been set
+func GetMaxConcurrentPodUpdates() (int, error) {
+	m, found := os.LookupEnv(MaxConcurrentPodUpdatesEnvVar)
+	if !found || m == "" {
+		return 0, nil
+	}
+	maxConcurrentPodUpdates, err := strconv.Atoi(m)
+	if err != nil {
+		return 0, err
+	}
+	return maxConcurrentPodUpdates, nil
+}
+
+// RunConcurrently calls fn for 0..n-1 with at most limit calls in flight
+// every call is made and the errors are joined
+func RunConcurrently(limit int, n int, fn func(i int) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	errs := []error{}
+	sem := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(i); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
 func InitOTelProvider(collectorURL string, ctx context.Context) (func(context.Context) error, error) {
 
 	hostname, err := os.Hostname()
@@ -174,6 +245,19 @@ func InitOTelProvider(collectorURL string, ctx context.Context) (func(context.Co
 
 	otel.SetMeterProvider(meterProvider)
 
+	// Set up a trace exporter, trace context is propagated to Gateways in Graphman request headers
+	traceExporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn), otlptracegrpc.WithCompressor("gzip"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithResource(res), sdktrace.WithBatcher(traceExporter))
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
 	// Shutdown will flush any remaining spans and shut down the exporter.
-	return meterProvider.Shutdown, nil
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
 }
@@ -1,8 +1,12 @@
 package util
 
 import (
+	"errors"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestGetWatchNamespace(t *testing.T) {
@@ -48,3 +52,79 @@ func TestDefaultLabels(t *testing.T) {
 	}
 
 }
+
+func TestDefaultLabelsName(t *testing.T) {
+	if name, ok := DefaultLabelsName(DefaultLabels("ssg", map[string]string{"app": "ssg"})); !ok || name != "ssg" {
+		t.Errorf("expected ssg, actual %q", name)
+	}
+	if _, ok := DefaultLabelsName(map[string]string{"app.kubernetes.io/name": "ssg"}); ok {
+		t.Errorf("labels that were not created by DefaultLabels should not match")
+	}
+	if _, ok := DefaultLabelsName(nil); ok {
+		t.Errorf("empty labels should not match")
+	}
+}
+
+func TestPodIP(t *testing.T) {
+	for podIp, expected := range map[string]string{
+		"10.0.0.1":   "10.0.0.1",
+		"fd00::1":    "[fd00::1]",
+		"::ffff:1.2": "::ffff:1.2",
+		"":           "",
+	} {
+		if actual := PodIP(podIp); actual != expected {
+			t.Errorf("actual %s, expected %s", actual, expected)
+		}
+	}
+}
+
+func TestGetMaxConcurrentPodUpdates(t *testing.T) {
+	os.Setenv(MaxConcurrentPodUpdatesEnvVar, "8")
+	defer os.Unsetenv(MaxConcurrentPodUpdatesEnvVar)
+	actual, err := GetMaxConcurrentPodUpdates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != 8 {
+		t.Errorf("actual %d, expected %d", actual, 8)
+	}
+
+	os.Setenv(MaxConcurrentPodUpdatesEnvVar, "eight")
+	_, err = GetMaxConcurrentPodUpdates()
+	if err == nil {
+		t.Errorf("invalid %s should be rejected", MaxConcurrentPodUpdatesEnvVar)
+	}
+}
+
+func TestRunConcurrently(t *testing.T) {
+	var inFlight, maxInFlight, calls atomic.Int32
+	err := RunConcurrently(3, 20, func(i int) error {
+		calls.Add(1)
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if current <= m || maxInFlight.CompareAndSwap(m, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if i%5 == 0 {
+			return errors.New("pod-" + string(rune('a'+i)))
+		}
+		return nil
+	})
+
+	if calls.Load() != 20 {
+		t.Errorf("expected %d calls, actual %d", 20, calls.Load())
+	}
+	if maxInFlight.Load() > 3 {
+		t.Errorf("expected at most %d calls in flight, actual %d", 3, maxInFlight.Load())
+	}
+	if err == nil {
+		t.Fatal("expected errors to be returned")
+	}
+	if len(strings.Split(err.Error(), "\n")) != 4 {
+		t.Errorf("expected 4 errors, actual %s", err.Error())
+	}
+}